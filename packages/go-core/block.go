@@ -0,0 +1,90 @@
+package core
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxCityBlockHeight is the largest block height accepted in a City Protocol
+// block identifier. It is far beyond any real Bitcoin height and exists to
+// reject absurd values before they reach storage or arithmetic.
+const MaxCityBlockHeight = 10_000_000
+
+// CityBlockID is a parsed City Protocol block identifier:
+// org.cityprotocol:block:<height>:<hash>
+type CityBlockID struct {
+	Height int64
+	Hash   string
+}
+
+// NewCityBlockID builds a block identifier and validates its height and hash.
+func NewCityBlockID(height int64, hash string) (CityBlockID, error) {
+	id := CityBlockID{Height: height, Hash: hash}
+	if err := id.Validate(); err != nil {
+		return CityBlockID{}, err
+	}
+	return id, nil
+}
+
+// ParseCityBlockID parses org.cityprotocol:block:<height>:<hash>.
+func ParseCityBlockID(value string) (CityBlockID, error) {
+	if !strings.HasPrefix(value, CityBlockIDPrefix) {
+		return CityBlockID{}, fmt.Errorf("block id must start with '%s'", CityBlockIDPrefix)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, CityBlockIDPrefix), ":")
+	if len(parts) != 2 {
+		return CityBlockID{}, fmt.Errorf("block id format invalid: expected '%s<height>:<hash>', got '%s'", CityBlockIDPrefix, value)
+	}
+
+	height, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return CityBlockID{}, fmt.Errorf("block id height must be numeric: %s", parts[0])
+	}
+
+	return NewCityBlockID(height, parts[1])
+}
+
+// Validate checks the height bounds and that the hash is a 64-character hex string.
+func (b CityBlockID) Validate() error {
+	if b.Height < 0 || b.Height > MaxCityBlockHeight {
+		return fmt.Errorf("block height %d out of range [0, %d]", b.Height, MaxCityBlockHeight)
+	}
+	if len(b.Hash) != 64 {
+		return fmt.Errorf("block hash must be 64 hex characters, got %d", len(b.Hash))
+	}
+	if _, err := hex.DecodeString(b.Hash); err != nil {
+		return fmt.Errorf("block hash must be hex: %s", b.Hash)
+	}
+	return nil
+}
+
+// String returns the identifier as org.cityprotocol:block:<height>:<hash>.
+func (b CityBlockID) String() string {
+	return fmt.Sprintf("%s%d:%s", CityBlockIDPrefix, b.Height, b.Hash)
+}
+
+// DTag returns the block event d tag.
+func (b CityBlockID) DTag() DTag {
+	return NewDTag(KindCityBlock, fmt.Sprintf("%d:%s", b.Height, b.Hash))
+}
+
+// Coordinate returns the block event coordinate for the given clock pubkey.
+func (b CityBlockID) Coordinate(clock_pubkey string) Coordinate {
+	return NewCoordinate(KindCityBlock, clock_pubkey, b.DTag())
+}
+
+// NewCityBlockReference builds the ref_clock_pubkey/ref_block_id pair used in event content.
+func NewCityBlockReference(clock_pubkey string, block_id CityBlockID) CityBlockReference {
+	return CityBlockReference{
+		RefClockPubkey: clock_pubkey,
+		RefBlockID:     block_id.String(),
+	}
+}
+
+// BlockID parses the referenced block identifier.
+func (r CityBlockReference) BlockID() (CityBlockID, error) {
+	return ParseCityBlockID(r.RefBlockID)
+}
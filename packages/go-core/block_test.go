@@ -0,0 +1,64 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+var testBlockHash = strings.Repeat("0a", 32)
+
+func TestParseCityBlockID(t *testing.T) {
+	id, err := ParseCityBlockID("org.cityprotocol:block:870500:" + testBlockHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id.Height != 870500 || id.Hash != testBlockHash {
+		t.Errorf("unexpected block id: %+v", id)
+	}
+	if id.String() != "org.cityprotocol:block:870500:"+testBlockHash {
+		t.Errorf("unexpected string: %s", id.String())
+	}
+}
+
+func TestParseCityBlockIDInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"org.attnprotocol:block:870500:" + testBlockHash,
+		"org.cityprotocol:block:870500",
+		"org.cityprotocol:block:abc:" + testBlockHash,
+		"org.cityprotocol:block:-1:" + testBlockHash,
+		"org.cityprotocol:block:99999999999:" + testBlockHash,
+		"org.cityprotocol:block:870500:00000000000000000001a7c",
+		"org.cityprotocol:block:870500:" + strings.Repeat("zz", 32),
+		"org.cityprotocol:block:870500:" + testBlockHash + ":extra",
+	}
+	for _, value := range invalid {
+		if _, err := ParseCityBlockID(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
+
+func TestCityBlockIDBuilders(t *testing.T) {
+	id, err := NewCityBlockID(870500, testBlockHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	coordinate := id.Coordinate(testPubkey)
+	if coordinate.String() != "38808:"+testPubkey+":org.cityprotocol:block:870500:"+testBlockHash {
+		t.Errorf("unexpected coordinate: %s", coordinate.String())
+	}
+	if err := coordinate.Validate(); err != nil {
+		t.Errorf("expected valid coordinate, got %v", err)
+	}
+
+	reference := NewCityBlockReference(testPubkey, id)
+	parsed, err := reference.BlockID()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed != id {
+		t.Errorf("expected %+v, got %+v", id, parsed)
+	}
+}
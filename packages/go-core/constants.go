@@ -42,6 +42,16 @@ const (
 	KindCityBlock = 38808
 )
 
+// NIP-51 list kinds that can carry ATTN Protocol preference lists.
+// ATTN-01 publishes lists as follow sets; bookmark sets are accepted when parsing.
+const (
+	// KindFollowSet is the NIP-51 follow set kind used for ATTN Protocol lists (30000).
+	KindFollowSet = 30000
+
+	// KindBookmarkSet is the NIP-51 bookmark set kind (30003).
+	KindBookmarkSet = 30003
+)
+
 // NIP-51 list type identifiers for ATTN Protocol.
 // Used for user preference lists (blocked promotions, trusted marketplaces, etc.)
 const (
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Namespaces used as the first segment of protocol d tags.
+const (
+	// NamespaceATTN is the d tag namespace for ATTN Protocol events.
+	NamespaceATTN = "org.attnprotocol"
+
+	// NamespaceCity is the d tag namespace for City Protocol events.
+	NamespaceCity = "org.cityprotocol"
+)
+
+// eventTypes maps event kinds to the event type segment of their d tag.
+var eventTypes = map[int]string{
+	KindMarketplace:                  "marketplace",
+	KindBillboard:                    "billboard",
+	KindPromotion:                    "promotion",
+	KindAttention:                    "attention",
+	KindBillboardConfirmation:        "billboard-confirmation",
+	KindAttentionConfirmation:        "attention-confirmation",
+	KindMarketplaceConfirmation:      "marketplace-confirmation",
+	KindMatch:                        "match",
+	KindAttentionPaymentConfirmation: "attention-payment-confirmation",
+	KindCityBlock:                    "block",
+}
+
+// EventTypeForKind returns the d tag event type for a protocol kind
+// (e.g. "promotion" for 38388) and false if the kind has none.
+func EventTypeForKind(kind int) (string, bool) {
+	event_type, ok := eventTypes[kind]
+	return event_type, ok
+}
+
+// DTag is a protocol d tag: <namespace>:<event_type>:<identifier>.
+// The identifier may itself contain colons.
+type DTag struct {
+	Namespace  string
+	EventType  string
+	Identifier string
+}
+
+// NewDTag builds the d tag for a protocol kind and identifier.
+// City Protocol block kinds use the City namespace; all others use ATTN.
+func NewDTag(kind int, identifier string) DTag {
+	namespace := NamespaceATTN
+	if kind == KindCityBlock {
+		namespace = NamespaceCity
+	}
+	event_type, _ := EventTypeForKind(kind)
+	return DTag{Namespace: namespace, EventType: event_type, Identifier: identifier}
+}
+
+// ParseDTag parses a d tag of the form <namespace>:<event_type>:<identifier>.
+func ParseDTag(value string) (DTag, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) < 3 {
+		return DTag{}, fmt.Errorf("d tag format invalid: expected <namespace>:<event_type>:<identifier>, got '%s'", value)
+	}
+
+	d_tag := DTag{Namespace: parts[0], EventType: parts[1], Identifier: parts[2]}
+	if d_tag.Namespace == "" {
+		return DTag{}, fmt.Errorf("d tag namespace is empty")
+	}
+	if d_tag.EventType == "" {
+		return DTag{}, fmt.Errorf("d tag event type is empty")
+	}
+	if d_tag.Identifier == "" {
+		return DTag{}, fmt.Errorf("d tag identifier is empty")
+	}
+
+	return d_tag, nil
+}
+
+// String returns the d tag in <namespace>:<event_type>:<identifier> form.
+func (d DTag) String() string {
+	return d.Namespace + ":" + d.EventType + ":" + d.Identifier
+}
+
+// Validate checks that the d tag uses the namespace and event type required for kind.
+// ATTN Protocol events use org.attnprotocol:<event_type>:<identifier>.
+// City Protocol block events use org.cityprotocol:block:<height>:<hash>.
+func (d DTag) Validate(kind int) error {
+	expected_type, ok := EventTypeForKind(kind)
+	if !ok {
+		return fmt.Errorf("unknown event kind: %d", kind)
+	}
+
+	expected_namespace := NamespaceATTN
+	if kind == KindCityBlock {
+		expected_namespace = NamespaceCity
+	}
+
+	if d.Namespace != expected_namespace {
+		return fmt.Errorf("d tag must start with '%s:'", expected_namespace)
+	}
+	if d.EventType != expected_type {
+		return fmt.Errorf("d tag event type mismatch: expected '%s', got '%s'", expected_type, d.EventType)
+	}
+	if d.Identifier == "" {
+		return fmt.Errorf("d tag identifier is empty")
+	}
+
+	return nil
+}
+
+// Coordinate is an addressable event coordinate: <kind>:<pubkey>:<d_tag>.
+type Coordinate struct {
+	Kind   int
+	Pubkey string
+	DTag   string
+}
+
+// NewCoordinate builds the coordinate for a protocol event.
+func NewCoordinate(kind int, pubkey string, d_tag DTag) Coordinate {
+	return Coordinate{Kind: kind, Pubkey: pubkey, DTag: d_tag.String()}
+}
+
+// ParseCoordinate parses a coordinate of the form <kind>:<pubkey>:<d_tag>.
+// It checks the structure only; use Validate for protocol rules.
+func ParseCoordinate(value string) (Coordinate, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) < 3 {
+		return Coordinate{}, fmt.Errorf("coordinate format invalid: expected kind:pubkey:identifier")
+	}
+
+	kind, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Coordinate{}, fmt.Errorf("coordinate kind must be numeric: %s", parts[0])
+	}
+
+	return Coordinate{Kind: kind, Pubkey: parts[1], DTag: parts[2]}, nil
+}
+
+// String returns the coordinate in <kind>:<pubkey>:<d_tag> form.
+func (c Coordinate) String() string {
+	return fmt.Sprintf("%d:%s:%s", c.Kind, c.Pubkey, c.DTag)
+}
+
+// ParsedDTag parses the coordinate's d tag as a protocol d tag.
+func (c Coordinate) ParsedDTag() (DTag, error) {
+	return ParseDTag(c.DTag)
+}
+
+// IsProtocol returns true if the coordinate points at an ATTN Protocol or
+// City Protocol block event, whose d tags must be namespaced.
+func (c Coordinate) IsProtocol() bool {
+	return IsATTNKind(c.Kind) || c.Kind == KindCityBlock
+}
+
+// Validate checks the coordinate against protocol rules.
+// Protocol coordinates must carry a namespaced d tag matching their kind;
+// non-protocol coordinates (e.g. video kind 34236) only need a d tag and
+// must not use the ATTN namespace.
+func (c Coordinate) Validate() error {
+	if c.Pubkey == "" {
+		return fmt.Errorf("coordinate pubkey is empty")
+	}
+	if c.DTag == "" {
+		return fmt.Errorf("coordinate identifier is empty")
+	}
+
+	if !c.IsProtocol() {
+		if strings.HasPrefix(c.DTag, NamespaceATTN+":") {
+			return fmt.Errorf("coordinate for kind %d should not include '%s:' prefix", c.Kind, NamespaceATTN)
+		}
+		return nil
+	}
+
+	d_tag, err := c.ParsedDTag()
+	if err != nil {
+		return fmt.Errorf("protocol coordinate %s", err.Error())
+	}
+	if err := d_tag.Validate(c.Kind); err != nil {
+		return fmt.Errorf("protocol coordinate %s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package core
+
+import "testing"
+
+func TestParseDTag(t *testing.T) {
+	d_tag, err := ParseDTag("org.attnprotocol:promotion:abc:123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d_tag.Namespace != NamespaceATTN || d_tag.EventType != "promotion" || d_tag.Identifier != "abc:123" {
+		t.Errorf("unexpected d tag: %+v", d_tag)
+	}
+	if d_tag.String() != "org.attnprotocol:promotion:abc:123" {
+		t.Errorf("unexpected string: %s", d_tag.String())
+	}
+}
+
+func TestParseDTagInvalid(t *testing.T) {
+	invalid := []string{"", "promotion", "org.attnprotocol:promotion", "org.attnprotocol:promotion:", ":promotion:x", "org.attnprotocol::x"}
+	for _, value := range invalid {
+		if _, err := ParseDTag(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
+
+func TestDTagValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		kind  int
+		value string
+		valid bool
+	}{
+		{"promotion", KindPromotion, "org.attnprotocol:promotion:p1", true},
+		{"attention payment confirmation", KindAttentionPaymentConfirmation, "org.attnprotocol:attention-payment-confirmation:x", true},
+		{"city block", KindCityBlock, "org.cityprotocol:block:870500:abc", true},
+		{"wrong event type", KindPromotion, "org.attnprotocol:match:p1", false},
+		{"wrong namespace", KindPromotion, "org.cityprotocol:promotion:p1", false},
+		{"city block with attn namespace", KindCityBlock, "org.attnprotocol:block:1:abc", false},
+		{"unknown kind", 1, "org.attnprotocol:note:1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d_tag, err := ParseDTag(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+			if err := d_tag.Validate(tt.kind); (err == nil) != tt.valid {
+				t.Errorf("expected valid=%v, got err=%v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestNewDTag(t *testing.T) {
+	if got := NewDTag(KindMatch, "m1").String(); got != "org.attnprotocol:match:m1" {
+		t.Errorf("unexpected match d tag: %s", got)
+	}
+	if got := NewDTag(KindCityBlock, "870500:abc").String(); got != "org.cityprotocol:block:870500:abc" {
+		t.Errorf("unexpected block d tag: %s", got)
+	}
+}
+
+func TestParseCoordinate(t *testing.T) {
+	coordinate, err := ParseCoordinate("38188:" + testPubkey + ":org.attnprotocol:marketplace:m1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if coordinate.Kind != KindMarketplace || coordinate.Pubkey != testPubkey || coordinate.DTag != "org.attnprotocol:marketplace:m1" {
+		t.Errorf("unexpected coordinate: %+v", coordinate)
+	}
+	if coordinate.String() != "38188:"+testPubkey+":org.attnprotocol:marketplace:m1" {
+		t.Errorf("unexpected string: %s", coordinate.String())
+	}
+	if err := coordinate.Validate(); err != nil {
+		t.Errorf("expected valid coordinate, got %v", err)
+	}
+}
+
+func TestCoordinateValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"marketplace", "38188:" + testPubkey + ":org.attnprotocol:marketplace:m1", true},
+		{"city block", "38808:" + testPubkey + ":org.cityprotocol:block:870500:abc", true},
+		{"video", "34236:" + testPubkey + ":video-1", true},
+		{"missing namespace", "38188:" + testPubkey + ":m1", false},
+		{"wrong event type", "38188:" + testPubkey + ":org.attnprotocol:billboard:b1", false},
+		{"video with attn namespace", "34236:" + testPubkey + ":org.attnprotocol:video:v1", false},
+		{"empty pubkey", "38188::org.attnprotocol:marketplace:m1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coordinate, err := ParseCoordinate(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected parse error: %v", err)
+			}
+			if err := coordinate.Validate(); (err == nil) != tt.valid {
+				t.Errorf("expected valid=%v, got err=%v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestParseCoordinateInvalid(t *testing.T) {
+	invalid := []string{"", "38188", "38188:pubkey", "abc:pubkey:d"}
+	for _, value := range invalid {
+		if _, err := ParseCoordinate(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
@@ -0,0 +1,541 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// DecodedEvent is implemented by every typed event returned from Decode.
+type DecodedEvent interface {
+	// Header returns the tag-derived fields shared by all decoded events.
+	Header() *EventHeader
+}
+
+// EventHeader holds the fields every ATTN Protocol event carries in its tags.
+type EventHeader struct {
+	// Event is the raw Nostr event that was decoded.
+	Event *nostr.Event
+
+	// DTag is the addressable identifier from the 'd' tag.
+	DTag string
+
+	// BlockHeight is the block height from the 't' tag.
+	// For City Protocol block events it is taken from the content instead.
+	BlockHeight int64
+
+	// Coordinate is the event's own address (<kind>:<pubkey>:<d>).
+	Coordinate string
+
+	// Relays contains the relay URLs from 'r' tags.
+	Relays []string
+}
+
+// Header returns the event header.
+func (h *EventHeader) Header() *EventHeader {
+	return h
+}
+
+// MatchRefs holds the coordinates of the four parties referenced by a match.
+type MatchRefs struct {
+	MarketplaceCoordinate string
+	BillboardCoordinate   string
+	PromotionCoordinate   string
+	AttentionCoordinate   string
+}
+
+// Marketplace is a decoded MARKETPLACE event (kind 38188).
+type Marketplace struct {
+	EventHeader
+	Data *MarketplaceData
+
+	// BlockCoordinate is the City Protocol block coordinate from the 'a' tag.
+	BlockCoordinate string
+
+	// Kinds contains the supported content kinds from 'k' tags.
+	Kinds []int
+}
+
+// Billboard is a decoded BILLBOARD event (kind 38288).
+type Billboard struct {
+	EventHeader
+	Data *BillboardData
+
+	MarketplaceCoordinate string
+	Kinds                 []int
+	URL                   string
+}
+
+// Promotion is a decoded PROMOTION event (kind 38388).
+type Promotion struct {
+	EventHeader
+	Data *PromotionData
+
+	MarketplaceCoordinate string
+	BillboardCoordinate   string
+
+	// VideoCoordinate is the promoted content coordinate (34236:pubkey:d).
+	VideoCoordinate string
+
+	Kinds []int
+	URL   string
+}
+
+// Attention is a decoded ATTENTION event (kind 38488).
+type Attention struct {
+	EventHeader
+	Data *AttentionData
+
+	MarketplaceCoordinate string
+
+	// NIP-51 list coordinates (30000:pubkey:<list type>).
+	BlockedPromotionsCoordinate   string
+	BlockedPromotersCoordinate    string
+	TrustedMarketplacesCoordinate string
+	TrustedBillboardsCoordinate   string
+
+	Kinds []int
+}
+
+// Match is a decoded MATCH event (kind 38888).
+type Match struct {
+	EventHeader
+	MatchRefs
+	Data *MatchData
+
+	Kinds []int
+}
+
+// BillboardConfirmation is a decoded BILLBOARD_CONFIRMATION event (kind 38588).
+type BillboardConfirmation struct {
+	EventHeader
+	MatchRefs
+	Data *BillboardConfirmationData
+
+	MatchCoordinate string
+
+	// MatchEventID is the 'e' tag with the "match" marker.
+	MatchEventID string
+}
+
+// AttentionConfirmation is a decoded ATTENTION_CONFIRMATION event (kind 38688).
+type AttentionConfirmation struct {
+	EventHeader
+	MatchRefs
+	Data *AttentionConfirmationData
+
+	MatchCoordinate string
+
+	// MatchEventID is the 'e' tag with the "match" marker.
+	MatchEventID string
+}
+
+// MarketplaceConfirmation is a decoded MARKETPLACE_CONFIRMATION event (kind 38788).
+type MarketplaceConfirmation struct {
+	EventHeader
+	MatchRefs
+	Data *MarketplaceConfirmationData
+
+	MatchCoordinate string
+
+	// Event IDs from 'e' tags with "match", "billboard_confirmation"
+	// and "attention_confirmation" markers.
+	MatchEventID                 string
+	BillboardConfirmationEventID string
+	AttentionConfirmationEventID string
+}
+
+// AttentionPaymentConfirmation is a decoded ATTENTION_PAYMENT_CONFIRMATION event (kind 38988).
+type AttentionPaymentConfirmation struct {
+	EventHeader
+	MatchRefs
+	Data *AttentionPaymentConfirmationData
+
+	MatchCoordinate string
+
+	// Event IDs from 'e' tags with "match" and "marketplace_confirmation" markers.
+	MatchEventID                   string
+	MarketplaceConfirmationEventID string
+}
+
+// CityBlock is a decoded City Protocol BLOCK event (kind 38808).
+type CityBlock struct {
+	EventHeader
+	Data *CityBlockData
+}
+
+// Decode parses an ATTN Protocol or City Protocol block event into its typed form.
+// The concrete type of the result is one of *Marketplace, *Billboard, *Promotion,
+// *Attention, *Match, *BillboardConfirmation, *AttentionConfirmation,
+// *MarketplaceConfirmation, *AttentionPaymentConfirmation or *CityBlock.
+//
+// Decode only parses; it does not enforce the ATTN-01 rules checked by the
+// validation package.
+func Decode(event *nostr.Event) (DecodedEvent, error) {
+	if event == nil {
+		return nil, ErrNilEvent
+	}
+
+	switch event.Kind {
+	case KindMarketplace:
+		return DecodeMarketplace(event)
+	case KindBillboard:
+		return DecodeBillboard(event)
+	case KindPromotion:
+		return DecodePromotion(event)
+	case KindAttention:
+		return DecodeAttention(event)
+	case KindBillboardConfirmation:
+		return DecodeBillboardConfirmation(event)
+	case KindAttentionConfirmation:
+		return DecodeAttentionConfirmation(event)
+	case KindMarketplaceConfirmation:
+		return DecodeMarketplaceConfirmation(event)
+	case KindMatch:
+		return DecodeMatch(event)
+	case KindAttentionPaymentConfirmation:
+		return DecodeAttentionPaymentConfirmation(event)
+	case KindCityBlock:
+		return DecodeCityBlock(event)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedKind, event.Kind)
+	}
+}
+
+// DecodeMarketplace decodes a MARKETPLACE event (kind 38188).
+func DecodeMarketplace(event *nostr.Event) (*Marketplace, error) {
+	header, err := decodeHeader(event, KindMarketplace)
+	if err != nil {
+		return nil, err
+	}
+
+	var data MarketplaceData
+	if err := decodeContent(event, &data); err != nil {
+		return nil, err
+	}
+
+	return &Marketplace{
+		EventHeader:     header,
+		Data:            &data,
+		BlockCoordinate: coordinateTag(event, KindCityBlock),
+		Kinds:           tagKinds(event),
+	}, nil
+}
+
+// DecodeBillboard decodes a BILLBOARD event (kind 38288).
+func DecodeBillboard(event *nostr.Event) (*Billboard, error) {
+	header, err := decodeHeader(event, KindBillboard)
+	if err != nil {
+		return nil, err
+	}
+
+	var data BillboardData
+	if err := decodeContent(event, &data); err != nil {
+		return nil, err
+	}
+
+	return &Billboard{
+		EventHeader:           header,
+		Data:                  &data,
+		MarketplaceCoordinate: coordinateTag(event, KindMarketplace),
+		Kinds:                 tagKinds(event),
+		URL:                   tagValue(event, "u"),
+	}, nil
+}
+
+// DecodePromotion decodes a PROMOTION event (kind 38388).
+func DecodePromotion(event *nostr.Event) (*Promotion, error) {
+	header, err := decodeHeader(event, KindPromotion)
+	if err != nil {
+		return nil, err
+	}
+
+	var data PromotionData
+	if err := decodeContent(event, &data); err != nil {
+		return nil, err
+	}
+
+	return &Promotion{
+		EventHeader:           header,
+		Data:                  &data,
+		MarketplaceCoordinate: coordinateTag(event, KindMarketplace),
+		BillboardCoordinate:   coordinateTag(event, KindBillboard),
+		VideoCoordinate:       tagValueByPrefix(event, "a", "34236:"),
+		Kinds:                 tagKinds(event),
+		URL:                   tagValue(event, "u"),
+	}, nil
+}
+
+// DecodeAttention decodes an ATTENTION event (kind 38488).
+func DecodeAttention(event *nostr.Event) (*Attention, error) {
+	header, err := decodeHeader(event, KindAttention)
+	if err != nil {
+		return nil, err
+	}
+
+	var data AttentionData
+	if err := decodeContent(event, &data); err != nil {
+		return nil, err
+	}
+
+	return &Attention{
+		EventHeader:                   header,
+		Data:                          &data,
+		MarketplaceCoordinate:         coordinateTag(event, KindMarketplace),
+		BlockedPromotionsCoordinate:   listCoordinateTag(event, NIP51BlockedPromotions),
+		BlockedPromotersCoordinate:    listCoordinateTag(event, NIP51BlockedPromoters),
+		TrustedMarketplacesCoordinate: listCoordinateTag(event, NIP51TrustedMarketplaces),
+		TrustedBillboardsCoordinate:   listCoordinateTag(event, NIP51TrustedBillboards),
+		Kinds:                         tagKinds(event),
+	}, nil
+}
+
+// DecodeMatch decodes a MATCH event (kind 38888).
+func DecodeMatch(event *nostr.Event) (*Match, error) {
+	header, err := decodeHeader(event, KindMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	var data MatchData
+	if err := decodeContent(event, &data); err != nil {
+		return nil, err
+	}
+
+	return &Match{
+		EventHeader: header,
+		MatchRefs:   decodeMatchRefs(event),
+		Data:        &data,
+		Kinds:       tagKinds(event),
+	}, nil
+}
+
+// DecodeBillboardConfirmation decodes a BILLBOARD_CONFIRMATION event (kind 38588).
+func DecodeBillboardConfirmation(event *nostr.Event) (*BillboardConfirmation, error) {
+	header, err := decodeHeader(event, KindBillboardConfirmation)
+	if err != nil {
+		return nil, err
+	}
+
+	var data BillboardConfirmationData
+	if err := decodeContent(event, &data); err != nil {
+		return nil, err
+	}
+
+	return &BillboardConfirmation{
+		EventHeader:     header,
+		MatchRefs:       decodeMatchRefs(event),
+		Data:            &data,
+		MatchCoordinate: coordinateTag(event, KindMatch),
+		MatchEventID:    eTagByMarker(event, "match"),
+	}, nil
+}
+
+// DecodeAttentionConfirmation decodes an ATTENTION_CONFIRMATION event (kind 38688).
+func DecodeAttentionConfirmation(event *nostr.Event) (*AttentionConfirmation, error) {
+	header, err := decodeHeader(event, KindAttentionConfirmation)
+	if err != nil {
+		return nil, err
+	}
+
+	var data AttentionConfirmationData
+	if err := decodeContent(event, &data); err != nil {
+		return nil, err
+	}
+
+	return &AttentionConfirmation{
+		EventHeader:     header,
+		MatchRefs:       decodeMatchRefs(event),
+		Data:            &data,
+		MatchCoordinate: coordinateTag(event, KindMatch),
+		MatchEventID:    eTagByMarker(event, "match"),
+	}, nil
+}
+
+// DecodeMarketplaceConfirmation decodes a MARKETPLACE_CONFIRMATION event (kind 38788).
+func DecodeMarketplaceConfirmation(event *nostr.Event) (*MarketplaceConfirmation, error) {
+	header, err := decodeHeader(event, KindMarketplaceConfirmation)
+	if err != nil {
+		return nil, err
+	}
+
+	var data MarketplaceConfirmationData
+	if err := decodeContent(event, &data); err != nil {
+		return nil, err
+	}
+
+	return &MarketplaceConfirmation{
+		EventHeader:                  header,
+		MatchRefs:                    decodeMatchRefs(event),
+		Data:                         &data,
+		MatchCoordinate:              coordinateTag(event, KindMatch),
+		MatchEventID:                 eTagByMarker(event, "match"),
+		BillboardConfirmationEventID: eTagByMarker(event, "billboard_confirmation"),
+		AttentionConfirmationEventID: eTagByMarker(event, "attention_confirmation"),
+	}, nil
+}
+
+// DecodeAttentionPaymentConfirmation decodes an ATTENTION_PAYMENT_CONFIRMATION event (kind 38988).
+func DecodeAttentionPaymentConfirmation(event *nostr.Event) (*AttentionPaymentConfirmation, error) {
+	header, err := decodeHeader(event, KindAttentionPaymentConfirmation)
+	if err != nil {
+		return nil, err
+	}
+
+	var data AttentionPaymentConfirmationData
+	if err := decodeContent(event, &data); err != nil {
+		return nil, err
+	}
+
+	return &AttentionPaymentConfirmation{
+		EventHeader:                    header,
+		MatchRefs:                      decodeMatchRefs(event),
+		Data:                           &data,
+		MatchCoordinate:                coordinateTag(event, KindMatch),
+		MatchEventID:                   eTagByMarker(event, "match"),
+		MarketplaceConfirmationEventID: eTagByMarker(event, "marketplace_confirmation"),
+	}, nil
+}
+
+// DecodeCityBlock decodes a City Protocol BLOCK event (kind 38808).
+// The block height is read from the content, not from a 't' tag.
+func DecodeCityBlock(event *nostr.Event) (*CityBlock, error) {
+	header, err := decodeHeader(event, KindCityBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	var data CityBlockData
+	if err := decodeContent(event, &data); err != nil {
+		return nil, err
+	}
+	header.BlockHeight = data.BlockHeight
+
+	return &CityBlock{
+		EventHeader: header,
+		Data:        &data,
+	}, nil
+}
+
+// decodeHeader checks the event kind and parses the d and t tags.
+func decodeHeader(event *nostr.Event, kind int) (EventHeader, error) {
+	if event == nil {
+		return EventHeader{}, ErrNilEvent
+	}
+	if event.Kind != kind {
+		return EventHeader{}, fmt.Errorf("%w: expected %d, got %d", ErrKindMismatch, kind, event.Kind)
+	}
+
+	d_tag := tagValue(event, "d")
+	if d_tag == "" {
+		return EventHeader{}, ErrMissingDTag
+	}
+
+	header := EventHeader{
+		Event:      event,
+		DTag:       d_tag,
+		Coordinate: Coordinate{Kind: event.Kind, Pubkey: event.PubKey, DTag: d_tag}.String(),
+		Relays:     tagValues(event, "r"),
+	}
+
+	// City Protocol block events carry the height in content only
+	if kind == KindCityBlock {
+		return header, nil
+	}
+
+	block_height, err := strconv.ParseInt(tagValue(event, "t"), 10, 64)
+	if err != nil {
+		return EventHeader{}, ErrInvalidBlockHeight
+	}
+	header.BlockHeight = block_height
+
+	return header, nil
+}
+
+// decodeContent unmarshals event content into the kind's data struct.
+func decodeContent(event *nostr.Event, v any) error {
+	if err := json.Unmarshal([]byte(event.Content), v); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidContent, err.Error())
+	}
+	return nil
+}
+
+// decodeMatchRefs reads the four party coordinates from 'a' tags.
+func decodeMatchRefs(event *nostr.Event) MatchRefs {
+	return MatchRefs{
+		MarketplaceCoordinate: coordinateTag(event, KindMarketplace),
+		BillboardCoordinate:   coordinateTag(event, KindBillboard),
+		PromotionCoordinate:   coordinateTag(event, KindPromotion),
+		AttentionCoordinate:   coordinateTag(event, KindAttention),
+	}
+}
+
+// coordinateTag gets the first 'a' tag referencing an event of the given kind.
+func coordinateTag(event *nostr.Event, kind int) string {
+	return tagValueByPrefix(event, "a", fmt.Sprintf("%d:", kind))
+}
+
+// listCoordinateTag gets the 'a' tag referencing a NIP-51 list of the given type.
+func listCoordinateTag(event *nostr.Event, list_type string) string {
+	for _, tag := range event.Tags {
+		if len(tag) >= 2 && tag[0] == "a" && strings.HasPrefix(tag[1], "30000:") && strings.HasSuffix(tag[1], ":"+list_type) {
+			return tag[1]
+		}
+	}
+	return ""
+}
+
+// tagKinds parses all numeric 'k' tags, skipping values that are not integers.
+func tagKinds(event *nostr.Event) []int {
+	var kinds []int
+	for _, value := range tagValues(event, "k") {
+		if kind, err := strconv.Atoi(value); err == nil {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// tagValue gets the first value for a tag with the given name.
+func tagValue(event *nostr.Event, tag_name string) string {
+	for _, tag := range event.Tags {
+		if len(tag) >= 2 && tag[0] == tag_name {
+			return tag[1]
+		}
+	}
+	return ""
+}
+
+// tagValueByPrefix gets the first tag value that starts with the given prefix.
+func tagValueByPrefix(event *nostr.Event, tag_name, prefix string) string {
+	for _, tag := range event.Tags {
+		if len(tag) >= 2 && tag[0] == tag_name && strings.HasPrefix(tag[1], prefix) {
+			return tag[1]
+		}
+	}
+	return ""
+}
+
+// tagValues gets all values for tags with the given name.
+func tagValues(event *nostr.Event, tag_name string) []string {
+	var values []string
+	for _, tag := range event.Tags {
+		if len(tag) >= 2 && tag[0] == tag_name {
+			values = append(values, tag[1])
+		}
+	}
+	return values
+}
+
+// eTagByMarker gets the 'e' tag value with the given marker.
+func eTagByMarker(event *nostr.Event, marker string) string {
+	for _, tag := range event.Tags {
+		if len(tag) >= 4 && tag[0] == "e" && tag[3] == marker {
+			return tag[1]
+		}
+	}
+	return ""
+}
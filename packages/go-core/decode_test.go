@@ -0,0 +1,149 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+const testPubkey = "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+
+func TestDecodePromotion(t *testing.T) {
+	event := &nostr.Event{
+		Kind:    KindPromotion,
+		PubKey:  testPubkey,
+		Content: `{"duration":30000,"bid":5000,"ref_promotion_id":"p1"}`,
+		Tags: nostr.Tags{
+			{"d", "org.attnprotocol:promotion:p1"},
+			{"t", "870500"},
+			{"a", "38188:" + testPubkey + ":org.attnprotocol:marketplace:m1"},
+			{"a", "38288:" + testPubkey + ":org.attnprotocol:billboard:b1"},
+			{"a", "34236:" + testPubkey + ":video-1"},
+			{"r", "wss://relay.example.com"},
+			{"k", "34236"},
+			{"u", "https://example.com"},
+		},
+	}
+
+	decoded, err := Decode(event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	promotion, ok := decoded.(*Promotion)
+	if !ok {
+		t.Fatalf("expected *Promotion, got %T", decoded)
+	}
+
+	if promotion.DTag != "org.attnprotocol:promotion:p1" {
+		t.Errorf("unexpected d tag: %s", promotion.DTag)
+	}
+	if promotion.BlockHeight != 870500 {
+		t.Errorf("expected block height 870500, got %d", promotion.BlockHeight)
+	}
+	if promotion.Coordinate != "38388:"+testPubkey+":org.attnprotocol:promotion:p1" {
+		t.Errorf("unexpected coordinate: %s", promotion.Coordinate)
+	}
+	if promotion.MarketplaceCoordinate != "38188:"+testPubkey+":org.attnprotocol:marketplace:m1" {
+		t.Errorf("unexpected marketplace coordinate: %s", promotion.MarketplaceCoordinate)
+	}
+	if promotion.BillboardCoordinate != "38288:"+testPubkey+":org.attnprotocol:billboard:b1" {
+		t.Errorf("unexpected billboard coordinate: %s", promotion.BillboardCoordinate)
+	}
+	if promotion.VideoCoordinate != "34236:"+testPubkey+":video-1" {
+		t.Errorf("unexpected video coordinate: %s", promotion.VideoCoordinate)
+	}
+	if promotion.Data.Bid != 5000 || promotion.Data.Duration != 30000 {
+		t.Errorf("unexpected content: %+v", promotion.Data)
+	}
+	if len(promotion.Kinds) != 1 || promotion.Kinds[0] != 34236 {
+		t.Errorf("unexpected kinds: %v", promotion.Kinds)
+	}
+	if promotion.Header().Event != event {
+		t.Error("expected header to reference the raw event")
+	}
+}
+
+func TestDecodeMarketplaceConfirmationMarkers(t *testing.T) {
+	event := &nostr.Event{
+		Kind:    KindMarketplaceConfirmation,
+		PubKey:  testPubkey,
+		Content: `{"ref_match_id":"m1"}`,
+		Tags: nostr.Tags{
+			{"d", "org.attnprotocol:marketplace-confirmation:c1"},
+			{"t", "870500"},
+			{"a", "38888:" + testPubkey + ":org.attnprotocol:match:m1"},
+			{"e", "match-id", "", "match"},
+			{"e", "bc-id", "", "billboard_confirmation"},
+			{"e", "ac-id", "", "attention_confirmation"},
+		},
+	}
+
+	confirmation, err := DecodeMarketplaceConfirmation(event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if confirmation.MatchEventID != "match-id" {
+		t.Errorf("unexpected match event id: %s", confirmation.MatchEventID)
+	}
+	if confirmation.BillboardConfirmationEventID != "bc-id" {
+		t.Errorf("unexpected billboard confirmation id: %s", confirmation.BillboardConfirmationEventID)
+	}
+	if confirmation.AttentionConfirmationEventID != "ac-id" {
+		t.Errorf("unexpected attention confirmation id: %s", confirmation.AttentionConfirmationEventID)
+	}
+	if confirmation.MatchCoordinate == "" {
+		t.Error("expected match coordinate")
+	}
+}
+
+func TestDecodeCityBlockHeightFromContent(t *testing.T) {
+	event := &nostr.Event{
+		Kind:    KindCityBlock,
+		PubKey:  testPubkey,
+		Content: `{"block_height":870500,"block_hash":"abc"}`,
+		Tags:    nostr.Tags{{"d", "org.cityprotocol:block:870500:abc"}},
+	}
+
+	block, err := DecodeCityBlock(event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if block.BlockHeight != 870500 {
+		t.Errorf("expected block height 870500, got %d", block.BlockHeight)
+	}
+	if block.Data.BlockHash != "abc" {
+		t.Errorf("unexpected block hash: %s", block.Data.BlockHash)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    *nostr.Event
+		expected error
+	}{
+		{"nil event", nil, ErrNilEvent},
+		{"unsupported kind", &nostr.Event{Kind: 1}, ErrUnsupportedKind},
+		{"missing d tag", &nostr.Event{Kind: KindMatch, Content: "{}", Tags: nostr.Tags{{"t", "1"}}}, ErrMissingDTag},
+		{"missing t tag", &nostr.Event{Kind: KindMatch, Content: "{}", Tags: nostr.Tags{{"d", "x"}}}, ErrInvalidBlockHeight},
+		{"invalid t tag", &nostr.Event{Kind: KindMatch, Content: "{}", Tags: nostr.Tags{{"d", "x"}, {"t", "abc"}}}, ErrInvalidBlockHeight},
+		{"invalid content", &nostr.Event{Kind: KindMatch, Content: "not json", Tags: nostr.Tags{{"d", "x"}, {"t", "1"}}}, ErrInvalidContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.event); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestDecodeKindMismatch(t *testing.T) {
+	event := &nostr.Event{Kind: KindMatch, Content: "{}", Tags: nostr.Tags{{"d", "x"}, {"t", "1"}}}
+	if _, err := DecodePromotion(event); !errors.Is(err, ErrKindMismatch) {
+		t.Errorf("expected ErrKindMismatch, got %v", err)
+	}
+}
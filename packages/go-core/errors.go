@@ -0,0 +1,42 @@
+package core
+
+import "errors"
+
+var (
+	// ErrNilEvent is returned when a nil event is passed to a decoder.
+	ErrNilEvent = errors.New("event is nil")
+
+	// ErrUnsupportedKind is returned when an event kind has no typed decoder.
+	ErrUnsupportedKind = errors.New("unsupported event kind")
+
+	// ErrKindMismatch is returned when a kind-specific decoder receives another kind.
+	ErrKindMismatch = errors.New("event kind mismatch")
+
+	// ErrMissingDTag is returned when an addressable event has no 'd' tag.
+	ErrMissingDTag = errors.New("missing 'd' tag")
+
+	// ErrInvalidBlockHeight is returned when the 't' tag is missing or not numeric.
+	ErrInvalidBlockHeight = errors.New("invalid block height in 't' tag")
+
+	// ErrInvalidContent is returned when event content is not valid JSON for its kind.
+	ErrInvalidContent = errors.New("invalid event content")
+
+	// ErrUnknownListType is returned when a NIP-51 list 'd' tag is not an ATTN Protocol list type.
+	ErrUnknownListType = errors.New("unknown list type")
+
+	// ErrListTypeMismatch is returned when a typed list parser receives another list type.
+	ErrListTypeMismatch = errors.New("list type mismatch")
+
+	// ErrInvalidPrivateKey is returned when a private key is not a valid 32-byte secp256k1 key.
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+
+	// ErrEncryptionUnsupported is returned when a signer cannot NIP-44 encrypt or decrypt.
+	ErrEncryptionUnsupported = errors.New("signer does not support NIP-44 encryption")
+
+	// ErrNegativeAmount is returned when an amount or the result of amount arithmetic is negative.
+	ErrNegativeAmount = errors.New("amount is negative")
+
+	// ErrAmountOverflow is returned when an amount or the result of amount arithmetic
+	// exceeds the bitcoin supply.
+	ErrAmountOverflow = errors.New("amount exceeds the bitcoin supply")
+)
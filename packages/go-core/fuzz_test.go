@@ -0,0 +1,70 @@
+package core
+
+import "testing"
+
+// The parsers read d tags and coordinates from untrusted events. Each target checks
+// that parsing never panics and that a parsed value survives a String round trip.
+
+func FuzzParseDTag(f *testing.F) {
+	f.Add("org.attnprotocol:marketplace:test-marketplace")
+	f.Add("org.attnprotocol:promotion:abc:123")
+	f.Add("org.cityprotocol:block:870500:" + testBlockHash)
+	f.Add("org.attnprotocol::")
+	f.Add(":::")
+
+	f.Fuzz(func(t *testing.T, value string) {
+		d_tag, err := ParseDTag(value)
+		if err != nil {
+			return
+		}
+		d_tag.Validate(KindMatch)
+		d_tag.Validate(KindCityBlock)
+
+		reparsed, err := ParseDTag(d_tag.String())
+		if err != nil || reparsed != d_tag {
+			t.Errorf("round trip of %q: got %+v, %v", value, reparsed, err)
+		}
+	})
+}
+
+func FuzzParseCoordinate(f *testing.F) {
+	f.Add("38188:" + testPubkey + ":org.attnprotocol:marketplace:test-marketplace")
+	f.Add("38808:" + testPubkey + ":org.cityprotocol:block:870500:" + testBlockHash)
+	f.Add("30000:" + testPubkey + ":org.attnprotocol:promotion:blocked")
+	f.Add("34236:" + testPubkey + ":test-video")
+	f.Add("+38188::")
+	f.Add("-1:x:y")
+
+	f.Fuzz(func(t *testing.T, value string) {
+		coordinate, err := ParseCoordinate(value)
+		if err != nil {
+			return
+		}
+		coordinate.Validate()
+		coordinate.ParsedDTag()
+		coordinate.IsProtocol()
+
+		reparsed, err := ParseCoordinate(coordinate.String())
+		if err != nil || reparsed != coordinate {
+			t.Errorf("round trip of %q: got %+v, %v", value, reparsed, err)
+		}
+	})
+}
+
+func FuzzParseCityBlockID(f *testing.F) {
+	f.Add("org.cityprotocol:block:870500:" + testBlockHash)
+	f.Add("org.cityprotocol:block:-1:" + testBlockHash)
+	f.Add("org.cityprotocol:block::")
+
+	f.Fuzz(func(t *testing.T, value string) {
+		block_id, err := ParseCityBlockID(value)
+		if err != nil {
+			return
+		}
+
+		reparsed, err := ParseCityBlockID(block_id.String())
+		if err != nil || reparsed != block_id {
+			t.Errorf("round trip of %q: got %+v, %v", value, reparsed, err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package lifecycle
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrIllegalTransition is returned when an event arrives before the step it depends on,
+	// or after the match has reached a terminal state.
+	ErrIllegalTransition = errors.New("illegal transition")
+
+	// ErrUnrelatedEvent is returned when an event references a different match or confirmation.
+	ErrUnrelatedEvent = errors.New("event belongs to a different match")
+
+	// ErrConflictingEvent is returned when a step already recorded receives a different event.
+	ErrConflictingEvent = errors.New("conflicting event for recorded step")
+
+	// ErrWrongParty is returned when an event is not authored by the party responsible for it.
+	ErrWrongParty = errors.New("event published by the wrong party")
+
+	// ErrUnsupportedKind is returned for kinds that are not part of the match lifecycle.
+	ErrUnsupportedKind = errors.New("kind is not part of the match lifecycle")
+)
+
+// TransitionError describes why an event could not be applied to a match.
+type TransitionError struct {
+	// State is the match state when the event was rejected.
+	State State
+
+	// Kind is the kind of the rejected event.
+	Kind int
+
+	// Reason is a human-readable explanation.
+	Reason string
+
+	// Err is one of the sentinel errors in this package.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: kind %d in state %s: %s", e.Err, e.Kind, e.State, e.Reason)
+}
+
+// Unwrap returns the sentinel error so callers can use errors.Is.
+func (e *TransitionError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,369 @@
+// Package lifecycle models a match's progress through its confirmation chain:
+//
+//	MATCH (38888)
+//	  → BILLBOARD_CONFIRMATION (38588) + ATTENTION_CONFIRMATION (38688), in either order
+//	  → MARKETPLACE_CONFIRMATION (38788)
+//	  → ATTENTION_PAYMENT_CONFIRMATION (38988)
+//
+// A Match takes events one at a time and reports its state, the parties that still
+// have to act, and whether it has reached a terminal outcome: paid, or expired when
+// the chain stalled and the caller gave up on it. Events that arrive out
+// of order, reference another match, or are published by the wrong party are rejected
+// with a *TransitionError. Tracker routes events for many matches.
+package lifecycle
+
+import (
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// State is a match's position in the confirmation chain.
+type State string
+
+// Match states.
+const (
+	// StateAwaitingMatch means no MATCH event has been applied yet.
+	StateAwaitingMatch State = "awaiting_match"
+
+	// StateMatched means the MATCH was published and neither party has confirmed.
+	StateMatched State = "matched"
+
+	// StateBillboardConfirmed means only the billboard has confirmed.
+	StateBillboardConfirmed State = "billboard_confirmed"
+
+	// StateAttentionConfirmed means only the attention owner has confirmed.
+	StateAttentionConfirmed State = "attention_confirmed"
+
+	// StatePartiesConfirmed means both parties have confirmed and the marketplace has not.
+	StatePartiesConfirmed State = "parties_confirmed"
+
+	// StateMarketplaceConfirmed means the marketplace settled and payment is not yet confirmed.
+	StateMarketplaceConfirmed State = "marketplace_confirmed"
+
+	// StatePaid means the attention owner confirmed payment. It is terminal.
+	StatePaid State = "paid"
+
+	// StateExpired means the match was given up before payment was confirmed. It is terminal.
+	StateExpired State = "expired"
+)
+
+// Party is a participant responsible for a step in the chain.
+type Party string
+
+// Parties.
+const (
+	PartyMarketplace Party = "marketplace"
+	PartyBillboard   Party = "billboard"
+	PartyAttention   Party = "attention"
+)
+
+// Outcome is the final result of a match.
+type Outcome string
+
+// Outcomes.
+const (
+	// OutcomePending means the match has not reached a terminal state.
+	OutcomePending Outcome = "pending"
+
+	// OutcomeCompleted means the attention owner confirmed payment.
+	OutcomeCompleted Outcome = "completed"
+
+	// OutcomeExpired means the match stalled and was expired before payment was confirmed.
+	OutcomeExpired Outcome = "expired"
+)
+
+// Transition records a state change caused by an applied event.
+// From equals To when an already applied event is replayed.
+type Transition struct {
+	From  State
+	To    State
+	Event *nostr.Event
+}
+
+// Match tracks a single match through the confirmation chain.
+type Match struct {
+	state State
+
+	match                   *nostr.Event
+	billboardConfirmation   *nostr.Event
+	attentionConfirmation   *nostr.Event
+	marketplaceConfirmation *nostr.Event
+	paymentConfirmation     *nostr.Event
+
+	// Party pubkeys and coordinates taken from the MATCH event
+	marketplacePubkey string
+	billboardPubkey   string
+	attentionPubkey   string
+	coordinates       map[int]string
+
+	// Block height from the MATCH event's 't' tag
+	blockHeight int64
+}
+
+// New returns a Match awaiting its MATCH event.
+func New() *Match {
+	return &Match{state: StateAwaitingMatch}
+}
+
+// State returns the current state.
+func (m *Match) State() State {
+	return m.state
+}
+
+// MatchEvent returns the applied MATCH event, or nil.
+func (m *Match) MatchEvent() *nostr.Event {
+	return m.match
+}
+
+// BlockHeight returns the block height of the applied MATCH event, or 0.
+func (m *Match) BlockHeight() int64 {
+	return m.blockHeight
+}
+
+// Terminal reports whether the match has reached a terminal state.
+func (m *Match) Terminal() bool {
+	return m.state == StatePaid || m.state == StateExpired
+}
+
+// Outcome returns the terminal outcome, or OutcomePending.
+func (m *Match) Outcome() Outcome {
+	switch m.state {
+	case StatePaid:
+		return OutcomeCompleted
+	case StateExpired:
+		return OutcomeExpired
+	}
+	return OutcomePending
+}
+
+// Expire gives up on a match that has not reached a terminal state and reports
+// whether it did. Later confirmations for an expired match are rejected with
+// ErrIllegalTransition.
+func (m *Match) Expire() bool {
+	if m.Terminal() {
+		return false
+	}
+	m.state = StateExpired
+	return true
+}
+
+// MissingParties returns the parties whose events are needed for the next step.
+func (m *Match) MissingParties() []Party {
+	switch m.state {
+	case StateAwaitingMatch:
+		return []Party{PartyMarketplace}
+	case StateMatched:
+		return []Party{PartyBillboard, PartyAttention}
+	case StateBillboardConfirmed:
+		return []Party{PartyAttention}
+	case StateAttentionConfirmed:
+		return []Party{PartyBillboard}
+	case StatePartiesConfirmed:
+		return []Party{PartyMarketplace}
+	case StateMarketplaceConfirmed:
+		return []Party{PartyAttention}
+	}
+	return nil
+}
+
+// Apply advances the match with the next event.
+// Replaying an event that was already applied is a no-op.
+func (m *Match) Apply(event *nostr.Event) (Transition, error) {
+	if event == nil {
+		return Transition{}, core.ErrNilEvent
+	}
+
+	from := m.state
+	var err error
+	switch event.Kind {
+	case core.KindMatch:
+		err = m.applyMatch(event)
+	case core.KindBillboardConfirmation:
+		err = m.applyPartyConfirmation(event, &m.billboardConfirmation, m.billboardPubkey)
+	case core.KindAttentionConfirmation:
+		err = m.applyPartyConfirmation(event, &m.attentionConfirmation, m.attentionPubkey)
+	case core.KindMarketplaceConfirmation:
+		err = m.applyMarketplaceConfirmation(event)
+	case core.KindAttentionPaymentConfirmation:
+		err = m.applyPaymentConfirmation(event)
+	default:
+		err = m.reject(event.Kind, ErrUnsupportedKind, "expected kinds 38888, 38588, 38688, 38788 or 38988")
+	}
+	if err != nil {
+		return Transition{}, err
+	}
+
+	return Transition{From: from, To: m.state, Event: event}, nil
+}
+
+// snapshot returns a copy of the match that later Apply calls do not change.
+// The coordinates map is shared; it is never modified once the MATCH is applied.
+func (m *Match) snapshot() *Match {
+	copied := *m
+	return &copied
+}
+
+func (m *Match) applyMatch(event *nostr.Event) error {
+	if m.match != nil {
+		if m.match.ID == event.ID {
+			return nil
+		}
+		return m.reject(event.Kind, ErrConflictingEvent, "match already recorded")
+	}
+
+	match, err := core.DecodeMatch(event)
+	if err != nil {
+		return m.reject(event.Kind, ErrUnrelatedEvent, err.Error())
+	}
+
+	m.coordinates = map[int]string{
+		core.KindMarketplace: match.MarketplaceCoordinate,
+		core.KindBillboard:   match.BillboardCoordinate,
+		core.KindPromotion:   match.PromotionCoordinate,
+		core.KindAttention:   match.AttentionCoordinate,
+		core.KindMatch:       match.Coordinate,
+	}
+	m.marketplacePubkey = event.PubKey
+	m.billboardPubkey = coordinatePubkey(match.BillboardCoordinate)
+	m.attentionPubkey = coordinatePubkey(match.AttentionCoordinate)
+	m.blockHeight = match.BlockHeight
+
+	m.match = event
+	m.state = StateMatched
+	return nil
+}
+
+func (m *Match) applyPartyConfirmation(event *nostr.Event, slot **nostr.Event, party_pubkey string) error {
+	if *slot != nil {
+		if (*slot).ID == event.ID {
+			return nil
+		}
+		return m.reject(event.Kind, ErrConflictingEvent, "confirmation already recorded")
+	}
+	if m.match == nil {
+		return m.reject(event.Kind, ErrIllegalTransition, "confirmation received before match")
+	}
+	if m.state != StateMatched && m.state != StateBillboardConfirmed && m.state != StateAttentionConfirmed {
+		return m.reject(event.Kind, ErrIllegalTransition, "party confirmations are closed")
+	}
+	if getETagByMarker(event, "match") != m.match.ID {
+		return m.reject(event.Kind, ErrUnrelatedEvent, "'match' e tag does not reference this match")
+	}
+	if err := m.checkCoordinates(event); err != nil {
+		return err
+	}
+	if event.PubKey != party_pubkey {
+		return m.reject(event.Kind, ErrWrongParty, "confirmation must be published by the party named in the match")
+	}
+
+	*slot = event
+	switch {
+	case m.billboardConfirmation != nil && m.attentionConfirmation != nil:
+		m.state = StatePartiesConfirmed
+	case m.billboardConfirmation != nil:
+		m.state = StateBillboardConfirmed
+	default:
+		m.state = StateAttentionConfirmed
+	}
+	return nil
+}
+
+func (m *Match) applyMarketplaceConfirmation(event *nostr.Event) error {
+	if m.marketplaceConfirmation != nil {
+		if m.marketplaceConfirmation.ID == event.ID {
+			return nil
+		}
+		return m.reject(event.Kind, ErrConflictingEvent, "marketplace confirmation already recorded")
+	}
+	if m.state != StatePartiesConfirmed {
+		return m.reject(event.Kind, ErrIllegalTransition, "marketplace confirmation requires both party confirmations")
+	}
+	if getETagByMarker(event, "match") != m.match.ID ||
+		getETagByMarker(event, "billboard_confirmation") != m.billboardConfirmation.ID ||
+		getETagByMarker(event, "attention_confirmation") != m.attentionConfirmation.ID {
+		return m.reject(event.Kind, ErrUnrelatedEvent, "marker e tags do not reference this match and its party confirmations")
+	}
+	if err := m.checkCoordinates(event); err != nil {
+		return err
+	}
+	if event.PubKey != m.marketplacePubkey {
+		return m.reject(event.Kind, ErrWrongParty, "marketplace confirmation must be published by the match author")
+	}
+
+	m.marketplaceConfirmation = event
+	m.state = StateMarketplaceConfirmed
+	return nil
+}
+
+func (m *Match) applyPaymentConfirmation(event *nostr.Event) error {
+	if m.paymentConfirmation != nil {
+		if m.paymentConfirmation.ID == event.ID {
+			return nil
+		}
+		return m.reject(event.Kind, ErrConflictingEvent, "payment confirmation already recorded")
+	}
+	if m.state != StateMarketplaceConfirmed {
+		return m.reject(event.Kind, ErrIllegalTransition, "payment confirmation requires a marketplace confirmation")
+	}
+	if getETagByMarker(event, "marketplace_confirmation") != m.marketplaceConfirmation.ID {
+		return m.reject(event.Kind, ErrUnrelatedEvent, "'marketplace_confirmation' e tag does not reference this match's marketplace confirmation")
+	}
+	if match_id := getETagByMarker(event, "match"); match_id != "" && match_id != m.match.ID {
+		return m.reject(event.Kind, ErrUnrelatedEvent, "'match' e tag does not reference this match")
+	}
+	if err := m.checkCoordinates(event); err != nil {
+		return err
+	}
+	if event.PubKey != m.attentionPubkey {
+		return m.reject(event.Kind, ErrWrongParty, "payment confirmation must be published by the attention owner")
+	}
+
+	m.paymentConfirmation = event
+	m.state = StatePaid
+	return nil
+}
+
+// checkCoordinates verifies that a confirmation addresses the same events as the match.
+func (m *Match) checkCoordinates(event *nostr.Event) error {
+	for kind, coordinate := range m.coordinates {
+		if value := coordinateTag(event, kind); value != "" && value != coordinate {
+			return m.reject(event.Kind, ErrUnrelatedEvent, "coordinates do not match the match event")
+		}
+	}
+	return nil
+}
+
+func (m *Match) reject(kind int, err error, reason string) error {
+	return &TransitionError{State: m.state, Kind: kind, Reason: reason, Err: err}
+}
+
+// coordinatePubkey returns the pubkey part of a coordinate, or "" if it does not parse.
+func coordinatePubkey(value string) string {
+	coordinate, err := core.ParseCoordinate(value)
+	if err != nil {
+		return ""
+	}
+	return coordinate.Pubkey
+}
+
+// coordinateTag returns the first 'a' tag addressing the given kind.
+func coordinateTag(event *nostr.Event, kind int) string {
+	for _, tag := range event.Tags {
+		if len(tag) >= 2 && tag[0] == "a" {
+			if coordinate, err := core.ParseCoordinate(tag[1]); err == nil && coordinate.Kind == kind {
+				return tag[1]
+			}
+		}
+	}
+	return ""
+}
+
+// getETagByMarker returns the 'e' tag value with the given marker.
+func getETagByMarker(event *nostr.Event, marker string) string {
+	for _, tag := range event.Tags {
+		if len(tag) >= 4 && tag[0] == "e" && tag[3] == marker {
+			return tag[1]
+		}
+	}
+	return ""
+}
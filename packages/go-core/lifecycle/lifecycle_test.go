@@ -0,0 +1,348 @@
+package lifecycle
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+const (
+	marketplacePubkey = "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+	billboardPubkey   = "b1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+	attentionPubkey   = "c1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+	promoterPubkey    = "d1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
+)
+
+// chainTags returns the a tags shared by every event in the chain of the named match.
+func chainTags(match_id string) nostr.Tags {
+	return nostr.Tags{
+		{"a", "38188:" + marketplacePubkey + ":org.attnprotocol:marketplace:m1"},
+		{"a", "38288:" + billboardPubkey + ":org.attnprotocol:billboard:b1"},
+		{"a", "38388:" + promoterPubkey + ":org.attnprotocol:promotion:p1"},
+		{"a", "38488:" + attentionPubkey + ":org.attnprotocol:attention:a1"},
+		{"a", "38888:" + marketplacePubkey + ":org.attnprotocol:match:" + match_id},
+	}
+}
+
+func newEvent(kind int, pubkey string, d_tag string, match_id string, tags ...nostr.Tag) *nostr.Event {
+	event := &nostr.Event{
+		Kind:    kind,
+		PubKey:  pubkey,
+		Content: `{}`,
+		Tags:    nostr.Tags{{"d", d_tag}, {"t", "870500"}},
+	}
+	event.Tags = append(event.Tags, chainTags(match_id)...)
+	event.Tags = append(event.Tags, tags...)
+	event.ID = event.GetID()
+	return event
+}
+
+// chain holds one event for every step of a match.
+type chain struct {
+	match                   *nostr.Event
+	billboardConfirmation   *nostr.Event
+	attentionConfirmation   *nostr.Event
+	marketplaceConfirmation *nostr.Event
+	paymentConfirmation     *nostr.Event
+}
+
+func newChain(match_id string) chain {
+	var c chain
+	c.match = newEvent(core.KindMatch, marketplacePubkey, "org.attnprotocol:match:"+match_id, match_id)
+	c.billboardConfirmation = newEvent(core.KindBillboardConfirmation, billboardPubkey, "org.attnprotocol:billboard-confirmation:"+match_id, match_id,
+		nostr.Tag{"e", c.match.ID, "", "match"},
+	)
+	c.attentionConfirmation = newEvent(core.KindAttentionConfirmation, attentionPubkey, "org.attnprotocol:attention-confirmation:"+match_id, match_id,
+		nostr.Tag{"e", c.match.ID, "", "match"},
+	)
+	c.marketplaceConfirmation = newEvent(core.KindMarketplaceConfirmation, marketplacePubkey, "org.attnprotocol:marketplace-confirmation:"+match_id, match_id,
+		nostr.Tag{"e", c.match.ID, "", "match"},
+		nostr.Tag{"e", c.billboardConfirmation.ID, "", "billboard_confirmation"},
+		nostr.Tag{"e", c.attentionConfirmation.ID, "", "attention_confirmation"},
+	)
+	c.paymentConfirmation = newEvent(core.KindAttentionPaymentConfirmation, attentionPubkey, "org.attnprotocol:attention-payment-confirmation:"+match_id, match_id,
+		nostr.Tag{"e", c.marketplaceConfirmation.ID, "", "marketplace_confirmation"},
+	)
+	return c
+}
+
+func mustApply(t *testing.T, m *Match, event *nostr.Event, want State) {
+	t.Helper()
+	transition, err := m.Apply(event)
+	if err != nil {
+		t.Fatalf("unexpected error applying kind %d: %v", event.Kind, err)
+	}
+	if transition.To != want || m.State() != want {
+		t.Fatalf("expected state %s, got %s", want, m.State())
+	}
+}
+
+func TestMatchHappyPath(t *testing.T) {
+	c := newChain("x1")
+	m := New()
+
+	if m.State() != StateAwaitingMatch || !slices.Equal(m.MissingParties(), []Party{PartyMarketplace}) {
+		t.Fatalf("unexpected initial state %s / %v", m.State(), m.MissingParties())
+	}
+
+	mustApply(t, m, c.match, StateMatched)
+	if !slices.Equal(m.MissingParties(), []Party{PartyBillboard, PartyAttention}) {
+		t.Errorf("unexpected missing parties: %v", m.MissingParties())
+	}
+
+	mustApply(t, m, c.billboardConfirmation, StateBillboardConfirmed)
+	if !slices.Equal(m.MissingParties(), []Party{PartyAttention}) {
+		t.Errorf("unexpected missing parties: %v", m.MissingParties())
+	}
+
+	mustApply(t, m, c.attentionConfirmation, StatePartiesConfirmed)
+	mustApply(t, m, c.marketplaceConfirmation, StateMarketplaceConfirmed)
+	if m.Terminal() || m.Outcome() != OutcomePending {
+		t.Errorf("match should not be terminal before payment")
+	}
+
+	mustApply(t, m, c.paymentConfirmation, StatePaid)
+	if !m.Terminal() || m.Outcome() != OutcomeCompleted || len(m.MissingParties()) != 0 {
+		t.Errorf("expected completed terminal match, got %s / %s / %v", m.State(), m.Outcome(), m.MissingParties())
+	}
+}
+
+func TestMatchAttentionConfirmsFirst(t *testing.T) {
+	c := newChain("x1")
+	m := New()
+
+	mustApply(t, m, c.match, StateMatched)
+	mustApply(t, m, c.attentionConfirmation, StateAttentionConfirmed)
+	if !slices.Equal(m.MissingParties(), []Party{PartyBillboard}) {
+		t.Errorf("unexpected missing parties: %v", m.MissingParties())
+	}
+	mustApply(t, m, c.billboardConfirmation, StatePartiesConfirmed)
+}
+
+func TestMatchReplayIsNoop(t *testing.T) {
+	c := newChain("x1")
+	m := New()
+
+	mustApply(t, m, c.match, StateMatched)
+	mustApply(t, m, c.billboardConfirmation, StateBillboardConfirmed)
+
+	transition, err := m.Apply(c.billboardConfirmation)
+	if err != nil {
+		t.Fatalf("unexpected error on replay: %v", err)
+	}
+	if transition.From != StateBillboardConfirmed || transition.To != StateBillboardConfirmed {
+		t.Errorf("expected no-op transition, got %s -> %s", transition.From, transition.To)
+	}
+
+	if _, err := m.Apply(c.match); err != nil {
+		t.Errorf("unexpected error replaying match: %v", err)
+	}
+}
+
+func TestMatchRejections(t *testing.T) {
+	c := newChain("x1")
+	other := newChain("x2")
+
+	wrong_party := newEvent(core.KindBillboardConfirmation, attentionPubkey, "org.attnprotocol:billboard-confirmation:x1", "x1",
+		nostr.Tag{"e", c.match.ID, "", "match"},
+	)
+	conflicting := newEvent(core.KindBillboardConfirmation, billboardPubkey, "org.attnprotocol:billboard-confirmation:other", "x1",
+		nostr.Tag{"e", c.match.ID, "", "match"},
+	)
+
+	tests := []struct {
+		name  string
+		setup []*nostr.Event
+		event *nostr.Event
+		want  error
+		state State
+	}{
+		{"confirmation before match", nil, c.billboardConfirmation, ErrIllegalTransition, StateAwaitingMatch},
+		{"marketplace confirmation before parties", []*nostr.Event{c.match, c.billboardConfirmation}, c.marketplaceConfirmation, ErrIllegalTransition, StateBillboardConfirmed},
+		{"payment before marketplace confirmation", []*nostr.Event{c.match, c.billboardConfirmation, c.attentionConfirmation}, c.paymentConfirmation, ErrIllegalTransition, StatePartiesConfirmed},
+		{"confirmation for another match", []*nostr.Event{c.match}, other.billboardConfirmation, ErrUnrelatedEvent, StateMatched},
+		{"wrong party", []*nostr.Event{c.match}, wrong_party, ErrWrongParty, StateMatched},
+		{"conflicting confirmation", []*nostr.Event{c.match, c.billboardConfirmation}, conflicting, ErrConflictingEvent, StateBillboardConfirmed},
+		{"second match", []*nostr.Event{c.match}, other.match, ErrConflictingEvent, StateMatched},
+		{"unsupported kind", nil, newEvent(core.KindPromotion, promoterPubkey, "org.attnprotocol:promotion:p1", "x1"), ErrUnsupportedKind, StateAwaitingMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			for _, event := range tt.setup {
+				if _, err := m.Apply(event); err != nil {
+					t.Fatalf("unexpected setup error: %v", err)
+				}
+			}
+
+			_, err := m.Apply(tt.event)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			var transition_error *TransitionError
+			if !errors.As(err, &transition_error) || transition_error.State != tt.state || transition_error.Kind != tt.event.Kind {
+				t.Errorf("unexpected transition error: %v", err)
+			}
+			if m.State() != tt.state {
+				t.Errorf("state changed to %s after rejected event", m.State())
+			}
+		})
+	}
+}
+
+func TestTrackerRoutesEvents(t *testing.T) {
+	first := newChain("x1")
+	second := newChain("x2")
+	tracker := NewTracker()
+
+	events := []*nostr.Event{
+		first.match, second.match,
+		second.attentionConfirmation, first.billboardConfirmation,
+		first.attentionConfirmation, first.marketplaceConfirmation,
+		first.paymentConfirmation,
+	}
+	for _, event := range events {
+		if _, _, err := tracker.Apply(event); err != nil {
+			t.Fatalf("unexpected error applying kind %d: %v", event.Kind, err)
+		}
+	}
+
+	if m := tracker.Get(first.match.ID); m == nil || m.State() != StatePaid {
+		t.Errorf("expected first match to be paid, got %+v", m)
+	}
+	if m := tracker.Get(second.match.ID); m == nil || m.State() != StateAttentionConfirmed {
+		t.Errorf("expected second match to be attention_confirmed, got %+v", m)
+	}
+	if pending := tracker.Pending(); len(pending) != 1 || pending[0].MatchEvent().ID != second.match.ID {
+		t.Errorf("expected only the second match to be pending, got %d", len(pending))
+	}
+}
+
+func TestTrackerUnknownMatch(t *testing.T) {
+	c := newChain("x1")
+	tracker := NewTracker()
+
+	if _, _, err := tracker.Apply(c.billboardConfirmation); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("expected ErrIllegalTransition, got %v", err)
+	}
+	if tracker.Get(c.match.ID) != nil {
+		t.Errorf("rejected confirmation should not start tracking a match")
+	}
+}
+
+func TestTrackerReturnsSnapshots(t *testing.T) {
+	c := newChain("x1")
+	tracker := NewTracker()
+
+	match, _, err := tracker.Apply(c.match)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Readers and writers may run concurrently; run with -race to check
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if m := tracker.Get(c.match.ID); m != nil {
+				_ = m.State()
+			}
+			for _, m := range tracker.Pending() {
+				_ = m.MissingParties()
+			}
+		}
+	}()
+	for _, event := range []*nostr.Event{c.billboardConfirmation, c.attentionConfirmation} {
+		if _, _, err := tracker.Apply(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+
+	if match.State() != StateMatched {
+		t.Errorf("expected the returned match to stay matched, got %s", match.State())
+	}
+	if _, err := match.Apply(c.billboardConfirmation); err != nil {
+		t.Fatal(err)
+	}
+	if m := tracker.Get(c.match.ID); m.State() != StatePartiesConfirmed {
+		t.Errorf("expected the tracker to be unaffected by the snapshot, got %s", m.State())
+	}
+}
+
+func TestTrackerPruneAndForget(t *testing.T) {
+	paid := newChain("x1")
+	open := newChain("x2")
+	tracker := NewTracker()
+
+	for _, event := range []*nostr.Event{
+		paid.match, paid.billboardConfirmation, paid.attentionConfirmation,
+		paid.marketplaceConfirmation, paid.paymentConfirmation,
+		open.match,
+	} {
+		if _, _, err := tracker.Apply(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if pruned := tracker.Prune(); pruned != 1 {
+		t.Fatalf("expected one terminal match pruned, got %d", pruned)
+	}
+	if tracker.Get(paid.match.ID) != nil || len(tracker.settlements) != 0 {
+		t.Error("expected the paid match and its settlement to be removed")
+	}
+	if tracker.Get(open.match.ID) == nil {
+		t.Error("expected the open match to stay tracked")
+	}
+
+	if !tracker.Forget(open.match.ID) || tracker.Forget(open.match.ID) {
+		t.Error("expected Forget to report whether the match was tracked")
+	}
+	if _, _, err := tracker.Apply(open.billboardConfirmation); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("expected a forgotten match's confirmation to be rejected, got %v", err)
+	}
+}
+
+func TestTrackerExpiresStalledMatches(t *testing.T) {
+	stalled := newChain("s1")
+	paid := newChain("s2")
+	tracker := NewTracker()
+
+	for _, event := range []*nostr.Event{
+		stalled.match, stalled.billboardConfirmation,
+		paid.match, paid.billboardConfirmation, paid.attentionConfirmation,
+		paid.marketplaceConfirmation, paid.paymentConfirmation,
+	} {
+		if _, _, err := tracker.Apply(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expired := tracker.Expire(870500); expired != 0 {
+		t.Fatalf("expected no match published before the cutoff, got %d expired", expired)
+	}
+	if expired := tracker.Expire(870600); expired != 1 {
+		t.Fatalf("expected the stalled match to expire, got %d", expired)
+	}
+	m := tracker.Get(stalled.match.ID)
+	if !m.Terminal() || m.Outcome() != OutcomeExpired || len(m.MissingParties()) != 0 {
+		t.Errorf("expected an expired terminal match, got %s / %s / %v", m.State(), m.Outcome(), m.MissingParties())
+	}
+	if tracker.Get(paid.match.ID).Outcome() != OutcomeCompleted {
+		t.Error("expected the paid match to keep its outcome")
+	}
+	if _, _, err := tracker.Apply(stalled.attentionConfirmation); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("expected a confirmation for an expired match to be rejected, got %v", err)
+	}
+	if len(tracker.Pending()) != 0 {
+		t.Errorf("expected no pending matches, got %d", len(tracker.Pending()))
+	}
+
+	if pruned := tracker.Prune(); pruned != 2 {
+		t.Errorf("expected the expired and paid matches pruned, got %d", pruned)
+	}
+}
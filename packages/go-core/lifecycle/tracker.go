@@ -0,0 +1,156 @@
+package lifecycle
+
+import (
+	"sync"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// Tracker routes lifecycle events to the Match they belong to.
+// It is safe for concurrent use. The matches it returns are snapshots: they do not
+// change as later events are applied, and applying events to them does not affect
+// the tracker.
+type Tracker struct {
+	mu      sync.Mutex
+	matches map[string]*Match
+
+	// marketplace confirmation ID → match event ID, for payment confirmations
+	// that omit the 'match' e tag
+	settlements map[string]string
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		matches:     make(map[string]*Match),
+		settlements: make(map[string]string),
+	}
+}
+
+// Apply routes the event to its match and applies it.
+// MATCH events start tracking a new match; confirmations for a match that has not
+// been seen are rejected with ErrIllegalTransition.
+func (t *Tracker) Apply(event *nostr.Event) (*Match, Transition, error) {
+	if event == nil {
+		return nil, Transition{}, core.ErrNilEvent
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	match_id := t.matchID(event)
+	match, ok := t.matches[match_id]
+	if !ok {
+		if event.Kind != core.KindMatch {
+			// Let an empty Match report unsupported kinds and out-of-order confirmations
+			_, err := New().Apply(event)
+			return nil, Transition{}, err
+		}
+		match = New()
+	}
+
+	transition, err := match.Apply(event)
+	if err != nil {
+		return match.snapshot(), transition, err
+	}
+
+	t.matches[match_id] = match
+	if event.Kind == core.KindMarketplaceConfirmation {
+		t.settlements[event.ID] = match_id
+	}
+	return match.snapshot(), transition, nil
+}
+
+// Get returns the tracked match with the given MATCH event ID, or nil.
+func (t *Tracker) Get(match_event_id string) *Match {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	match, ok := t.matches[match_event_id]
+	if !ok {
+		return nil
+	}
+	return match.snapshot()
+}
+
+// Pending returns the tracked matches that have not reached a terminal state.
+func (t *Tracker) Pending() []*Match {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var pending []*Match
+	for _, match := range t.matches {
+		if !match.Terminal() {
+			pending = append(pending, match.snapshot())
+		}
+	}
+	return pending
+}
+
+// Forget stops tracking the match with the given MATCH event ID and reports whether
+// it was tracked. Events for a forgotten match are rejected like those of an unseen one.
+func (t *Tracker) Forget(match_event_id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	match, ok := t.matches[match_event_id]
+	if !ok {
+		return false
+	}
+	t.forget(match_event_id, match)
+	return true
+}
+
+// Expire gives up on every pending match whose MATCH event was published before
+// before_block_height and returns how many expired. Call it with a cutoff such as the
+// current block height minus the blocks a match may take to settle, so matches that
+// stall are not kept forever.
+func (t *Tracker) Expire(before_block_height int64) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var expired int
+	for _, match := range t.matches {
+		if match.blockHeight < before_block_height && match.Expire() {
+			expired++
+		}
+	}
+	return expired
+}
+
+// Prune stops tracking every match that has reached a terminal state, paid or
+// expired, and returns how many were removed. Long-running processes should call
+// Expire and Prune periodically, since the tracker otherwise keeps every match it has seen.
+func (t *Tracker) Prune() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var pruned int
+	for match_id, match := range t.matches {
+		if match.Terminal() {
+			t.forget(match_id, match)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+// forget removes a match and its settlement entry. The caller must hold mu.
+func (t *Tracker) forget(match_id string, match *Match) {
+	delete(t.matches, match_id)
+	if match.marketplaceConfirmation != nil {
+		delete(t.settlements, match.marketplaceConfirmation.ID)
+	}
+}
+
+// matchID returns the MATCH event ID an event belongs to.
+func (t *Tracker) matchID(event *nostr.Event) string {
+	if event.Kind == core.KindMatch {
+		return event.ID
+	}
+	if match_id := getETagByMarker(event, "match"); match_id != "" {
+		return match_id
+	}
+	return t.settlements[getETagByMarker(event, "marketplace_confirmation")]
+}
@@ -0,0 +1,317 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// listCoordinateKinds maps each list type to the kind of coordinates it holds in 'a' tags.
+// Blocked promoter lists hold pubkeys only.
+var listCoordinateKinds = map[string]int{
+	NIP51BlockedPromotions:   KindPromotion,
+	NIP51BlockedPromoters:    0,
+	NIP51TrustedMarketplaces: KindMarketplace,
+	NIP51TrustedBillboards:   KindBillboard,
+}
+
+// listEntryTags maps each list type to the tag names it holds entries in.
+var listEntryTags = map[string]map[string]bool{
+	NIP51BlockedPromotions:   {"a": true, "e": true},
+	NIP51BlockedPromoters:    {"p": true},
+	NIP51TrustedMarketplaces: {"a": true, "p": true},
+	NIP51TrustedBillboards:   {"a": true, "p": true},
+}
+
+// AllListTypes returns the ATTN Protocol NIP-51 list types.
+func AllListTypes() []string {
+	return []string{
+		NIP51BlockedPromotions,
+		NIP51BlockedPromoters,
+		NIP51TrustedMarketplaces,
+		NIP51TrustedBillboards,
+	}
+}
+
+// IsListType returns true if the d tag is an ATTN Protocol NIP-51 list type.
+func IsListType(d_tag string) bool {
+	_, ok := listEntryTags[d_tag]
+	return ok
+}
+
+// IsListKind returns true if the kind is a NIP-51 set kind that can carry an ATTN Protocol list.
+func IsListKind(kind int) bool {
+	return kind == KindFollowSet || kind == KindBookmarkSet
+}
+
+// ListEntry is a single entry of a NIP-51 list: an 'a' coordinate, an 'e' event id
+// or a 'p' pubkey.
+type ListEntry struct {
+	Tag   string
+	Value string
+}
+
+// ParseListEntry parses a tag as an entry of the given list type.
+// It returns false for tags the list type does not hold entries in, such as 'd' and 't',
+// and an error for a malformed entry: a coordinate of the wrong kind, or an event id or
+// pubkey that is not 64 lowercase hex characters.
+func ParseListEntry(list_type string, tag nostr.Tag) (ListEntry, bool, error) {
+	entry_tags, ok := listEntryTags[list_type]
+	if !ok {
+		return ListEntry{}, false, fmt.Errorf("%w: %s", ErrUnknownListType, list_type)
+	}
+	if len(tag) < 2 || !entry_tags[tag[0]] {
+		return ListEntry{}, false, nil
+	}
+
+	entry := ListEntry{Tag: tag[0], Value: tag[1]}
+	switch entry.Tag {
+	case "a":
+		coordinate, err := ParseCoordinate(entry.Value)
+		if err != nil {
+			return ListEntry{}, true, err
+		}
+		if coordinate.Kind != listCoordinateKinds[list_type] {
+			return ListEntry{}, true, fmt.Errorf("%s lists hold kind %d coordinates, got kind %d", list_type, listCoordinateKinds[list_type], coordinate.Kind)
+		}
+		if err := coordinate.Validate(); err != nil {
+			return ListEntry{}, true, err
+		}
+	case "e":
+		if !nostr.IsValid32ByteHex(entry.Value) {
+			return ListEntry{}, true, fmt.Errorf("event id must be 64 lowercase hex characters: %s", entry.Value)
+		}
+	case "p":
+		if !nostr.IsValid32ByteHex(entry.Value) {
+			return ListEntry{}, true, fmt.Errorf("pubkey must be 64 lowercase hex characters: %s", entry.Value)
+		}
+	}
+
+	return entry, true, nil
+}
+
+// List is a decoded NIP-51 preference list (kind 30000 or 30003) with an ATTN Protocol
+// list type. It holds the public entries from tags; content may instead carry NIP-44
+// encrypted private entries, which ParseList does not read.
+type List struct {
+	// Event is the raw Nostr event that was decoded.
+	Event *nostr.Event
+
+	// Type is the list type from the 'd' tag (one of the NIP51* constants).
+	Type string
+
+	// Coordinate is the list's own address (<kind>:<pubkey>:<d>), as ATTENTION events reference it.
+	Coordinate string
+
+	// BlockHeight is the block height from the 't' tag, or 0 if the list has none.
+	BlockHeight int64
+
+	// Description is the description from JSON content or, when content holds
+	// encrypted private entries, from the NIP-51 'description' tag.
+	Description string
+
+	// Coordinates contains the 'a' entries.
+	Coordinates []string
+
+	// EventIDs contains the 'e' entries.
+	EventIDs []string
+
+	// Pubkeys contains the 'p' entries.
+	Pubkeys []string
+
+	entries map[ListEntry]bool
+}
+
+// ParseList decodes a NIP-51 list event carrying an ATTN Protocol list type.
+// Malformed entries are skipped rather than failing the whole list, so one bad tag
+// does not unblock everything else; use validation.ValidateListEvent to reject them.
+func ParseList(event *nostr.Event) (*List, error) {
+	if event == nil {
+		return nil, ErrNilEvent
+	}
+	if !IsListKind(event.Kind) {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedKind, event.Kind)
+	}
+
+	d_tag := tagValue(event, "d")
+	if d_tag == "" {
+		return nil, ErrMissingDTag
+	}
+	if !IsListType(d_tag) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownListType, d_tag)
+	}
+
+	list := &List{
+		Event:      event,
+		Type:       d_tag,
+		Coordinate: Coordinate{Kind: event.Kind, Pubkey: event.PubKey, DTag: d_tag}.String(),
+		entries:    make(map[ListEntry]bool),
+	}
+
+	if value := tagValue(event, "t"); value != "" {
+		block_height, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidBlockHeight
+		}
+		list.BlockHeight = block_height
+	}
+
+	// Content is optional and may be encrypted, so only a JSON object is read
+	var content struct {
+		Description string `json:"description"`
+	}
+	if json.Unmarshal([]byte(event.Content), &content) == nil {
+		list.Description = content.Description
+	}
+	if list.Description == "" {
+		list.Description = tagValue(event, "description")
+	}
+
+	for _, tag := range event.Tags {
+		entry, ok, err := ParseListEntry(d_tag, tag)
+		if !ok || err != nil {
+			continue
+		}
+		list.Add(entry)
+	}
+
+	return list, nil
+}
+
+// Add adds an entry to the list, ignoring duplicates.
+func (l *List) Add(entry ListEntry) {
+	if l.entries == nil {
+		l.entries = make(map[ListEntry]bool)
+	}
+	if l.entries[entry] {
+		return
+	}
+	l.entries[entry] = true
+
+	switch entry.Tag {
+	case "a":
+		l.Coordinates = append(l.Coordinates, entry.Value)
+	case "e":
+		l.EventIDs = append(l.EventIDs, entry.Value)
+	case "p":
+		l.Pubkeys = append(l.Pubkeys, entry.Value)
+	}
+}
+
+// HasCoordinate returns true if the list has an 'a' entry for the coordinate.
+func (l *List) HasCoordinate(coordinate string) bool {
+	return l != nil && l.entries[ListEntry{Tag: "a", Value: coordinate}]
+}
+
+// HasEventID returns true if the list has an 'e' entry for the event id.
+func (l *List) HasEventID(event_id string) bool {
+	return l != nil && l.entries[ListEntry{Tag: "e", Value: event_id}]
+}
+
+// HasPubkey returns true if the list has a 'p' entry for the pubkey.
+func (l *List) HasPubkey(pubkey string) bool {
+	return l != nil && l.entries[ListEntry{Tag: "p", Value: pubkey}]
+}
+
+// Empty returns true if the list has no entries. A nil list is empty.
+func (l *List) Empty() bool {
+	return l == nil || len(l.entries) == 0
+}
+
+// BlockedPromotionsList is a decoded blocked promotions list (org.attnprotocol:promotion:blocked).
+type BlockedPromotionsList struct {
+	List
+}
+
+// ParseBlockedPromotionsList decodes a blocked promotions list.
+func ParseBlockedPromotionsList(event *nostr.Event) (*BlockedPromotionsList, error) {
+	list, err := parseTypedList(event, NIP51BlockedPromotions)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockedPromotionsList{List: *list}, nil
+}
+
+// Blocks returns true if the promotion is blocked by coordinate or by event id.
+// A nil list blocks nothing.
+func (l *BlockedPromotionsList) Blocks(promotion_coordinate string, event_id string) bool {
+	if l == nil {
+		return false
+	}
+	return l.HasCoordinate(promotion_coordinate) || l.HasEventID(event_id)
+}
+
+// BlockedPromotersList is a decoded blocked promoters list (org.attnprotocol:promoter:blocked).
+type BlockedPromotersList struct {
+	List
+}
+
+// ParseBlockedPromotersList decodes a blocked promoters list.
+func ParseBlockedPromotersList(event *nostr.Event) (*BlockedPromotersList, error) {
+	list, err := parseTypedList(event, NIP51BlockedPromoters)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockedPromotersList{List: *list}, nil
+}
+
+// Blocks returns true if the promoter pubkey is blocked. A nil list blocks nothing.
+func (l *BlockedPromotersList) Blocks(pubkey string) bool {
+	if l == nil {
+		return false
+	}
+	return l.HasPubkey(pubkey)
+}
+
+// TrustList is a decoded trusted marketplaces or trusted billboards list.
+// Per ATTN-01, trust is opt-in: an empty or missing list trusts no one, a 'p' entry
+// trusts every instance its pubkey operates and an 'a' entry trusts one instance.
+type TrustList struct {
+	List
+}
+
+// ParseTrustedMarketplacesList decodes a trusted marketplaces list.
+func ParseTrustedMarketplacesList(event *nostr.Event) (*TrustList, error) {
+	list, err := parseTypedList(event, NIP51TrustedMarketplaces)
+	if err != nil {
+		return nil, err
+	}
+	return &TrustList{List: *list}, nil
+}
+
+// ParseTrustedBillboardsList decodes a trusted billboards list.
+func ParseTrustedBillboardsList(event *nostr.Event) (*TrustList, error) {
+	list, err := parseTypedList(event, NIP51TrustedBillboards)
+	if err != nil {
+		return nil, err
+	}
+	return &TrustList{List: *list}, nil
+}
+
+// Trusts returns true if the marketplace or billboard coordinate is trusted, either
+// directly or through its operator pubkey. Coordinates of another kind are never
+// trusted, and a nil list trusts no one.
+func (l *TrustList) Trusts(coordinate string) bool {
+	if l == nil {
+		return false
+	}
+	parsed, err := ParseCoordinate(coordinate)
+	if err != nil || parsed.Kind != listCoordinateKinds[l.Type] {
+		return false
+	}
+	return l.HasCoordinate(coordinate) || l.HasPubkey(parsed.Pubkey)
+}
+
+// parseTypedList decodes a list and checks its type.
+func parseTypedList(event *nostr.Event, list_type string) (*List, error) {
+	list, err := ParseList(event)
+	if err != nil {
+		return nil, err
+	}
+	if list.Type != list_type {
+		return nil, fmt.Errorf("%w: expected %s, got %s", ErrListTypeMismatch, list_type, list.Type)
+	}
+	return list, nil
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+var otherPubkey = strings.Repeat("b2", 32)
+
+func TestParseBlockedPromotionsList(t *testing.T) {
+	promotion := "38388:" + otherPubkey + ":org.attnprotocol:promotion:p1"
+	event_id := strings.Repeat("c3", 32)
+	event := &nostr.Event{
+		Kind:    KindFollowSet,
+		PubKey:  testPubkey,
+		Content: `{"description":"Promotions I don't want to see"}`,
+		Tags: nostr.Tags{
+			{"d", NIP51BlockedPromotions},
+			{"t", "862626"},
+			{"a", promotion, "wss://relay.example.com"},
+			{"a", promotion},
+			{"a", "38188:" + otherPubkey + ":org.attnprotocol:marketplace:m1"},
+			{"e", event_id, "wss://relay.example.com"},
+			{"e", "not-hex"},
+			{"p", otherPubkey},
+		},
+	}
+
+	list, err := ParseBlockedPromotionsList(event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Coordinate != "30000:"+testPubkey+":"+NIP51BlockedPromotions {
+		t.Errorf("unexpected coordinate: %s", list.Coordinate)
+	}
+	if list.BlockHeight != 862626 || list.Description != "Promotions I don't want to see" {
+		t.Errorf("unexpected header: %d %q", list.BlockHeight, list.Description)
+	}
+	if len(list.Coordinates) != 1 || len(list.EventIDs) != 1 || len(list.Pubkeys) != 0 {
+		t.Errorf("expected one coordinate and one event id, got %v %v %v", list.Coordinates, list.EventIDs, list.Pubkeys)
+	}
+
+	if !list.Blocks(promotion, "") || !list.Blocks("", event_id) {
+		t.Error("expected listed promotion to be blocked")
+	}
+	if list.Blocks("38388:"+otherPubkey+":org.attnprotocol:promotion:p2", strings.Repeat("d4", 32)) {
+		t.Error("expected unlisted promotion not to be blocked")
+	}
+
+	var missing *BlockedPromotionsList
+	if missing.Blocks(promotion, event_id) {
+		t.Error("expected nil list to block nothing")
+	}
+}
+
+func TestParseBlockedPromotersList(t *testing.T) {
+	event := &nostr.Event{
+		Kind:   KindBookmarkSet,
+		PubKey: testPubkey,
+		Tags: nostr.Tags{
+			{"d", NIP51BlockedPromoters},
+			{"p", otherPubkey},
+		},
+	}
+
+	list, err := ParseBlockedPromotersList(event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.BlockHeight != 0 || list.Description != "" {
+		t.Errorf("expected no block height or description, got %d %q", list.BlockHeight, list.Description)
+	}
+	if !list.Blocks(otherPubkey) || list.Blocks(testPubkey) {
+		t.Error("expected only the listed promoter to be blocked")
+	}
+
+	// Lists with encrypted private entries carry their description in a tag
+	event.Tags = append(event.Tags, nostr.Tag{"description", "Spammers"})
+	event.Content = "AqI1ZW5jcnlwdGVk"
+	list, err = ParseBlockedPromotersList(event)
+	if err != nil || list.Description != "Spammers" {
+		t.Errorf("expected the description tag, got %q (%v)", list.Description, err)
+	}
+}
+
+func TestTrustList(t *testing.T) {
+	trusted_instance := "38188:" + testPubkey + ":org.attnprotocol:marketplace:m1"
+	event := &nostr.Event{
+		Kind:    KindFollowSet,
+		PubKey:  testPubkey,
+		Content: "encrypted-private-entries",
+		Tags: nostr.Tags{
+			{"d", NIP51TrustedMarketplaces},
+			{"t", "862626"},
+			{"a", trusted_instance},
+			{"p", otherPubkey},
+		},
+	}
+
+	list, err := ParseTrustedMarketplacesList(event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		coordinate string
+		trusted    bool
+	}{
+		{trusted_instance, true},
+		{"38188:" + testPubkey + ":org.attnprotocol:marketplace:m2", false},
+		{"38188:" + otherPubkey + ":org.attnprotocol:marketplace:any", true},
+		{"38288:" + otherPubkey + ":org.attnprotocol:billboard:any", false},
+		{"not-a-coordinate", false},
+	}
+	for _, tt := range tests {
+		if got := list.Trusts(tt.coordinate); got != tt.trusted {
+			t.Errorf("Trusts(%s) = %v, want %v", tt.coordinate, got, tt.trusted)
+		}
+	}
+
+	empty, err := ParseTrustedBillboardsList(&nostr.Event{Kind: KindFollowSet, PubKey: testPubkey, Tags: nostr.Tags{{"d", NIP51TrustedBillboards}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !empty.Empty() || empty.Trusts("38288:"+otherPubkey+":org.attnprotocol:billboard:b1") {
+		t.Error("expected empty list to trust no one")
+	}
+
+	var missing *TrustList
+	if missing.Trusts(trusted_instance) {
+		t.Error("expected missing list to trust no one")
+	}
+}
+
+func TestParseListErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *nostr.Event
+		want  error
+	}{
+		{"nil event", nil, ErrNilEvent},
+		{"wrong kind", &nostr.Event{Kind: KindAttention, Tags: nostr.Tags{{"d", NIP51BlockedPromoters}}}, ErrUnsupportedKind},
+		{"missing d tag", &nostr.Event{Kind: KindFollowSet}, ErrMissingDTag},
+		{"unknown list type", &nostr.Event{Kind: KindFollowSet, Tags: nostr.Tags{{"d", "org.attnprotocol:video:blocked"}}}, ErrUnknownListType},
+		{"bad block height", &nostr.Event{Kind: KindFollowSet, Tags: nostr.Tags{{"d", NIP51BlockedPromoters}, {"t", "abc"}}}, ErrInvalidBlockHeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseList(tt.event); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	event := &nostr.Event{Kind: KindFollowSet, Tags: nostr.Tags{{"d", NIP51BlockedPromoters}}}
+	if _, err := ParseTrustedBillboardsList(event); !errors.Is(err, ErrListTypeMismatch) {
+		t.Errorf("expected ErrListTypeMismatch, got %v", err)
+	}
+}
+
+func TestParseListEntry(t *testing.T) {
+	tests := []struct {
+		list_type string
+		tag       nostr.Tag
+		ok        bool
+		valid     bool
+	}{
+		{NIP51TrustedBillboards, nostr.Tag{"a", "38288:" + testPubkey + ":org.attnprotocol:billboard:b1"}, true, true},
+		{NIP51TrustedBillboards, nostr.Tag{"a", "38188:" + testPubkey + ":org.attnprotocol:marketplace:m1"}, true, false},
+		{NIP51TrustedBillboards, nostr.Tag{"a", "38288:" + testPubkey + ":org.attnprotocol:promotion:p1"}, true, false},
+		{NIP51TrustedBillboards, nostr.Tag{"p", strings.ToUpper(testPubkey)}, true, false},
+		{NIP51TrustedBillboards, nostr.Tag{"e", testPubkey}, false, true},
+		{NIP51BlockedPromoters, nostr.Tag{"a", "38388:" + testPubkey + ":org.attnprotocol:promotion:p1"}, false, true},
+		{NIP51BlockedPromotions, nostr.Tag{"t", "870500"}, false, true},
+		{NIP51BlockedPromotions, nostr.Tag{"e"}, false, true},
+	}
+	for _, tt := range tests {
+		_, ok, err := ParseListEntry(tt.list_type, tt.tag)
+		if ok != tt.ok || (err == nil) != tt.valid {
+			t.Errorf("ParseListEntry(%s, %v) = %v, %v; want ok %v, valid %v", tt.list_type, tt.tag, ok, err, tt.ok, tt.valid)
+		}
+	}
+}
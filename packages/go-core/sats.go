@@ -0,0 +1,221 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Sats is an amount in satoshis. Valid amounts lie in [0, MaxSats].
+//
+// Arithmetic methods are checked: they return ErrNegativeAmount or ErrAmountOverflow
+// instead of wrapping or going negative, so fee and payout math cannot silently
+// produce an amount no one could pay.
+type Sats int64
+
+// MilliSats is an amount in millisatoshis, the unit of Lightning invoices.
+// Valid amounts lie in [0, MaxMilliSats].
+type MilliSats int64
+
+const (
+	// SatsPerBitcoin is the number of satoshis in one bitcoin.
+	SatsPerBitcoin = 100_000_000
+
+	// MilliSatsPerSat is the number of millisatoshis in one satoshi.
+	MilliSatsPerSat = 1_000
+
+	// MaxSats is the largest valid amount: the 21 million bitcoin supply.
+	// Anything larger is absurd and rejected before it reaches arithmetic or storage.
+	MaxSats Sats = 21_000_000 * SatsPerBitcoin
+
+	// MaxMilliSats is MaxSats in millisatoshis. It fits in an int64.
+	MaxMilliSats MilliSats = MilliSats(MaxSats) * MilliSatsPerSat
+)
+
+// NewSats returns n as a validated Sats amount.
+func NewSats(n int64) (Sats, error) {
+	s := Sats(n)
+	return s, s.Validate()
+}
+
+// Validate checks the amount is within [0, MaxSats].
+func (s Sats) Validate() error {
+	return validateAmount(int64(s), int64(MaxSats), "sats")
+}
+
+// Add returns s + other.
+func (s Sats) Add(other Sats) (Sats, error) {
+	sum, err := addAmounts(int64(s), int64(other), int64(MaxSats), "sats")
+	return Sats(sum), err
+}
+
+// Sub returns s - other. A negative result is an error.
+func (s Sats) Sub(other Sats) (Sats, error) {
+	difference, err := subAmounts(int64(s), int64(other), int64(MaxSats), "sats")
+	return Sats(difference), err
+}
+
+// Mul returns s * n, for example a per-match fee times a match count.
+func (s Sats) Mul(n int64) (Sats, error) {
+	product, err := mulAmount(int64(s), n, int64(MaxSats), "sats")
+	return Sats(product), err
+}
+
+// MilliSats converts the amount to millisatoshis.
+func (s Sats) MilliSats() (MilliSats, error) {
+	if err := s.Validate(); err != nil {
+		return 0, err
+	}
+	return MilliSats(s) * MilliSatsPerSat, nil
+}
+
+// String formats the amount as "<n> sats".
+func (s Sats) String() string {
+	return strconv.FormatInt(int64(s), 10) + " sats"
+}
+
+// MarshalJSON encodes the amount as a JSON integer.
+func (s Sats) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(s), 10)), nil
+}
+
+// UnmarshalJSON decodes a JSON integer and rejects negative or absurd amounts.
+func (s *Sats) UnmarshalJSON(data []byte) error {
+	// null leaves the amount unchanged, as encoding/json does for other types
+	if string(data) == "null" {
+		return nil
+	}
+	n, err := unmarshalAmount(data, int64(MaxSats), "sats")
+	if err != nil {
+		return err
+	}
+	*s = Sats(n)
+	return nil
+}
+
+// NewMilliSats returns n as a validated MilliSats amount.
+func NewMilliSats(n int64) (MilliSats, error) {
+	m := MilliSats(n)
+	return m, m.Validate()
+}
+
+// Validate checks the amount is within [0, MaxMilliSats].
+func (m MilliSats) Validate() error {
+	return validateAmount(int64(m), int64(MaxMilliSats), "msats")
+}
+
+// Add returns m + other.
+func (m MilliSats) Add(other MilliSats) (MilliSats, error) {
+	sum, err := addAmounts(int64(m), int64(other), int64(MaxMilliSats), "msats")
+	return MilliSats(sum), err
+}
+
+// Sub returns m - other. A negative result is an error.
+func (m MilliSats) Sub(other MilliSats) (MilliSats, error) {
+	difference, err := subAmounts(int64(m), int64(other), int64(MaxMilliSats), "msats")
+	return MilliSats(difference), err
+}
+
+// Mul returns m * n.
+func (m MilliSats) Mul(n int64) (MilliSats, error) {
+	product, err := mulAmount(int64(m), n, int64(MaxMilliSats), "msats")
+	return MilliSats(product), err
+}
+
+// Sats splits the amount into whole satoshis and the millisatoshis left over,
+// so rounding is always explicit. Both parts are zero for an invalid amount.
+func (m MilliSats) Sats() (Sats, MilliSats) {
+	if m.Validate() != nil {
+		return 0, 0
+	}
+	return Sats(m / MilliSatsPerSat), m % MilliSatsPerSat
+}
+
+// String formats the amount as "<n> msats".
+func (m MilliSats) String() string {
+	return strconv.FormatInt(int64(m), 10) + " msats"
+}
+
+// MarshalJSON encodes the amount as a JSON integer.
+func (m MilliSats) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(m), 10)), nil
+}
+
+// UnmarshalJSON decodes a JSON integer and rejects negative or absurd amounts.
+func (m *MilliSats) UnmarshalJSON(data []byte) error {
+	// null leaves the amount unchanged, as encoding/json does for other types
+	if string(data) == "null" {
+		return nil
+	}
+	n, err := unmarshalAmount(data, int64(MaxMilliSats), "msats")
+	if err != nil {
+		return err
+	}
+	*m = MilliSats(n)
+	return nil
+}
+
+func validateAmount(n int64, max int64, unit string) error {
+	if n < 0 {
+		return fmt.Errorf("%w: %d %s", ErrNegativeAmount, n, unit)
+	}
+	if n > max {
+		return fmt.Errorf("%w: %d %s", ErrAmountOverflow, n, unit)
+	}
+	return nil
+}
+
+// Operands are validated first, so sums and differences of two valid amounts
+// cannot overflow int64.
+
+func addAmounts(a int64, b int64, max int64, unit string) (int64, error) {
+	if err := validateAmount(a, max, unit); err != nil {
+		return 0, err
+	}
+	if err := validateAmount(b, max, unit); err != nil {
+		return 0, err
+	}
+	if err := validateAmount(a+b, max, unit); err != nil {
+		return 0, err
+	}
+	return a + b, nil
+}
+
+func subAmounts(a int64, b int64, max int64, unit string) (int64, error) {
+	if err := validateAmount(a, max, unit); err != nil {
+		return 0, err
+	}
+	if err := validateAmount(b, max, unit); err != nil {
+		return 0, err
+	}
+	if err := validateAmount(a-b, max, unit); err != nil {
+		return 0, err
+	}
+	return a - b, nil
+}
+
+func mulAmount(a int64, n int64, max int64, unit string) (int64, error) {
+	if err := validateAmount(a, max, unit); err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("%w: multiplier %d", ErrNegativeAmount, n)
+	}
+	// Compare by division so the check itself cannot overflow
+	if n != 0 && a > max/n {
+		return 0, fmt.Errorf("%w: %d %s * %d", ErrAmountOverflow, a, unit, n)
+	}
+	return a * n, nil
+}
+
+// unmarshalAmount decodes a JSON integer amount and validates it.
+func unmarshalAmount(data []byte, max int64, unit string) (int64, error) {
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return 0, err
+	}
+	if err := validateAmount(n, max, unit); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
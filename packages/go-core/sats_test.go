@@ -0,0 +1,98 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSatsArithmetic(t *testing.T) {
+	sum, err := Sats(3000).Add(150)
+	if err != nil || sum != 3150 {
+		t.Errorf("expected 3150, got %d, %v", sum, err)
+	}
+	difference, err := sum.Sub(3150)
+	if err != nil || difference != 0 {
+		t.Errorf("expected 0, got %d, %v", difference, err)
+	}
+	product, err := Sats(10).Mul(3)
+	if err != nil || product != 30 {
+		t.Errorf("expected 30, got %d, %v", product, err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		run  func() (Sats, error)
+	}{
+		{"sub below zero", ErrNegativeAmount, func() (Sats, error) { return Sats(100).Sub(101) }},
+		{"add past supply", ErrAmountOverflow, func() (Sats, error) { return MaxSats.Add(1) }},
+		{"mul past supply", ErrAmountOverflow, func() (Sats, error) { return Sats(SatsPerBitcoin).Mul(21_000_001) }},
+		{"mul past int64", ErrAmountOverflow, func() (Sats, error) { return MaxSats.Mul(1 << 40) }},
+		{"negative multiplier", ErrNegativeAmount, func() (Sats, error) { return Sats(1).Mul(-1) }},
+		{"negative operand", ErrNegativeAmount, func() (Sats, error) { return Sats(-5).Add(10) }},
+		{"absurd operand", ErrAmountOverflow, func() (Sats, error) { return Sats(1 << 62).Sub(1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.run()
+			if !errors.Is(err, tt.err) || result != 0 {
+				t.Errorf("expected %v and 0, got %d, %v", tt.err, result, err)
+			}
+		})
+	}
+}
+
+func TestSatsConversion(t *testing.T) {
+	msats, err := Sats(21).MilliSats()
+	if err != nil || msats != 21_000 {
+		t.Errorf("expected 21000 msats, got %d, %v", msats, err)
+	}
+	if msats, err := MaxSats.MilliSats(); err != nil || msats != MaxMilliSats {
+		t.Errorf("expected MaxMilliSats, got %d, %v", msats, err)
+	}
+	if _, err := Sats(-1).MilliSats(); !errors.Is(err, ErrNegativeAmount) {
+		t.Errorf("expected ErrNegativeAmount, got %v", err)
+	}
+
+	sats, remainder := MilliSats(21_999).Sats()
+	if sats != 21 || remainder != 999 {
+		t.Errorf("expected 21 sats and 999 msats, got %d and %d", sats, remainder)
+	}
+
+	if _, err := MaxMilliSats.Add(1); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("expected ErrAmountOverflow, got %v", err)
+	}
+	if _, err := NewMilliSats(-1); !errors.Is(err, ErrNegativeAmount) {
+		t.Errorf("expected ErrNegativeAmount, got %v", err)
+	}
+}
+
+func TestSatsJSON(t *testing.T) {
+	data, err := json.Marshal(PromotionData{Bid: 5000})
+	if err != nil || string(data) != `{"bid":5000}` {
+		t.Fatalf("unexpected encoding %s, %v", data, err)
+	}
+
+	var promotion PromotionData
+	if err := json.Unmarshal([]byte(`{"bid":5000}`), &promotion); err != nil || promotion.Bid != 5000 {
+		t.Errorf("expected bid 5000, got %d, %v", promotion.Bid, err)
+	}
+	if err := json.Unmarshal([]byte(`{"bid":null}`), &promotion); err != nil || promotion.Bid != 5000 {
+		t.Errorf("expected null to leave bid unchanged, got %d, %v", promotion.Bid, err)
+	}
+
+	invalid := map[string]error{
+		`{"bid":-1}`:                   ErrNegativeAmount,
+		`{"bid":2100000000000001}`:     ErrAmountOverflow,
+		`{"bid":99999999999999999999}`: nil,
+		`{"bid":1.5}`:                  nil,
+		`{"bid":"5000"}`:               nil,
+	}
+	for content, want := range invalid {
+		err := json.Unmarshal([]byte(content), &PromotionData{})
+		if err == nil || (want != nil && !errors.Is(err, want)) {
+			t.Errorf("%s: expected error %v, got %v", content, want, err)
+		}
+	}
+}
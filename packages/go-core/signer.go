@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip44"
+)
+
+// Signer signs events on behalf of a single Nostr identity.
+//
+// Implementations may hold the key in memory (KeySigner) or forward requests to a
+// remote signer such as a NIP-46 bunker, so callers should not assume signing is
+// cheap or infallible and should pass a context that bounds it.
+type Signer interface {
+	// GetPublicKey returns the signer's hex-encoded public key.
+	GetPublicKey(ctx context.Context) (string, error)
+
+	// SignEvent sets the event's pubkey, id and signature.
+	SignEvent(ctx context.Context, event *nostr.Event) error
+}
+
+// Encrypter is implemented by signers that can NIP-44 encrypt and decrypt on behalf of
+// their identity. It is optional; use Encrypt and Decrypt to call it through a Signer.
+type Encrypter interface {
+	// Encrypt NIP-44 encrypts plaintext for the recipient pubkey.
+	Encrypt(ctx context.Context, recipient_pubkey string, plaintext string) (string, error)
+
+	// Decrypt decrypts a NIP-44 payload received from the sender pubkey.
+	Decrypt(ctx context.Context, sender_pubkey string, ciphertext string) (string, error)
+}
+
+// Encrypt NIP-44 encrypts plaintext for the recipient pubkey with the signer's key.
+// Returns ErrEncryptionUnsupported if the signer does not implement Encrypter.
+func Encrypt(ctx context.Context, signer Signer, recipient_pubkey string, plaintext string) (string, error) {
+	encrypter, ok := signer.(Encrypter)
+	if !ok {
+		return "", ErrEncryptionUnsupported
+	}
+	return encrypter.Encrypt(ctx, recipient_pubkey, plaintext)
+}
+
+// Decrypt decrypts a NIP-44 payload from the sender pubkey with the signer's key.
+// Returns ErrEncryptionUnsupported if the signer does not implement Encrypter.
+func Decrypt(ctx context.Context, signer Signer, sender_pubkey string, ciphertext string) (string, error) {
+	encrypter, ok := signer.(Encrypter)
+	if !ok {
+		return "", ErrEncryptionUnsupported
+	}
+	return encrypter.Decrypt(ctx, sender_pubkey, ciphertext)
+}
+
+// KeySigner is an in-memory Signer and Encrypter backed by a private key.
+// Prefer a remote signer for long-running services whose keys should not live in
+// process memory.
+type KeySigner struct {
+	privateKey string
+	publicKey  string
+
+	mu               sync.Mutex
+	conversationKeys map[string][32]byte
+}
+
+// NewKeySigner creates an in-memory signer from a hex-encoded private key.
+// Returns ErrInvalidPrivateKey if the key is not 32 bytes of hex within the curve order.
+func NewKeySigner(private_key string) (*KeySigner, error) {
+	if !nostr.IsValid32ByteHex(private_key) {
+		return nil, ErrInvalidPrivateKey
+	}
+	sk_bytes, err := hex.DecodeString(private_key)
+	if err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	var scalar btcec.ModNScalar
+	if overflow := scalar.SetByteSlice(sk_bytes); overflow || scalar.IsZero() {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	pk, err := nostr.GetPublicKey(private_key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+
+	return &KeySigner{
+		privateKey:       private_key,
+		publicKey:        pk,
+		conversationKeys: make(map[string][32]byte),
+	}, nil
+}
+
+// GetPublicKey returns the signer's hex-encoded public key.
+func (s *KeySigner) GetPublicKey(ctx context.Context) (string, error) {
+	return s.publicKey, nil
+}
+
+// SignEvent sets the event's pubkey, id and signature.
+func (s *KeySigner) SignEvent(ctx context.Context, event *nostr.Event) error {
+	if event == nil {
+		return ErrNilEvent
+	}
+	return event.Sign(s.privateKey)
+}
+
+// Encrypt NIP-44 encrypts plaintext for the recipient pubkey.
+func (s *KeySigner) Encrypt(ctx context.Context, recipient_pubkey string, plaintext string) (string, error) {
+	conversation_key, err := s.conversationKey(recipient_pubkey)
+	if err != nil {
+		return "", err
+	}
+	return nip44.Encrypt(plaintext, conversation_key)
+}
+
+// Decrypt decrypts a NIP-44 payload received from the sender pubkey.
+func (s *KeySigner) Decrypt(ctx context.Context, sender_pubkey string, ciphertext string) (string, error) {
+	conversation_key, err := s.conversationKey(sender_pubkey)
+	if err != nil {
+		return "", err
+	}
+	return nip44.Decrypt(ciphertext, conversation_key)
+}
+
+// conversationKey returns the NIP-44 conversation key shared with pubkey, caching it
+// since deriving it costs an ECDH operation.
+func (s *KeySigner) conversationKey(pubkey string) ([32]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.conversationKeys[pubkey]; ok {
+		return key, nil
+	}
+	key, err := nip44.GenerateConversationKey(pubkey, s.privateKey)
+	if err != nil {
+		return key, err
+	}
+	s.conversationKeys[pubkey] = key
+	return key, nil
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+func TestNewKeySignerRejectsInvalidKeys(t *testing.T) {
+	for _, private_key := range []string{
+		"",
+		"not-hex",
+		strings.Repeat("01", 31),
+		strings.Repeat("00", 32),
+		strings.Repeat("ff", 32),
+	} {
+		if _, err := NewKeySigner(private_key); !errors.Is(err, ErrInvalidPrivateKey) {
+			t.Errorf("NewKeySigner(%q) = %v, want ErrInvalidPrivateKey", private_key, err)
+		}
+	}
+}
+
+func TestKeySignerSignEvent(t *testing.T) {
+	ctx := context.Background()
+	signer, err := NewKeySigner(strings.Repeat("01", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubkey, err := signer.GetPublicKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := &nostr.Event{Kind: KindPromotion, Content: "{}"}
+	if err := signer.SignEvent(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	if event.PubKey != pubkey {
+		t.Errorf("expected pubkey %s, got %s", pubkey, event.PubKey)
+	}
+	if ok, err := event.CheckSignature(); !ok || err != nil {
+		t.Errorf("expected valid signature, got %v %v", ok, err)
+	}
+
+	if err := signer.SignEvent(ctx, nil); !errors.Is(err, ErrNilEvent) {
+		t.Errorf("expected ErrNilEvent, got %v", err)
+	}
+}
+
+// signOnly hides a signer's Encrypter implementation.
+type signOnly struct{ Signer }
+
+func TestEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	alice, err := NewKeySigner(strings.Repeat("01", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := NewKeySigner(strings.Repeat("02", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice_pubkey, _ := alice.GetPublicKey(ctx)
+	bob_pubkey, _ := bob.GetPublicKey(ctx)
+
+	ciphertext, err := Encrypt(ctx, alice, bob_pubkey, "private entries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := Decrypt(ctx, bob, alice_pubkey, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext != "private entries" {
+		t.Errorf("unexpected plaintext: %q", plaintext)
+	}
+
+	if _, err := Encrypt(ctx, signOnly{alice}, bob_pubkey, "x"); !errors.Is(err, ErrEncryptionUnsupported) {
+		t.Errorf("expected ErrEncryptionUnsupported, got %v", err)
+	}
+	if _, err := Decrypt(ctx, signOnly{bob}, alice_pubkey, ciphertext); !errors.Is(err, ErrEncryptionUnsupported) {
+		t.Errorf("expected ErrEncryptionUnsupported, got %v", err)
+	}
+}
@@ -42,14 +42,15 @@ type CityBlockData struct {
 }
 
 // MarketplaceData represents MARKETPLACE event content (kind 38188).
+// The fee fields have no omitempty: ATTN-01 requires them, and zero is a valid fee.
 type MarketplaceData struct {
 	Name                 string `json:"name,omitempty"`
 	Description          string `json:"description,omitempty"`
 	AdminPubkey          string `json:"admin_pubkey,omitempty"`
 	MinDuration          int64  `json:"min_duration,omitempty"`
 	MaxDuration          int64  `json:"max_duration,omitempty"`
-	MatchFeeSats         int64  `json:"match_fee_sats,omitempty"`
-	ConfirmationFeeSats  int64  `json:"confirmation_fee_sats,omitempty"`
+	MatchFeeSats         Sats   `json:"match_fee_sats"`
+	ConfirmationFeeSats  Sats   `json:"confirmation_fee_sats"`
 	RefMarketplacePubkey string `json:"ref_marketplace_pubkey,omitempty"`
 	RefMarketplaceID     string `json:"ref_marketplace_id,omitempty"`
 	RefClockPubkey       string `json:"ref_clock_pubkey,omitempty"`
@@ -61,10 +62,11 @@ type MarketplaceData struct {
 }
 
 // BillboardData represents BILLBOARD event content (kind 38288).
+// ConfirmationFeeSats has no omitempty: ATTN-01 requires it, and zero is a valid fee.
 type BillboardData struct {
 	Name                 string `json:"name,omitempty"`
 	Description          string `json:"description,omitempty"`
-	ConfirmationFeeSats  int64  `json:"confirmation_fee_sats,omitempty"`
+	ConfirmationFeeSats  Sats   `json:"confirmation_fee_sats"`
 	RefBillboardPubkey   string `json:"ref_billboard_pubkey,omitempty"`
 	RefBillboardID       string `json:"ref_billboard_id,omitempty"`
 	RefMarketplacePubkey string `json:"ref_marketplace_pubkey,omitempty"`
@@ -74,7 +76,7 @@ type BillboardData struct {
 // PromotionData represents PROMOTION event content (kind 38388).
 type PromotionData struct {
 	Duration             int64    `json:"duration,omitempty"`
-	Bid                  int64    `json:"bid,omitempty"`
+	Bid                  Sats     `json:"bid,omitempty"`
 	EventID              string   `json:"event_id,omitempty"`
 	CallToAction         string   `json:"call_to_action,omitempty"`
 	CallToActionURL      string   `json:"call_to_action_url,omitempty"`
@@ -89,7 +91,7 @@ type PromotionData struct {
 
 // AttentionData represents ATTENTION event content (kind 38488).
 type AttentionData struct {
-	Ask                   int64  `json:"ask,omitempty"`
+	Ask                   Sats   `json:"ask,omitempty"`
 	MinDuration           int64  `json:"min_duration,omitempty"`
 	MaxDuration           int64  `json:"max_duration,omitempty"`
 	BlockedPromotionsID   string `json:"blocked_promotions_id,omitempty"`
@@ -167,7 +169,7 @@ type MarketplaceConfirmationData struct {
 // AttentionPaymentConfirmationData represents ATTENTION_PAYMENT_CONFIRMATION event content (kind 38988).
 // Per ATTN-01, contains sats_received, payment_proof, and ref_* fields.
 type AttentionPaymentConfirmationData struct {
-	SatsReceived                      int64  `json:"sats_received,omitempty"`
+	SatsReceived                      Sats   `json:"sats_received,omitempty"`
 	PaymentProof                      string `json:"payment_proof,omitempty"`
 	RefMatchEventID                   string `json:"ref_match_event_id,omitempty"`
 	RefMatchID                        string `json:"ref_match_id,omitempty"`
@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// BatchOptions configures ValidateBatchWithOptions. The zero value verifies ids and
+// signatures and validates with DefaultOptions, like ValidateEvent.
+type BatchOptions struct {
+	// Options selects the validation profile.
+	Options
+
+	// SkipVerification skips id and signature checks, like ValidateTrustedEvent.
+	// Use it for events that were already verified, such as a relay's own storage.
+	SkipVerification bool
+}
+
+// ValidateBatch validates events concurrently with ValidateEvent semantics.
+//
+// Parameters:
+//   - ctx: Cancels the batch; events not yet validated are left unvalidated
+//   - events: The Nostr events to validate
+//   - workers: Number of concurrent workers; values <= 0 use GOMAXPROCS
+//
+// Returns one ValidationResult per event, in input order, and ctx.Err() if the batch
+// was cancelled before every event was validated. Results for unvalidated events are
+// the zero ValidationResult.
+func ValidateBatch(ctx context.Context, events []*nostr.Event, workers int) ([]ValidationResult, error) {
+	return ValidateBatchWithOptions(ctx, events, workers, BatchOptions{})
+}
+
+// ValidateBatchWithOptions is ValidateBatch with a validation profile and optional
+// signature verification.
+func ValidateBatchWithOptions(ctx context.Context, events []*nostr.Event, workers int, opts BatchOptions) ([]ValidationResult, error) {
+	results := make([]ValidationResult, len(events))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(events) {
+		workers = len(events)
+	}
+
+	// Workers claim the next index until the batch is exhausted or cancelled
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				index := int(next.Add(1) - 1)
+				if index >= len(events) {
+					return
+				}
+				results[index] = validateBatchEvent(events[index], opts)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if next.Load() < int64(len(events)) {
+		return results, ctx.Err()
+	}
+	return results, nil
+}
+
+// validateBatchEvent validates a single event of a batch.
+func validateBatchEvent(event *nostr.Event, opts BatchOptions) ValidationResult {
+	if !opts.SkipVerification {
+		if result := VerifyEvent(event); !result.Valid {
+			return result
+		}
+	}
+	if event.Kind == core.KindCityBlock {
+		return ValidateCityBlockEvent(event)
+	}
+	return ValidateATTNEventWithOptions(event, opts.Options)
+}
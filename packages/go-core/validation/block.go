@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// ValidateCityBlockEvent checks the block reference of City Protocol Block events (kind 38808).
+// Full Block event validation belongs to City Protocol; this only verifies what ATTN Protocol
+// relies on when events point at a block: that the d tag is a well-formed block identifier
+// and that it agrees with the block_height, block_hash and ref_block_id in content.
+//
+// Parameters:
+//   - event: The Nostr event to validate
+//
+// Returns a ValidationResult indicating if the event is valid and any error message.
+func ValidateCityBlockEvent(event *nostr.Event) ValidationResult {
+	if event.Kind != core.KindCityBlock {
+		return ValidationResult{Valid: false, Code: CodeUnsupportedKind, Message: fmt.Sprintf("Not a City Protocol block event kind: %d", event.Kind)}
+	}
+
+	// Must have d tag (org.cityprotocol:block:<height>:<hash>)
+	d_tag := getTagValue(event, "d")
+	if d_tag == "" {
+		return ValidationResult{Valid: false, Code: CodeMissingDTag, Field: "d", Message: "Missing 'd' tag (block identifier)"}
+	}
+
+	block_id, err := core.ParseCityBlockID(d_tag)
+	if err != nil {
+		return ValidationResult{Valid: false, Code: CodeBadDTag, Field: "d", Message: fmt.Sprintf("Invalid d tag format: %s", err.Error())}
+	}
+
+	// Content must be valid JSON
+	var content_data core.CityBlockData
+	if err := json.Unmarshal([]byte(event.Content), &content_data); err != nil {
+		return ValidationResult{Valid: false, Code: CodeInvalidJSON, Message: "Content must be valid JSON"}
+	}
+
+	// d tag height and hash must agree with content
+	if content_data.BlockHeight != block_id.Height {
+		return ValidationResult{Valid: false, Code: CodeContentMismatch, Field: "block_height", Message: fmt.Sprintf("block_height %d does not match d tag height %d", content_data.BlockHeight, block_id.Height)}
+	}
+	if !strings.EqualFold(content_data.BlockHash, block_id.Hash) {
+		return ValidationResult{Valid: false, Code: CodeContentMismatch, Field: "block_hash", Message: "block_hash does not match d tag hash"}
+	}
+	if content_data.RefBlockID != "" && content_data.RefBlockID != d_tag {
+		return ValidationResult{Valid: false, Code: CodeContentMismatch, Field: "ref_block_id", Message: "ref_block_id does not match d tag"}
+	}
+
+	return ValidationResult{Valid: true, Message: "Valid block event"}
+}
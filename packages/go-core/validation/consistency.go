@@ -0,0 +1,188 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// coordinateRef ties the ref_*_pubkey and ref_*_id content fields to an 'a' coordinate.
+// When kind equals the event's own kind, the fields describe the event itself and are
+// checked against its author and d tag instead.
+type coordinateRef struct {
+	kind         int
+	pubkey_field string
+	id_field     string
+}
+
+// eventRef ties a ref_*_event_id content field to an 'e' tag with the given marker.
+type eventRef struct {
+	field  string
+	marker string
+}
+
+// contentRefs lists the reference fields a kind carries in content.
+type contentRefs struct {
+	coordinates []coordinateRef
+	events      []eventRef
+	lists       []string
+}
+
+var (
+	marketplaceRef = coordinateRef{core.KindMarketplace, "ref_marketplace_pubkey", "ref_marketplace_id"}
+	billboardRef   = coordinateRef{core.KindBillboard, "ref_billboard_pubkey", "ref_billboard_id"}
+	promotionRef   = coordinateRef{core.KindPromotion, "ref_promotion_pubkey", "ref_promotion_id"}
+	attentionRef   = coordinateRef{core.KindAttention, "ref_attention_pubkey", "ref_attention_id"}
+	matchRef       = coordinateRef{core.KindMatch, "", "ref_match_id"}
+	blockRef       = coordinateRef{core.KindCityBlock, "ref_clock_pubkey", "ref_block_id"}
+)
+
+// refsByKind maps each ATTN Protocol kind to the references its content must agree with.
+var refsByKind = map[int]contentRefs{
+	core.KindMarketplace: {
+		coordinates: []coordinateRef{marketplaceRef, blockRef},
+	},
+	core.KindBillboard: {
+		coordinates: []coordinateRef{billboardRef, marketplaceRef},
+	},
+	core.KindPromotion: {
+		coordinates: []coordinateRef{promotionRef, marketplaceRef, billboardRef},
+	},
+	core.KindAttention: {
+		coordinates: []coordinateRef{attentionRef, marketplaceRef},
+		lists:       []string{"blocked_promotions_id", "blocked_promoters_id", "trusted_marketplaces_id", "trusted_billboards_id"},
+	},
+	core.KindMatch: {
+		coordinates: []coordinateRef{matchRef, marketplaceRef, billboardRef, promotionRef, attentionRef},
+	},
+	core.KindBillboardConfirmation: {
+		coordinates: []coordinateRef{matchRef, marketplaceRef, billboardRef, promotionRef, attentionRef},
+		events:      []eventRef{{"ref_match_event_id", "match"}},
+	},
+	core.KindAttentionConfirmation: {
+		coordinates: []coordinateRef{matchRef, marketplaceRef, billboardRef, promotionRef, attentionRef},
+		events:      []eventRef{{"ref_match_event_id", "match"}},
+	},
+	core.KindMarketplaceConfirmation: {
+		coordinates: []coordinateRef{matchRef, marketplaceRef, billboardRef, promotionRef, attentionRef},
+		events: []eventRef{
+			{"ref_match_event_id", "match"},
+			{"ref_billboard_confirmation_event_id", "billboard_confirmation"},
+			{"ref_attention_confirmation_event_id", "attention_confirmation"},
+		},
+	},
+	core.KindAttentionPaymentConfirmation: {
+		coordinates: []coordinateRef{matchRef, marketplaceRef, billboardRef, promotionRef, attentionRef},
+		events: []eventRef{
+			{"ref_match_event_id", "match"},
+			{"ref_marketplace_confirmation_event_id", "marketplace_confirmation"},
+		},
+	},
+}
+
+// validateContentRefs checks that ref_* content fields agree with the event's tags:
+// ids and pubkeys with the matching 'a' coordinate (or the event's own d tag and author),
+// referenced pubkeys with 'p' tags, event ids with 'e' tags, and NIP-51 list ids with
+// list coordinates. Fields absent from content are left to the required-field checks.
+func validateContentRefs(event *nostr.Event, content_data map[string]interface{}) ValidationResult {
+	refs := refsByKind[event.Kind]
+	p_tags := getTagValues(event, "p")
+
+	for _, ref := range refs.coordinates {
+		event_type, _ := core.EventTypeForKind(ref.kind)
+
+		pubkey, d_tag, source := event.PubKey, getTagValue(event, "d"), "event"
+		if ref.kind != event.Kind {
+			coordinate, err := core.ParseCoordinate(getTagValueByPrefix(event, "a", fmt.Sprintf("%d:", ref.kind)))
+			if err != nil {
+				continue // Coordinate presence and format are checked by the kind validator
+			}
+			pubkey, d_tag, source = coordinate.Pubkey, coordinate.DTag, event_type+" coordinate 'a' tag"
+		}
+
+		if ref.id_field != "" {
+			value, result := contentString(content_data, ref.id_field)
+			if !result.Valid {
+				return result
+			}
+			if value != nil && !matchesDTag(*value, d_tag) {
+				return contentMismatch(ref.id_field, fmt.Sprintf("%s does not match %s", ref.id_field, source))
+			}
+		}
+
+		if ref.pubkey_field != "" {
+			value, result := contentString(content_data, ref.pubkey_field)
+			if !result.Valid {
+				return result
+			}
+			if value == nil {
+				continue
+			}
+			if *value != pubkey {
+				return contentMismatch(ref.pubkey_field, fmt.Sprintf("%s does not match %s", ref.pubkey_field, source))
+			}
+			if !slices.Contains(p_tags, *value) {
+				return contentMismatch(ref.pubkey_field, fmt.Sprintf("%s is not listed in 'p' tags", ref.pubkey_field))
+			}
+		}
+	}
+
+	for _, ref := range refs.events {
+		value, result := contentString(content_data, ref.field)
+		if !result.Valid {
+			return result
+		}
+		if value == nil {
+			continue
+		}
+		// Prefer the marked e tag; fall back to any e tag when the marker is optional for this kind
+		if marked := getETagByMarker(event, ref.marker); marked != "" {
+			if *value != marked {
+				return contentMismatch(ref.field, fmt.Sprintf("%s does not match 'e' tag with '%s' marker", ref.field, ref.marker))
+			}
+		} else if !slices.Contains(getTagValues(event, "e"), *value) {
+			return contentMismatch(ref.field, fmt.Sprintf("%s is not referenced by any 'e' tag", ref.field))
+		}
+	}
+
+	for _, field := range refs.lists {
+		value, result := contentString(content_data, field)
+		if !result.Valid {
+			return result
+		}
+		if value != nil && !hasListCoordinate(event, *value) {
+			return contentMismatch(field, fmt.Sprintf("%s does not match any list coordinate 'a' tag", field))
+		}
+	}
+
+	return ValidationResult{Valid: true, Message: "Content references match tags"}
+}
+
+// contentString returns a string content field, or nil when the field is absent.
+func contentString(content_data map[string]interface{}, field string) (*string, ValidationResult) {
+	raw, ok := content_data[field]
+	if !ok || raw == nil {
+		return nil, ValidationResult{Valid: true}
+	}
+	value, ok := raw.(string)
+	if !ok {
+		return nil, ValidationResult{Valid: false, Code: CodeBadField, Field: field, Message: fmt.Sprintf("%s must be a string", field)}
+	}
+	return &value, ValidationResult{Valid: true}
+}
+
+// matchesDTag reports whether a ref_*_id value names the given d tag, either as the
+// bare identifier (per ATTN-01) or as the full d tag.
+func matchesDTag(value string, d_tag string) bool {
+	if value == d_tag {
+		return true
+	}
+	parsed, err := core.ParseDTag(d_tag)
+	return err == nil && parsed.Identifier == value
+}
+
+func contentMismatch(field string, message string) ValidationResult {
+	return ValidationResult{Valid: false, Code: CodeContentMismatch, Field: field, Message: message}
+}
@@ -0,0 +1,86 @@
+package validation
+
+import "fmt"
+
+// ErrorCode is a stable, machine-readable reason for a validation failure.
+// Codes are part of the public contract: relays return them in OK messages
+// and consumers branch on them, so existing values must not change.
+type ErrorCode string
+
+// Validation error codes.
+const (
+	// CodeBadID means the event id is not the hash of the serialized event.
+	CodeBadID ErrorCode = "bad_id"
+
+	// CodeBadSignature means the signature does not verify against the event pubkey.
+	CodeBadSignature ErrorCode = "bad_signature"
+
+	// CodeUnsupportedKind means the event kind is not handled by the validator.
+	CodeUnsupportedKind ErrorCode = "unsupported_kind"
+
+	// CodeNonStandardTag means the event uses a tag outside the official Nostr set.
+	CodeNonStandardTag ErrorCode = "non_standard_tag"
+
+	// CodeMissingDTag means the 'd' tag is absent.
+	CodeMissingDTag ErrorCode = "missing_d_tag"
+
+	// CodeBadDTag means the 'd' tag does not follow the namespace format.
+	CodeBadDTag ErrorCode = "bad_d_tag"
+
+	// CodeMissingBlockHeight means the 't' tag is absent.
+	CodeMissingBlockHeight ErrorCode = "missing_block_height"
+
+	// CodeBadBlockHeight means the 't' tag is not a valid block height.
+	CodeBadBlockHeight ErrorCode = "bad_block_height"
+
+	// CodeMissingCoordinate means a required 'a' tag coordinate is absent.
+	CodeMissingCoordinate ErrorCode = "missing_coordinate"
+
+	// CodeBadCoordinate means an 'a' tag coordinate is malformed.
+	CodeBadCoordinate ErrorCode = "bad_coordinate"
+
+	// CodeMissingMarker means a required 'e' tag marker is absent.
+	CodeMissingMarker ErrorCode = "missing_marker"
+
+	// CodeMissingTag means too few tags of a required name are present.
+	CodeMissingTag ErrorCode = "missing_tag"
+
+	// CodeBadTag means a tag value is malformed, such as a list entry that is not a hex pubkey.
+	CodeBadTag ErrorCode = "bad_tag"
+
+	// CodeInvalidJSON means the content is not valid JSON.
+	CodeInvalidJSON ErrorCode = "invalid_json"
+
+	// CodeMissingField means a required content field is absent.
+	CodeMissingField ErrorCode = "missing_field"
+
+	// CodeBadField means a content field has the wrong type or an out-of-range value.
+	CodeBadField ErrorCode = "bad_field"
+
+	// CodeUnknownField means content has a field ATTN-01 does not define (strict profiles only).
+	CodeUnknownField ErrorCode = "unknown_field"
+
+	// CodeContentMismatch means content disagrees with the event's tags.
+	CodeContentMismatch ErrorCode = "content_mismatch"
+
+	// CodeUnresolvedReference means a referenced event could not be found.
+	CodeUnresolvedReference ErrorCode = "unresolved_reference"
+
+	// CodeReferenceMismatch means a referenced event disagrees with the referencing event.
+	CodeReferenceMismatch ErrorCode = "reference_mismatch"
+
+	// CodeResolverFailed means the resolver returned an error while fetching a reference.
+	CodeResolverFailed ErrorCode = "resolver_failed"
+)
+
+// Reason formats a failed result as a NIP-01 OK message: "invalid: <code>: <message>".
+// Valid results return an empty string.
+func (r ValidationResult) Reason() string {
+	if r.Valid {
+		return ""
+	}
+	if r.Code == "" {
+		return fmt.Sprintf("invalid: %s", r.Message)
+	}
+	return fmt.Sprintf("invalid: %s: %s", r.Code, r.Message)
+}
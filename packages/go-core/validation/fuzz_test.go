@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// Validators run on untrusted events at the relay's RejectEvent hook, so a panic on any
+// input is a remote crash. Each target decodes fuzzed tags and content into an event of
+// the validator's kind and checks that validation returns instead of panicking.
+//
+// Run a target with: go test ./validation -run '^$' -fuzz FuzzValidateMatchEvent
+
+// fuzzFixtures returns one fixture per kind to seed the fuzz corpus.
+func fuzzFixtures() []*nostr.Event {
+	pubkey := generateTestPubkey()
+	match := createTestMatchEvent(pubkey, 870500)
+	marketplace_confirmation := createTestMarketplaceConfirmationEvent(pubkey, 870500, match.ID, generateTestPubkey(), generateTestPubkey())
+
+	return []*nostr.Event{
+		createTestMarketplaceEvent(pubkey, 870500),
+		createTestBillboardEvent(pubkey, 870500),
+		createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey),
+		createTestAttentionEvent(pubkey, 870500, pubkey),
+		createTestConfirmationEvent(38588, pubkey, 870500, match.ID),
+		createTestConfirmationEvent(38688, pubkey, 870500, match.ID),
+		marketplace_confirmation,
+		match,
+		createTestPaymentConfirmationEvent(pubkey, 870500, match.ID, marketplace_confirmation.ID),
+		createTestBlockEvent(pubkey, 870500, strings.Repeat("ab", 32)),
+	}
+}
+
+// addFuzzSeeds seeds the corpus with the tags and content of every fixture, plus
+// malformed tag shapes that have crashed validators elsewhere.
+func addFuzzSeeds(f *testing.F) {
+	for _, fixture := range fuzzFixtures() {
+		tags, err := json.Marshal(fixture.Tags)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(tags, fixture.Content)
+	}
+
+	f.Add([]byte(`[]`), `{}`)
+	f.Add([]byte(`[[]]`), `null`)
+	f.Add([]byte(`[["d"],["t"],["a"],["e"],["p"]]`), `[]`)
+	f.Add([]byte(`[["e","","",""],["a",":::"]]`), `{"block_height":"870500"}`)
+	f.Add([]byte(`[["d","org.attnprotocol:match:"],["t","-1"],["a","38188::"]]`), `{"ref_match_id":1e400}`)
+}
+
+// fuzzEvent builds an event of the given kind from fuzzed tags and content.
+func fuzzEvent(t *testing.T, kind int, tags_json []byte, content string) *nostr.Event {
+	var tags nostr.Tags
+	if err := json.Unmarshal(tags_json, &tags); err != nil {
+		t.Skip()
+	}
+	return &nostr.Event{
+		Kind:    kind,
+		PubKey:  strings.Repeat("ab", 32),
+		Tags:    tags,
+		Content: content,
+	}
+}
+
+// checkResult fails when a rejection carries no code, since callers and OK messages depend on it.
+func checkResult(t *testing.T, result ValidationResult) {
+	if !result.Valid && result.Code == "" {
+		t.Errorf("invalid result has no code: %s", result.Message)
+	}
+}
+
+// fuzzValidator runs a validator against fuzzed events of its kind.
+func fuzzValidator(f *testing.F, kind int, validate func(event *nostr.Event) ValidationResult) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, tags_json []byte, content string) {
+		checkResult(t, validate(fuzzEvent(t, kind, tags_json, content)))
+	})
+}
+
+func FuzzValidateMarketplaceEvent(f *testing.F) {
+	fuzzValidator(f, 38188, ValidateMarketplaceEvent)
+}
+
+func FuzzValidateBillboardEvent(f *testing.F) {
+	fuzzValidator(f, 38288, ValidateBillboardEvent)
+}
+
+func FuzzValidatePromotionEvent(f *testing.F) {
+	fuzzValidator(f, 38388, ValidatePromotionEvent)
+}
+
+func FuzzValidateAttentionEvent(f *testing.F) {
+	fuzzValidator(f, 38488, ValidateAttentionEvent)
+}
+
+func FuzzValidateBillboardConfirmationEvent(f *testing.F) {
+	fuzzValidator(f, 38588, ValidateBillboardConfirmationEvent)
+}
+
+func FuzzValidateAttentionConfirmationEvent(f *testing.F) {
+	fuzzValidator(f, 38688, ValidateAttentionConfirmationEvent)
+}
+
+func FuzzValidateMarketplaceConfirmationEvent(f *testing.F) {
+	fuzzValidator(f, 38788, ValidateMarketplaceConfirmationEvent)
+}
+
+func FuzzValidateMatchEvent(f *testing.F) {
+	fuzzValidator(f, 38888, ValidateMatchEvent)
+}
+
+func FuzzValidateAttentionPaymentConfirmationEvent(f *testing.F) {
+	fuzzValidator(f, 38988, ValidateAttentionPaymentConfirmationEvent)
+}
+
+func FuzzValidateCityBlockEvent(f *testing.F) {
+	fuzzValidator(f, 38808, ValidateCityBlockEvent)
+}
+
+func FuzzValidateListEvent(f *testing.F) {
+	fuzzValidator(f, 30000, ValidateListEvent)
+}
+
+// FuzzValidateATTNEvent routes fuzzed events of any kind through the entry points the
+// relay and framework call, under every profile.
+func FuzzValidateATTNEvent(f *testing.F) {
+	for _, fixture := range fuzzFixtures() {
+		tags, _ := json.Marshal(fixture.Tags)
+		f.Add(fixture.Kind, tags, fixture.Content)
+	}
+	f.Add(1, []byte(`[["client"]]`), `{}`)
+
+	f.Fuzz(func(t *testing.T, kind int, tags_json []byte, content string) {
+		event := fuzzEvent(t, kind, tags_json, content)
+
+		checkResult(t, ValidateATTNEvent(event))
+		checkResult(t, ValidateTrustedEvent(event))
+		checkResult(t, ValidateEvent(event))
+		for _, opts := range []Options{StrictOptions(), LenientOptions()} {
+			checkResult(t, ValidateATTNEventWithOptions(event, opts))
+		}
+	})
+}
+
+func FuzzParseHeight(f *testing.F) {
+	for _, fixture := range fuzzFixtures() {
+		for _, tag := range fixture.Tags {
+			if len(tag) >= 2 && tag[0] == "t" {
+				f.Add(tag[1], 870500.0)
+			}
+		}
+	}
+	f.Add("-9223372036854775809", 1e300)
+	f.Add("0x10", -1.5)
+
+	f.Fuzz(func(t *testing.T, text string, number float64) {
+		parseHeight(text)
+		parseHeight(number)
+
+		// Content decodes heights from arbitrary JSON values
+		var value interface{}
+		if json.Unmarshal([]byte(text), &value) == nil {
+			parseHeight(value)
+		}
+	})
+}
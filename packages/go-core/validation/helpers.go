@@ -8,9 +8,11 @@ package validation
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
+	"github.com/joinnextblock/attn-protocol/go-core"
 	"github.com/nbd-wtf/go-nostr"
 )
 
@@ -18,126 +20,33 @@ import (
 // ATTN Protocol events use: org.attnprotocol:<event_type>:<identifier>
 // City Protocol events use: org.cityprotocol:<event_type>:<identifier>
 func validateDTagFormat(kind int, d_tag string) error {
-	// City Protocol block events use org.cityprotocol: prefix
-	if kind == 38808 {
-		expected_prefix := "org.cityprotocol:"
-		if !strings.HasPrefix(d_tag, expected_prefix) {
-			return fmt.Errorf("d tag must start with '%s' for City Protocol events", expected_prefix)
-		}
-		// Validate format: org.cityprotocol:block:<height>:<hash>
-		if !strings.HasPrefix(d_tag, "org.cityprotocol:block:") {
-			return fmt.Errorf("d tag must be in format 'org.cityprotocol:block:<height>:<hash>'")
-		}
-		return nil
-	}
-
-	// ATTN Protocol events use org.attnprotocol: prefix
-	expected_prefix := "org.attnprotocol:"
-	if !strings.HasPrefix(d_tag, expected_prefix) {
-		return fmt.Errorf("d tag must start with '%s'", expected_prefix)
-	}
-
-	// Map of event kinds to expected event type in d tag
-	event_type_map := map[int]string{
-		38188: "marketplace",
-		38288: "billboard",
-		38388: "promotion",
-		38488: "attention",
-		38588: "billboard-confirmation",
-		38688: "attention-confirmation",
-		38788: "marketplace-confirmation",
-		38888: "match",
-		38988: "attention-payment-confirmation",
-	}
-
-	expected_type, ok := event_type_map[kind]
-	if !ok {
-		return fmt.Errorf("unknown event kind: %d", kind)
-	}
-
-	// Remove the prefix to get the remaining parts: <event_type>:<identifier>
-	remaining := strings.TrimPrefix(d_tag, expected_prefix)
-	if remaining == d_tag {
-		// This shouldn't happen since we checked HasPrefix above, but be safe
-		return fmt.Errorf("d tag must start with 'org.attnprotocol:'")
+	// City Protocol block d tags carry a height and hash: org.cityprotocol:block:<height>:<hash>
+	if kind == core.KindCityBlock {
+		_, err := core.ParseCityBlockID(d_tag)
+		return err
 	}
 
-	// Split the remaining part by ':' to get event_type and identifier
-	// Format: <event_type>:<identifier> (identifier may contain colons)
-	parts := strings.SplitN(remaining, ":", 2)
-	if len(parts) < 2 {
-		return fmt.Errorf("d tag format invalid: expected org.attnprotocol:<event_type>:<identifier>, got '%s'", d_tag)
-	}
-
-	event_type := parts[0]
-	identifier := parts[1]
-
-	if event_type != expected_type {
-		return fmt.Errorf("d tag event type mismatch: expected '%s', got '%s'", expected_type, event_type)
-	}
-
-	if identifier == "" {
-		return fmt.Errorf("d tag identifier is empty")
+	parsed, err := core.ParseDTag(d_tag)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	return parsed.Validate(kind)
 }
 
 // validateCoordinateFormat validates that coordinate follows format: kind:pubkey:org.attnprotocol:event_type:identifier
 // For City Protocol events (38808), format is: kind:pubkey:org.cityprotocol:event_type:identifier
 // For non-protocol events (e.g., video kind 34236), format is: kind:pubkey:d_tag (without org.attnprotocol:)
 func validateCoordinateFormat(coordinate string, expected_kind int) error {
-	parts := strings.Split(coordinate, ":")
-	if len(parts) < 3 {
-		return fmt.Errorf("coordinate format invalid: expected kind:pubkey:identifier")
-	}
-
-	// Parse kind from coordinate
-	coord_kind, err := strconv.Atoi(parts[0])
+	parsed, err := core.ParseCoordinate(coordinate)
 	if err != nil {
-		return fmt.Errorf("coordinate kind must be numeric: %s", parts[0])
+		return err
 	}
 
-	if coord_kind != expected_kind {
-		return fmt.Errorf("coordinate kind mismatch: expected %d, got %d", expected_kind, coord_kind)
+	if parsed.Kind != expected_kind {
+		return fmt.Errorf("coordinate kind mismatch: expected %d, got %d", expected_kind, parsed.Kind)
 	}
 
-	// For City Protocol block events (38808), validate org.cityprotocol: prefix
-	if coord_kind == 38808 {
-		if len(parts) < 4 {
-			return fmt.Errorf("City Protocol coordinate format invalid: expected kind:pubkey:org.cityprotocol:event_type:identifier")
-		}
-		if parts[2] != "org.cityprotocol" {
-			return fmt.Errorf("City Protocol coordinate must include 'org.cityprotocol:' prefix")
-		}
-		if len(parts) < 5 {
-			return fmt.Errorf("City Protocol coordinate format invalid: expected kind:pubkey:org.cityprotocol:event_type:identifier")
-		}
-		if parts[3] == "" {
-			return fmt.Errorf("City Protocol coordinate event type is empty")
-		}
-		return nil
-	}
-
-	// For ATTN Protocol events (38188-38988), validate org.attnprotocol: prefix
-	if coord_kind >= 38188 && coord_kind <= 38988 {
-		if len(parts) < 4 {
-			return fmt.Errorf("protocol coordinate format invalid: expected kind:pubkey:org.attnprotocol:event_type:identifier")
-		}
-		// parts[0] = kind, parts[1] = pubkey, parts[2] = org.attnprotocol, parts[3] = event_type, parts[4+] = identifier
-		if parts[2] != "org.attnprotocol" {
-			return fmt.Errorf("protocol coordinate must include 'org.attnprotocol:' prefix")
-		}
-		if len(parts) < 5 {
-			return fmt.Errorf("protocol coordinate format invalid: expected kind:pubkey:org.attnprotocol:event_type:identifier")
-		}
-		// parts[3] should be the event type (marketplace, etc.)
-		if parts[3] == "" {
-			return fmt.Errorf("protocol coordinate event type is empty")
-		}
-	}
-
-	return nil
+	return parsed.Validate()
 }
 
 // validateETagWithMarker validates that an e tag with the specified marker exists
@@ -203,6 +112,26 @@ func parseHeight(value interface{}) (int64, error) {
 	}
 }
 
+// validateSatsField validates that a content amount is a whole number of sats no larger
+// than the bitcoin supply, and positive unless allow_zero is set.
+func validateSatsField(content_data map[string]interface{}, field string, allow_zero bool) ValidationResult {
+	amount, ok := content_data[field].(float64)
+	if !ok || amount < 0 || (amount == 0 && !allow_zero) {
+		qualifier := "positive"
+		if allow_zero {
+			qualifier = "non-negative"
+		}
+		return ValidationResult{Valid: false, Code: CodeBadField, Field: field, Message: fmt.Sprintf("%s must be a %s number", field, qualifier)}
+	}
+	if amount != math.Trunc(amount) {
+		return ValidationResult{Valid: false, Code: CodeBadField, Field: field, Message: fmt.Sprintf("%s must be a whole number of sats", field)}
+	}
+	if amount > float64(core.MaxSats) {
+		return ValidationResult{Valid: false, Code: CodeBadField, Field: field, Message: fmt.Sprintf("%s must not exceed %d sats", field, int64(core.MaxSats))}
+	}
+	return ValidationResult{Valid: true, Message: "Valid amount"}
+}
+
 // hasListCoordinate checks if the event has an 'a' tag with a NIP-51 list coordinate
 func hasListCoordinate(event *nostr.Event, suffix string) bool {
 	for _, tag := range event.Tags {
@@ -213,14 +142,20 @@ func hasListCoordinate(event *nostr.Event, suffix string) bool {
 	return false
 }
 
-// validateOfficialTagsOnly validates that only official Nostr tags are used.
+// validateAllowedTags validates that only official Nostr tags, plus any extra tags
+// the validation profile permits, are used.
 // ATTN-01 limits tags to official Nostr tags: d, t, a, e, p, r, k, u
 // Block events (38808) only use d and p tags per CITY-01 specification.
-func validateOfficialTagsOnly(event *nostr.Event) ValidationResult {
+func validateAllowedTags(event *nostr.Event, extra_tags []string) ValidationResult {
 	allowed_tags := map[string]bool{
 		"d": true, "t": true, "a": true, "e": true,
 		"p": true, "r": true, "k": true, "u": true,
 	}
+	allowed_list := "d, t, a, e, p, r, k, u"
+	for _, tag_name := range extra_tags {
+		allowed_tags[tag_name] = true
+		allowed_list += ", " + tag_name
+	}
 
 	for _, tag := range event.Tags {
 		if len(tag) > 0 {
@@ -228,7 +163,9 @@ func validateOfficialTagsOnly(event *nostr.Event) ValidationResult {
 			if !allowed_tags[tag_name] {
 				return ValidationResult{
 					Valid:   false,
-					Message: fmt.Sprintf("Non-standard tag '%s' not allowed. Only official Nostr tags are permitted: d, t, a, e, p, r, k, u", tag_name),
+					Code:    CodeNonStandardTag,
+					Field:   tag_name,
+					Message: fmt.Sprintf("Non-standard tag '%s' not allowed. Only official Nostr tags are permitted: %s", tag_name, allowed_list),
 				}
 			}
 		}
@@ -236,4 +173,3 @@ func validateOfficialTagsOnly(event *nostr.Event) ValidationResult {
 
 	return ValidationResult{Valid: true, Message: "All tags are official Nostr tags"}
 }
-
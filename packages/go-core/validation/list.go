@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// ValidateListEvent validates an ATTN Protocol NIP-51 preference list (kind 30000 or 30003).
+// The d tag must be one of the four list types, the optional t tag must be a numeric
+// block height, and every entry the list type holds must be well formed: 'a' entries must
+// be coordinates of the listed kind, 'e' and 'p' entries 64-character hex values.
+// Content is not checked, since NIP-51 lists may carry encrypted private entries there.
+//
+// Parameters:
+//   - event: The Nostr event to validate
+//
+// Returns a ValidationResult indicating if the event is valid and any error message.
+func ValidateListEvent(event *nostr.Event) ValidationResult {
+	if !core.IsListKind(event.Kind) {
+		return ValidationResult{Valid: false, Code: CodeUnsupportedKind, Message: fmt.Sprintf("Not a NIP-51 list event kind: %d", event.Kind)}
+	}
+
+	// Must have d tag naming the list type
+	d_tag := getTagValue(event, "d")
+	if d_tag == "" {
+		return ValidationResult{Valid: false, Code: CodeMissingDTag, Field: "d", Message: "Missing 'd' tag (list type)"}
+	}
+	if !core.IsListType(d_tag) {
+		return ValidationResult{Valid: false, Code: CodeBadDTag, Field: "d", Message: fmt.Sprintf("Unknown list type: %s", d_tag)}
+	}
+
+	// t tag is optional on lists, but must be numeric when present
+	if block_height := getTagValue(event, "t"); block_height != "" {
+		if _, err := strconv.ParseInt(block_height, 10, 64); err != nil {
+			return ValidationResult{Valid: false, Code: CodeBadBlockHeight, Field: "t", Message: "Invalid block height in 't' tag: must be numeric"}
+		}
+	}
+
+	for _, tag := range event.Tags {
+		_, ok, err := core.ParseListEntry(d_tag, tag)
+		if !ok || err == nil {
+			continue
+		}
+		if tag[0] == "a" {
+			return ValidationResult{Valid: false, Code: CodeBadCoordinate, Field: "a", Message: fmt.Sprintf("Invalid list entry coordinate: %s", err.Error())}
+		}
+		return ValidationResult{Valid: false, Code: CodeBadTag, Field: tag[0], Message: fmt.Sprintf("Invalid list entry: %s", err.Error())}
+	}
+
+	return ValidationResult{Valid: true, Message: "Valid list event"}
+}
@@ -0,0 +1,204 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// Rules controls how much an event may deviate from ATTN-01 beyond its required tags and fields.
+// The zero value matches ValidateATTNEvent.
+type Rules struct {
+	// AllowUnknownTags accepts tags outside the official Nostr set (d, t, a, e, p, r, k, u).
+	AllowUnknownTags bool
+
+	// ExtraTags lists additional tag names accepted when AllowUnknownTags is false.
+	ExtraTags []string
+
+	// RejectUnknownFields rejects content fields that ATTN-01 does not define for the kind.
+	RejectUnknownFields bool
+
+	// TypedFields requires every content field ATTN-01 defines, optional ones included,
+	// to have its specified JSON type.
+	TypedFields bool
+}
+
+// Options selects the validation profile. Rules applies to every ATTN Protocol kind
+// unless Kinds holds an override for it. The zero value is DefaultOptions.
+type Options struct {
+	Rules
+
+	// Kinds holds per-kind overrides of Rules.
+	Kinds map[int]Rules
+}
+
+// DefaultOptions returns the rules ValidateATTNEvent applies: official tags only,
+// unknown content fields allowed, and optional fields unchecked.
+func DefaultOptions() Options {
+	return Options{}
+}
+
+// StrictOptions returns a profile that rejects unknown tags and content fields and
+// type-checks every content field.
+func StrictOptions() Options {
+	return Options{Rules: Rules{RejectUnknownFields: true, TypedFields: true}}
+}
+
+// LenientOptions returns a forward-compatible profile that accepts unknown tags and
+// content fields. Required tags and fields are still enforced.
+func LenientOptions() Options {
+	return Options{Rules: Rules{AllowUnknownTags: true}}
+}
+
+// ParseProfile returns the options for a named profile: "default" (or ""), "strict" or "lenient".
+func ParseProfile(name string) (Options, error) {
+	switch name {
+	case "", "default":
+		return DefaultOptions(), nil
+	case "strict":
+		return StrictOptions(), nil
+	case "lenient":
+		return LenientOptions(), nil
+	}
+	return Options{}, fmt.Errorf("unknown validation profile %q", name)
+}
+
+// WithKind returns a copy of the options with rules overriding the profile for kind.
+func (o Options) WithKind(kind int, rules Rules) Options {
+	kinds := make(map[int]Rules, len(o.Kinds)+1)
+	for k, v := range o.Kinds {
+		kinds[k] = v
+	}
+	kinds[kind] = rules
+	o.Kinds = kinds
+	return o
+}
+
+// RulesFor returns the rules that apply to kind.
+func (o Options) RulesFor(kind int) Rules {
+	if rules, ok := o.Kinds[kind]; ok {
+		return rules
+	}
+	return o.Rules
+}
+
+// ValidateATTNEventWithOptions validates an ATTN Protocol event like ValidateATTNEvent,
+// applying the rules the options select for its kind.
+//
+// Parameters:
+//   - event: The Nostr event to validate
+//   - opts: The validation profile
+//
+// Returns a ValidationResult indicating if the event is valid and any error message.
+func ValidateATTNEventWithOptions(event *nostr.Event, opts Options) ValidationResult {
+	rules := opts.RulesFor(event.Kind)
+
+	if ATTNProtocolKinds[event.Kind] && !rules.AllowUnknownTags {
+		if tag_result := validateAllowedTags(event, rules.ExtraTags); !tag_result.Valid {
+			return tag_result
+		}
+	}
+
+	result := validateKind(event)
+	if !result.Valid {
+		return result
+	}
+
+	if rules.RejectUnknownFields || rules.TypedFields {
+		if field_result := validateContentFields(event, rules); !field_result.Valid {
+			return field_result
+		}
+	}
+
+	return result
+}
+
+// contentTypes maps each ATTN Protocol kind to the core type its content decodes into.
+var contentTypes = map[int]reflect.Type{
+	core.KindMarketplace:                  reflect.TypeOf(core.MarketplaceData{}),
+	core.KindBillboard:                    reflect.TypeOf(core.BillboardData{}),
+	core.KindPromotion:                    reflect.TypeOf(core.PromotionData{}),
+	core.KindAttention:                    reflect.TypeOf(core.AttentionData{}),
+	core.KindBillboardConfirmation:        reflect.TypeOf(core.BillboardConfirmationData{}),
+	core.KindAttentionConfirmation:        reflect.TypeOf(core.AttentionConfirmationData{}),
+	core.KindMarketplaceConfirmation:      reflect.TypeOf(core.MarketplaceConfirmationData{}),
+	core.KindMatch:                        reflect.TypeOf(core.MatchData{}),
+	core.KindAttentionPaymentConfirmation: reflect.TypeOf(core.AttentionPaymentConfirmationData{}),
+}
+
+// validateContentFields rejects unknown content fields and mistyped defined fields,
+// as the rules require. The kind validator has already checked the content is a JSON object.
+func validateContentFields(event *nostr.Event, rules Rules) ValidationResult {
+	content_type, ok := contentTypes[event.Kind]
+	if !ok {
+		return ValidationResult{Valid: true, Message: "No content type for kind"}
+	}
+
+	if rules.RejectUnknownFields {
+		var content_data map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(event.Content), &content_data); err != nil {
+			return ValidationResult{Valid: false, Code: CodeInvalidJSON, Message: "Content must be valid JSON"}
+		}
+
+		known := contentFieldNames(content_type)
+		var unknown []string
+		for field := range content_data {
+			if !known[field] {
+				unknown = append(unknown, field)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return ValidationResult{Valid: false, Code: CodeUnknownField, Field: unknown[0], Message: fmt.Sprintf("Content field '%s' is not defined by ATTN-01", unknown[0])}
+		}
+	}
+
+	if rules.TypedFields {
+		err := json.Unmarshal([]byte(event.Content), reflect.New(content_type).Interface())
+		var type_err *json.UnmarshalTypeError
+		if errors.As(err, &type_err) {
+			return ValidationResult{Valid: false, Code: CodeBadField, Field: type_err.Field, Message: fmt.Sprintf("%s must be of type %s", type_err.Field, jsonTypeName(type_err.Type))}
+		}
+		if err != nil {
+			return ValidationResult{Valid: false, Code: CodeInvalidJSON, Message: "Content must be valid JSON"}
+		}
+	}
+
+	return ValidationResult{Valid: true, Message: "Content fields match ATTN-01"}
+}
+
+// contentFieldNames returns the JSON field names of a content type.
+func contentFieldNames(content_type reflect.Type) map[string]bool {
+	names := make(map[string]bool, content_type.NumField())
+	for i := 0; i < content_type.NumField(); i++ {
+		name, _, _ := strings.Cut(content_type.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// jsonTypeName describes a Go type the way ATTN-01 names JSON types.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array of " + jsonTypeName(t.Elem())
+	}
+	return "object"
+}
@@ -0,0 +1,191 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// Resolver fetches events referenced by other events.
+// Both methods return (nil, nil) when the referenced event is unknown.
+type Resolver interface {
+	// GetEventByID returns the event with the given id.
+	GetEventByID(ctx context.Context, id string) (*nostr.Event, error)
+
+	// GetEventByCoordinate returns the latest event at the given coordinate.
+	GetEventByCoordinate(ctx context.Context, coordinate core.Coordinate) (*nostr.Event, error)
+}
+
+// ValidateWithResolver validates an ATTN Protocol event and, for match and confirmation
+// events, checks that the chain of events it references is coherent.
+//
+// In addition to ValidateATTNEvent, it verifies that:
+//   - a MATCH event's promotion and attention coordinates resolve, and that the promotion
+//     and attention target the match's marketplace (and, for the promotion, billboard)
+//   - the 'match' e tag of a confirmation points at a known MATCH event (kind 38888)
+//   - the match's marketplace, billboard, promotion and attention coordinates equal the confirmation's
+//   - a MARKETPLACE_CONFIRMATION references a BILLBOARD_CONFIRMATION and an ATTENTION_CONFIRMATION for the same match
+//   - an ATTENTION_PAYMENT_CONFIRMATION references a MARKETPLACE_CONFIRMATION for the same match
+//
+// Returns a ValidationResult indicating if the event is valid and any error message.
+func ValidateWithResolver(event *nostr.Event, resolver Resolver) ValidationResult {
+	return ValidateWithResolverContext(context.Background(), event, resolver)
+}
+
+// ValidateWithResolverContext is ValidateWithResolver with a context passed to the resolver.
+func ValidateWithResolverContext(ctx context.Context, event *nostr.Event, resolver Resolver) ValidationResult {
+	return ValidateWithResolverOptions(ctx, event, resolver, DefaultOptions())
+}
+
+// ValidateWithResolverOptions is ValidateWithResolverContext with a validation profile
+// applied to the event itself, as in ValidateATTNEventWithOptions.
+func ValidateWithResolverOptions(ctx context.Context, event *nostr.Event, resolver Resolver, opts Options) ValidationResult {
+	if result := ValidateATTNEventWithOptions(event, opts); !result.Valid {
+		return result
+	}
+
+	switch event.Kind {
+	case core.KindMatch:
+		return validateMatchChain(ctx, event, resolver)
+	case core.KindBillboardConfirmation, core.KindAttentionConfirmation:
+		_, result := resolveMatch(ctx, event, getETagByMarker(event, "match"), resolver)
+		return result
+	case core.KindMarketplaceConfirmation:
+		return validateMarketplaceConfirmationChain(ctx, event, resolver)
+	case core.KindAttentionPaymentConfirmation:
+		return validatePaymentConfirmationChain(ctx, event, resolver)
+	}
+
+	return ValidationResult{Valid: true, Message: "Valid event"}
+}
+
+// validateMatchChain checks that the matched promotion and attention exist and
+// target the same marketplace and billboard as the match.
+func validateMatchChain(ctx context.Context, event *nostr.Event, resolver Resolver) ValidationResult {
+	parties := []struct {
+		kind   int
+		shared []int
+	}{
+		{core.KindPromotion, []int{core.KindMarketplace, core.KindBillboard}},
+		{core.KindAttention, []int{core.KindMarketplace}},
+	}
+
+	for _, party := range parties {
+		event_type, _ := core.EventTypeForKind(party.kind)
+		coordinate, err := core.ParseCoordinate(getTagValueByPrefix(event, "a", fmt.Sprintf("%d:", party.kind)))
+		if err != nil {
+			return ValidationResult{Valid: false, Code: CodeBadCoordinate, Field: "a", Message: fmt.Sprintf("Invalid %s coordinate format: %s", event_type, err.Error())}
+		}
+
+		referenced, err := resolver.GetEventByCoordinate(ctx, coordinate)
+		if err != nil {
+			return ValidationResult{Valid: false, Code: CodeResolverFailed, Field: "a", Message: fmt.Sprintf("Failed to resolve %s event: %s", event_type, err.Error())}
+		}
+		if referenced == nil {
+			return ValidationResult{Valid: false, Code: CodeUnresolvedReference, Field: "a", Message: fmt.Sprintf("Referenced %s event %s not found", event_type, coordinate.String())}
+		}
+
+		for _, kind := range party.shared {
+			prefix := fmt.Sprintf("%d:", kind)
+			if getTagValueByPrefix(referenced, "a", prefix) != getTagValueByPrefix(event, "a", prefix) {
+				shared_type, _ := core.EventTypeForKind(kind)
+				return referenceMismatch("a", fmt.Sprintf("Referenced %s targets a different %s", event_type, shared_type))
+			}
+		}
+	}
+
+	return ValidationResult{Valid: true, Message: "Valid match chain"}
+}
+
+// validateMarketplaceConfirmationChain checks the match and both party confirmations.
+func validateMarketplaceConfirmationChain(ctx context.Context, event *nostr.Event, resolver Resolver) ValidationResult {
+	match, result := resolveMatch(ctx, event, getETagByMarker(event, "match"), resolver)
+	if !result.Valid {
+		return result
+	}
+
+	party_confirmations := []struct {
+		marker string
+		kind   int
+	}{
+		{"billboard_confirmation", core.KindBillboardConfirmation},
+		{"attention_confirmation", core.KindAttentionConfirmation},
+	}
+	for _, party := range party_confirmations {
+		confirmation, result := resolveEvent(ctx, resolver, getETagByMarker(event, party.marker), party.kind, party.marker)
+		if !result.Valid {
+			return result
+		}
+		if getETagByMarker(confirmation, "match") != match.ID {
+			return referenceMismatch("e", fmt.Sprintf("%s references a different match", party.marker))
+		}
+	}
+
+	return ValidationResult{Valid: true, Message: "Valid marketplace confirmation chain"}
+}
+
+// validatePaymentConfirmationChain checks the marketplace confirmation and its match.
+func validatePaymentConfirmationChain(ctx context.Context, event *nostr.Event, resolver Resolver) ValidationResult {
+	marketplace_confirmation, result := resolveEvent(ctx, resolver, getETagByMarker(event, "marketplace_confirmation"), core.KindMarketplaceConfirmation, "marketplace_confirmation")
+	if !result.Valid {
+		return result
+	}
+
+	match_id := getETagByMarker(marketplace_confirmation, "match")
+	if marked := getETagByMarker(event, "match"); marked != "" && marked != match_id {
+		return referenceMismatch("e", "marketplace_confirmation references a different match")
+	}
+
+	_, result = resolveMatch(ctx, event, match_id, resolver)
+	return result
+}
+
+// resolveMatch fetches the referenced match and checks that its coordinates agree with the event's.
+func resolveMatch(ctx context.Context, event *nostr.Event, match_id string, resolver Resolver) (*nostr.Event, ValidationResult) {
+	match, result := resolveEvent(ctx, resolver, match_id, core.KindMatch, "match")
+	if !result.Valid {
+		return nil, result
+	}
+
+	// The event's match coordinate must address the resolved match
+	match_coordinate := core.Coordinate{Kind: match.Kind, Pubkey: match.PubKey, DTag: getTagValue(match, "d")}.String()
+	if getTagValueByPrefix(event, "a", fmt.Sprintf("%d:", core.KindMatch)) != match_coordinate {
+		return nil, referenceMismatch("a", "match coordinate does not address the referenced match")
+	}
+
+	for _, kind := range []int{core.KindMarketplace, core.KindBillboard, core.KindPromotion, core.KindAttention} {
+		prefix := fmt.Sprintf("%d:", kind)
+		if getTagValueByPrefix(event, "a", prefix) != getTagValueByPrefix(match, "a", prefix) {
+			event_type, _ := core.EventTypeForKind(kind)
+			return nil, referenceMismatch("a", fmt.Sprintf("%s coordinate does not match the referenced match", event_type))
+		}
+	}
+
+	return match, ValidationResult{Valid: true, Message: "Valid match reference"}
+}
+
+// resolveEvent fetches an event by id and checks its kind.
+func resolveEvent(ctx context.Context, resolver Resolver, id string, kind int, marker string) (*nostr.Event, ValidationResult) {
+	if id == "" {
+		return nil, ValidationResult{Valid: false, Code: CodeMissingMarker, Field: "e", Message: fmt.Sprintf("Missing 'e' tag with '%s' marker", marker)}
+	}
+
+	referenced, err := resolver.GetEventByID(ctx, id)
+	if err != nil {
+		return nil, ValidationResult{Valid: false, Code: CodeResolverFailed, Field: "e", Message: fmt.Sprintf("Failed to resolve %s event: %s", marker, err.Error())}
+	}
+	if referenced == nil {
+		return nil, ValidationResult{Valid: false, Code: CodeUnresolvedReference, Field: "e", Message: fmt.Sprintf("Referenced %s event %s not found", marker, id)}
+	}
+	if referenced.Kind != kind {
+		return nil, referenceMismatch("e", fmt.Sprintf("Referenced %s event has kind %d, expected %d", marker, referenced.Kind, kind))
+	}
+
+	return referenced, ValidationResult{Valid: true, Message: "Resolved reference"}
+}
+
+func referenceMismatch(field string, message string) ValidationResult {
+	return ValidationResult{Valid: false, Code: CodeReferenceMismatch, Field: field, Message: message}
+}
@@ -0,0 +1,184 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+)
+
+// requiredContentFields lists the content fields ATTN-01 requires for each kind.
+// The kind validators and the exported JSON Schemas both read it.
+var requiredContentFields = map[int][]string{
+	// ref_clock_pubkey replaces ref_node_pubkey (block events now from City Protocol)
+	38188: {"name", "description", "admin_pubkey", "min_duration", "max_duration", "match_fee_sats", "confirmation_fee_sats", "ref_marketplace_pubkey", "ref_marketplace_id", "ref_clock_pubkey", "ref_block_id"},
+	38288: {"name", "confirmation_fee_sats", "ref_billboard_pubkey", "ref_billboard_id", "ref_marketplace_pubkey", "ref_marketplace_id"},
+	38388: {"duration", "bid", "event_id", "call_to_action", "call_to_action_url", "escrow_id_list", "ref_promotion_pubkey", "ref_promotion_id", "ref_marketplace_pubkey", "ref_marketplace_id", "ref_billboard_pubkey", "ref_billboard_id"},
+	38488: {"ask", "min_duration", "max_duration", "ref_attention_pubkey", "ref_attention_id", "ref_marketplace_pubkey", "ref_marketplace_id", "blocked_promotions_id", "blocked_promoters_id"},
+	38588: {"ref_match_event_id", "ref_match_id", "ref_marketplace_pubkey", "ref_billboard_pubkey", "ref_promotion_pubkey", "ref_attention_pubkey", "ref_marketplace_id", "ref_billboard_id", "ref_promotion_id", "ref_attention_id"},
+	38688: {"ref_match_event_id", "ref_match_id", "ref_marketplace_pubkey", "ref_billboard_pubkey", "ref_promotion_pubkey", "ref_attention_pubkey", "ref_marketplace_id", "ref_billboard_id", "ref_promotion_id", "ref_attention_id"},
+	38788: {"ref_match_event_id", "ref_match_id", "ref_billboard_confirmation_event_id", "ref_attention_confirmation_event_id", "ref_marketplace_pubkey", "ref_billboard_pubkey", "ref_promotion_pubkey", "ref_attention_pubkey", "ref_marketplace_id", "ref_billboard_id", "ref_promotion_id", "ref_attention_id"},
+	38888: {"ref_match_id", "ref_promotion_id", "ref_attention_id", "ref_billboard_id", "ref_marketplace_id", "ref_marketplace_pubkey", "ref_promotion_pubkey", "ref_attention_pubkey", "ref_billboard_pubkey"},
+	// payment_proof is optional
+	38988: {"sats_received", "ref_match_event_id", "ref_match_id", "ref_marketplace_confirmation_event_id", "ref_marketplace_pubkey", "ref_billboard_pubkey", "ref_promotion_pubkey", "ref_attention_pubkey", "ref_marketplace_id", "ref_billboard_id", "ref_promotion_id", "ref_attention_id"},
+}
+
+// jsonSchema is the subset of JSON Schema (draft 2020-12) used for content types.
+type jsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        string                 `json:"type"`
+	Properties  map[string]*jsonSchema `json:"properties,omitempty"`
+	Items       *jsonSchema            `json:"items,omitempty"`
+	Minimum     *int64                 `json:"minimum,omitempty"`
+	Maximum     *int64                 `json:"maximum,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+}
+
+// ContentSchema returns the JSON Schema document for the content of an ATTN Protocol kind.
+// Schemas are generated from the core content types and the fields ATTN-01 requires;
+// fields outside the schema are allowed, as in the default validation profile.
+//
+// Parameters:
+//   - kind: An ATTN Protocol kind (38188-38988)
+//
+// Returns the indented schema document, or an error if the kind has no content type.
+func ContentSchema(kind int) ([]byte, error) {
+	content_type, ok := contentTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("no content schema for kind %d", kind)
+	}
+	event_type, _ := core.EventTypeForKind(kind)
+
+	schema := schemaForType(content_type)
+	schema.Schema = "https://json-schema.org/draft/2020-12/schema"
+	schema.Title = content_type.Name()
+	schema.Description = fmt.Sprintf("Content of %s events (kind %d), as defined by ATTN-01.",
+		strings.ToUpper(strings.ReplaceAll(event_type, "-", "_")), kind)
+	schema.Required = requiredContentFields[kind]
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaForType describes a content type or field type.
+func schemaForType(t reflect.Type) *jsonSchema {
+	switch t.Kind() {
+	case reflect.Struct:
+		schema := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema, t.NumField())}
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name != "" && name != "-" {
+				schema.Properties[name] = schemaForType(t.Field(i).Type)
+			}
+		}
+		return schema
+	case reflect.Slice, reflect.Array:
+		return &jsonSchema{Type: "array", Items: schemaForType(t.Elem())}
+	}
+
+	// Amounts must lie within the bitcoin supply
+	if t == reflect.TypeOf(core.Sats(0)) {
+		minimum, maximum := int64(0), int64(core.MaxSats)
+		return &jsonSchema{Type: "integer", Minimum: &minimum, Maximum: &maximum}
+	}
+
+	// Scalar types share the names jsonTypeName uses in error messages
+	return &jsonSchema{Type: jsonTypeName(t)}
+}
+
+// ValidateContent validates raw event content against the JSON Schema for its kind:
+// content must be a JSON object with every required field, and each field the schema
+// defines must have the specified type. It does not check tags or cross-field rules;
+// use ValidateATTNEvent for full events.
+//
+// Parameters:
+//   - kind: An ATTN Protocol kind (38188-38988)
+//   - content: The raw event content
+//
+// Returns a ValidationResult indicating if the content is valid and any error message.
+func ValidateContent(kind int, content string) ValidationResult {
+	content_type, ok := contentTypes[kind]
+	if !ok {
+		return ValidationResult{Valid: false, Code: CodeUnsupportedKind, Message: fmt.Sprintf("No content schema for kind %d", kind)}
+	}
+
+	var content_data map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &content_data); err != nil || content_data == nil {
+		return ValidationResult{Valid: false, Code: CodeInvalidJSON, Message: "Content must be a JSON object"}
+	}
+
+	for _, field := range requiredContentFields[kind] {
+		if _, ok := content_data[field]; !ok {
+			return ValidationResult{Valid: false, Code: CodeMissingField, Field: field, Message: fmt.Sprintf("Content must include %s", field)}
+		}
+	}
+
+	properties := schemaForType(content_type).Properties
+	fields := make([]string, 0, len(content_data))
+	for field := range content_data {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		property, ok := properties[field]
+		if ok && !matchesSchema(content_data[field], property) {
+			return ValidationResult{Valid: false, Code: CodeBadField, Field: field, Message: fmt.Sprintf("%s must be of type %s", field, describeSchema(property))}
+		}
+	}
+
+	return ValidationResult{Valid: true, Message: "Content matches schema"}
+}
+
+// matchesSchema reports whether a decoded JSON value has the schema's type.
+func matchesSchema(value interface{}, schema *jsonSchema) bool {
+	switch schema.Type {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return false
+		}
+		if schema.Minimum != nil && number < float64(*schema.Minimum) {
+			return false
+		}
+		return schema.Maximum == nil || number <= float64(*schema.Maximum)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range items {
+			if !matchesSchema(item, schema.Items) {
+				return false
+			}
+		}
+		return true
+	}
+	_, ok := value.(map[string]interface{})
+	return ok
+}
+
+// describeSchema names a schema type for error messages.
+func describeSchema(schema *jsonSchema) string {
+	if schema.Type == "array" {
+		return "array of " + describeSchema(schema.Items)
+	}
+	if schema.Minimum != nil && schema.Maximum != nil {
+		return fmt.Sprintf("%s in [%d, %d]", schema.Type, *schema.Minimum, *schema.Maximum)
+	}
+	return schema.Type
+}
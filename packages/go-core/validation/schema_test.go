@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+)
+
+const schemasDir = "../../protocol/schemas"
+
+// TestContentSchemaFiles checks that the shipped schema documents match the core types.
+func TestContentSchemaFiles(t *testing.T) {
+	for kind := range contentTypes {
+		event_type, _ := core.EventTypeForKind(kind)
+		path := filepath.Join(schemasDir, fmt.Sprintf("%d-%s.schema.json", kind, event_type))
+
+		schema, err := ContentSchema(kind)
+		if err != nil {
+			t.Fatalf("kind %d: %v", kind, err)
+		}
+		schema = append(schema, '\n')
+
+		if *update_golden {
+			if err := os.MkdirAll(schemasDir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, schema, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		shipped, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("kind %d: %v", kind, err)
+		}
+		if !bytes.Equal(shipped, schema) {
+			t.Errorf("%s is out of date; regenerate with -update", path)
+		}
+	}
+
+	if _, err := ContentSchema(38808); err == nil {
+		t.Errorf("expected no content schema for block events")
+	}
+}
+
+func TestValidateContent(t *testing.T) {
+	pubkey := generateTestPubkey()
+
+	for _, fixture := range fuzzFixtures() {
+		if fixture.Kind == core.KindCityBlock {
+			continue
+		}
+		if result := ValidateContent(fixture.Kind, fixture.Content); !result.Valid {
+			t.Errorf("kind %d: expected fixture content to be valid, got %s: %s", fixture.Kind, result.Code, result.Message)
+		}
+	}
+
+	promotion := createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey).Content
+	tests := []struct {
+		name    string
+		kind    int
+		content string
+		code    ErrorCode
+		field   string
+	}{
+		{"unsupported kind", 38808, promotion, CodeUnsupportedKind, ""},
+		{"not json", 38388, "not json", CodeInvalidJSON, ""},
+		{"not an object", 38388, `["duration"]`, CodeInvalidJSON, ""},
+		{"missing required field", 38388, strings.Replace(promotion, `"bid"`, `"bid_sats"`, 1), CodeMissingField, "bid"},
+		{"string for integer", 38388, strings.Replace(promotion, `"bid": 5000`, `"bid": "5000"`, 1), CodeBadField, "bid"},
+		{"fractional integer", 38388, strings.Replace(promotion, `"bid": 5000`, `"bid": 5000.5`, 1), CodeBadField, "bid"},
+		{"negative amount", 38388, strings.Replace(promotion, `"bid": 5000`, `"bid": -5000`, 1), CodeBadField, "bid"},
+		{"null field", 38388, strings.Replace(promotion, `"event_id": "test-video-id"`, `"event_id": null`, 1), CodeBadField, "event_id"},
+		{"mistyped array item", 38388, strings.Replace(promotion, `["strike_tx_abc123"]`, `[1]`, 1), CodeBadField, "escrow_id_list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateContent(tt.kind, tt.content)
+			if result.Valid || result.Code != tt.code || result.Field != tt.field {
+				t.Errorf("expected %s/%q, got valid=%t %s/%q: %s", tt.code, tt.field, result.Valid, result.Code, result.Field, result.Message)
+			}
+		})
+	}
+
+	// Fields outside the schema are allowed
+	if result := ValidateContent(38388, strings.Replace(promotion, "{", `{"extension": true,`, 1)); !result.Valid {
+		t.Errorf("expected unknown field to be allowed, got: %s", result.Message)
+	}
+}
@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// VerifyEvent checks that event.ID is the NIP-01 hash of the serialized event and
+// that Sig is a valid Schnorr signature of it by PubKey.
+//
+// Parameters:
+//   - event: The Nostr event to verify
+//
+// Returns a ValidationResult indicating if the event is authentic and any error message.
+func VerifyEvent(event *nostr.Event) ValidationResult {
+	if !event.CheckID() {
+		return ValidationResult{Valid: false, Code: CodeBadID, Field: "id", Message: "Event id does not match event hash"}
+	}
+
+	ok, err := event.CheckSignature()
+	if err != nil {
+		return ValidationResult{Valid: false, Code: CodeBadSignature, Field: "sig", Message: fmt.Sprintf("Invalid signature: %s", err.Error())}
+	}
+	if !ok {
+		return ValidationResult{Valid: false, Code: CodeBadSignature, Field: "sig", Message: "Signature does not verify against pubkey"}
+	}
+
+	return ValidationResult{Valid: true, Message: "Valid signature"}
+}
+
+// ValidateEvent verifies an event's id and signature, then validates it against ATTN-01.
+// It accepts ATTN Protocol kinds and City Protocol block events (38808), which ATTN
+// events reference.
+//
+// Use ValidateTrustedEvent for events that were already verified, such as events read
+// back from a relay's own storage.
+//
+// Parameters:
+//   - event: The Nostr event to validate
+//
+// Returns a ValidationResult indicating if the event is valid and any error message.
+func ValidateEvent(event *nostr.Event) ValidationResult {
+	if result := VerifyEvent(event); !result.Valid {
+		return result
+	}
+	return ValidateTrustedEvent(event)
+}
+
+// ValidateTrustedEvent is ValidateEvent without id and signature verification.
+func ValidateTrustedEvent(event *nostr.Event) ValidationResult {
+	if event.Kind == core.KindCityBlock {
+		return ValidateCityBlockEvent(event)
+	}
+	return ValidateATTNEvent(event)
+}
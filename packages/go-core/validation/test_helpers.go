@@ -1,11 +1,14 @@
 package validation
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/joinnextblock/attn-protocol/go-core"
 	"github.com/nbd-wtf/go-nostr"
 )
 
@@ -147,3 +150,241 @@ func createTestMarketplaceEvent(pubkey string, block_height int) *nostr.Event {
 	return event
 }
 
+// createTestBillboardEvent creates a test BILLBOARD event (kind 38288)
+func createTestBillboardEvent(pubkey string, block_height int) *nostr.Event {
+	content := fmt.Sprintf(`{
+		"name": "Test Billboard",
+		"description": "Test billboard description",
+		"confirmation_fee_sats": 100,
+		"ref_billboard_pubkey": "%s",
+		"ref_billboard_id": "test-billboard",
+		"ref_marketplace_pubkey": "%s",
+		"ref_marketplace_id": "test-marketplace"
+	}`, pubkey, pubkey)
+
+	event := createTestEvent(38288, pubkey, content)
+	event.Tags = append(event.Tags,
+		nostr.Tag{"d", "org.attnprotocol:billboard:test-billboard"},
+		nostr.Tag{"t", fmt.Sprintf("%d", block_height)},
+		nostr.Tag{"a", fmt.Sprintf("38188:%s:org.attnprotocol:marketplace:test-marketplace", pubkey)},
+		nostr.Tag{"p", pubkey},
+		nostr.Tag{"p", pubkey},
+		nostr.Tag{"r", "wss://relay.nextblock.city"},
+		nostr.Tag{"k", "34236"},
+		nostr.Tag{"u", "https://example.com/billboard"},
+	)
+
+	event.ID = event.GetID()
+	return event
+}
+
+// createTestMatchEvent creates a test MATCH event (kind 38888) with every party using pubkey
+func createTestMatchEvent(pubkey string, block_height int) *nostr.Event {
+	content := fmt.Sprintf(`{
+		"ref_match_id": "test-match",
+		"ref_promotion_id": "test-promotion",
+		"ref_attention_id": "test-attention",
+		"ref_billboard_id": "test-billboard",
+		"ref_marketplace_id": "test-marketplace",
+		"ref_marketplace_pubkey": "%s",
+		"ref_promotion_pubkey": "%s",
+		"ref_attention_pubkey": "%s",
+		"ref_billboard_pubkey": "%s"
+	}`, pubkey, pubkey, pubkey, pubkey)
+
+	event := createTestEvent(38888, pubkey, content)
+	event.Tags = append(event.Tags,
+		nostr.Tag{"d", "org.attnprotocol:match:test-match"},
+		nostr.Tag{"t", fmt.Sprintf("%d", block_height)},
+		nostr.Tag{"a", fmt.Sprintf("38188:%s:org.attnprotocol:marketplace:test-marketplace", pubkey)},
+		nostr.Tag{"a", fmt.Sprintf("38288:%s:org.attnprotocol:billboard:test-billboard", pubkey)},
+		nostr.Tag{"a", fmt.Sprintf("38388:%s:org.attnprotocol:promotion:test-promotion", pubkey)},
+		nostr.Tag{"a", fmt.Sprintf("38488:%s:org.attnprotocol:attention:test-attention", pubkey)},
+		nostr.Tag{"p", pubkey},
+		nostr.Tag{"p", pubkey},
+		nostr.Tag{"p", pubkey},
+		nostr.Tag{"p", pubkey},
+		nostr.Tag{"r", "wss://relay.nextblock.city"},
+		nostr.Tag{"k", "34236"},
+	)
+
+	event.ID = event.GetID()
+	return event
+}
+
+// createTestConfirmationEvent creates a test BILLBOARD_CONFIRMATION (kind 38588) or
+// ATTENTION_CONFIRMATION (kind 38688) event for the match created by createTestMatchEvent
+func createTestConfirmationEvent(kind int, pubkey string, block_height int, match_event_id string) *nostr.Event {
+	event_type := map[int]string{38588: "billboard-confirmation", 38688: "attention-confirmation"}[kind]
+	content := fmt.Sprintf(`{
+		"ref_match_event_id": "%s",
+		"ref_match_id": "test-match",
+		"ref_marketplace_pubkey": "%s",
+		"ref_billboard_pubkey": "%s",
+		"ref_promotion_pubkey": "%s",
+		"ref_attention_pubkey": "%s",
+		"ref_marketplace_id": "test-marketplace",
+		"ref_billboard_id": "test-billboard",
+		"ref_promotion_id": "test-promotion",
+		"ref_attention_id": "test-attention"
+	}`, match_event_id, pubkey, pubkey, pubkey, pubkey)
+
+	event := createTestEvent(kind, pubkey, content)
+	event.Tags = append(event.Tags,
+		nostr.Tag{"d", fmt.Sprintf("org.attnprotocol:%s:test-confirmation", event_type)},
+		nostr.Tag{"t", fmt.Sprintf("%d", block_height)},
+		nostr.Tag{"e", match_event_id, "", "match"},
+		nostr.Tag{"e", generateTestPubkey()},
+		nostr.Tag{"e", generateTestPubkey()},
+		nostr.Tag{"e", generateTestPubkey()},
+		nostr.Tag{"e", generateTestPubkey()},
+	)
+	event.Tags = append(event.Tags, testChainTags(pubkey)...)
+
+	event.ID = event.GetID()
+	return event
+}
+
+// createTestMarketplaceConfirmationEvent creates a test MARKETPLACE_CONFIRMATION event (kind 38788)
+func createTestMarketplaceConfirmationEvent(pubkey string, block_height int, match_event_id string, billboard_confirmation_id string, attention_confirmation_id string) *nostr.Event {
+	content := fmt.Sprintf(`{
+		"ref_match_event_id": "%s",
+		"ref_match_id": "test-match",
+		"ref_billboard_confirmation_event_id": "%s",
+		"ref_attention_confirmation_event_id": "%s",
+		"ref_marketplace_pubkey": "%s",
+		"ref_billboard_pubkey": "%s",
+		"ref_promotion_pubkey": "%s",
+		"ref_attention_pubkey": "%s",
+		"ref_marketplace_id": "test-marketplace",
+		"ref_billboard_id": "test-billboard",
+		"ref_promotion_id": "test-promotion",
+		"ref_attention_id": "test-attention"
+	}`, match_event_id, billboard_confirmation_id, attention_confirmation_id, pubkey, pubkey, pubkey, pubkey)
+
+	event := createTestEvent(38788, pubkey, content)
+	event.Tags = append(event.Tags,
+		nostr.Tag{"d", "org.attnprotocol:marketplace-confirmation:test-confirmation"},
+		nostr.Tag{"t", fmt.Sprintf("%d", block_height)},
+		nostr.Tag{"e", match_event_id, "", "match"},
+		nostr.Tag{"e", billboard_confirmation_id, "", "billboard_confirmation"},
+		nostr.Tag{"e", attention_confirmation_id, "", "attention_confirmation"},
+		nostr.Tag{"e", generateTestPubkey()},
+		nostr.Tag{"e", generateTestPubkey()},
+		nostr.Tag{"e", generateTestPubkey()},
+		nostr.Tag{"e", generateTestPubkey()},
+	)
+	event.Tags = append(event.Tags, testChainTags(pubkey)...)
+
+	event.ID = event.GetID()
+	return event
+}
+
+// createTestPaymentConfirmationEvent creates a test ATTENTION_PAYMENT_CONFIRMATION event (kind 38988)
+func createTestPaymentConfirmationEvent(pubkey string, block_height int, match_event_id string, marketplace_confirmation_id string) *nostr.Event {
+	content := fmt.Sprintf(`{
+		"sats_received": 3000,
+		"payment_proof": "lnbc30u1ptest",
+		"ref_match_event_id": "%s",
+		"ref_match_id": "test-match",
+		"ref_marketplace_confirmation_event_id": "%s",
+		"ref_marketplace_pubkey": "%s",
+		"ref_billboard_pubkey": "%s",
+		"ref_promotion_pubkey": "%s",
+		"ref_attention_pubkey": "%s",
+		"ref_marketplace_id": "test-marketplace",
+		"ref_billboard_id": "test-billboard",
+		"ref_promotion_id": "test-promotion",
+		"ref_attention_id": "test-attention"
+	}`, match_event_id, marketplace_confirmation_id, pubkey, pubkey, pubkey, pubkey)
+
+	event := createTestEvent(38988, pubkey, content)
+	event.Tags = append(event.Tags,
+		nostr.Tag{"d", "org.attnprotocol:attention-payment-confirmation:test-confirmation"},
+		nostr.Tag{"t", fmt.Sprintf("%d", block_height)},
+		nostr.Tag{"e", match_event_id, "", "match"},
+		nostr.Tag{"e", marketplace_confirmation_id, "", "marketplace_confirmation"},
+	)
+	event.Tags = append(event.Tags, testChainTags(pubkey)...)
+
+	event.ID = event.GetID()
+	return event
+}
+
+// testChainTags returns the a, p and r tags shared by confirmation events for the test match
+func testChainTags(pubkey string) nostr.Tags {
+	return nostr.Tags{
+		nostr.Tag{"a", fmt.Sprintf("38188:%s:org.attnprotocol:marketplace:test-marketplace", pubkey)},
+		nostr.Tag{"a", fmt.Sprintf("38288:%s:org.attnprotocol:billboard:test-billboard", pubkey)},
+		nostr.Tag{"a", fmt.Sprintf("38388:%s:org.attnprotocol:promotion:test-promotion", pubkey)},
+		nostr.Tag{"a", fmt.Sprintf("38488:%s:org.attnprotocol:attention:test-attention", pubkey)},
+		nostr.Tag{"a", fmt.Sprintf("38888:%s:org.attnprotocol:match:test-match", pubkey)},
+		nostr.Tag{"p", pubkey},
+		nostr.Tag{"p", pubkey},
+		nostr.Tag{"p", pubkey},
+		nostr.Tag{"p", pubkey},
+		nostr.Tag{"r", "wss://relay.nextblock.city"},
+	}
+}
+
+// testResolver is an in-memory Resolver over a fixed set of events
+type testResolver struct {
+	events []*nostr.Event
+}
+
+func newTestResolver(events ...*nostr.Event) *testResolver {
+	return &testResolver{events: events}
+}
+
+func (r *testResolver) GetEventByID(ctx context.Context, id string) (*nostr.Event, error) {
+	for _, event := range r.events {
+		if event.ID == id {
+			return event, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *testResolver) GetEventByCoordinate(ctx context.Context, coordinate core.Coordinate) (*nostr.Event, error) {
+	for _, event := range r.events {
+		if event.Kind == coordinate.Kind && event.PubKey == coordinate.Pubkey && getTagValue(event, "d") == coordinate.DTag {
+			return event, nil
+		}
+	}
+	return nil, nil
+}
+
+// withContentField returns a copy of the event with a content field set to value
+func withContentField(event *nostr.Event, field string, value interface{}) *nostr.Event {
+	var content_data map[string]interface{}
+	json.Unmarshal([]byte(event.Content), &content_data)
+	content_data[field] = value
+	content, _ := json.Marshal(content_data)
+
+	copied := *event
+	copied.Content = string(content)
+	copied.ID = copied.GetID()
+	return &copied
+}
+
+// createTestBlockEvent creates a test City Protocol BLOCK event (kind 38808)
+func createTestBlockEvent(pubkey string, block_height int, block_hash string) *nostr.Event {
+	block_id := fmt.Sprintf("org.cityprotocol:block:%d:%s", block_height, block_hash)
+	content := fmt.Sprintf(`{
+		"block_height": %d,
+		"block_hash": "%s",
+		"block_time": 1700000000,
+		"previous_hash": "%s",
+		"ref_clock_pubkey": "%s",
+		"ref_block_id": "%s"
+	}`, block_height, block_hash, block_hash, pubkey, block_id)
+
+	event := createTestEvent(core.KindCityBlock, pubkey, content)
+	event.Tags = append(event.Tags,
+		nostr.Tag{"d", block_id},
+		nostr.Tag{"p", pubkey},
+	)
+
+	event.ID = event.GetID()
+	return event
+}
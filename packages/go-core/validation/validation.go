@@ -12,8 +12,12 @@ import (
 
 // ValidationResult represents the result of event validation.
 // It indicates whether an event is valid and provides an error message if invalid.
+// Failed results also carry a stable Code and, where one applies, the offending
+// tag name or content field in Field.
 type ValidationResult struct {
 	Valid   bool
+	Code    ErrorCode
+	Field   string
 	Message string
 }
 
@@ -39,19 +43,19 @@ func IsATTNProtocolKind(kind int) bool {
 // ValidateATTNEvent validates an ATTN Protocol event based on its kind.
 // This only validates ATTN Protocol kinds (38188-38988).
 // For Block events (38808) and supporting kinds, use City Protocol validation.
+// Only official Nostr tags are accepted; use ValidateATTNEventWithOptions to choose
+// a stricter or more lenient profile.
 //
 // Parameters:
 //   - event: The Nostr event to validate
 //
 // Returns a ValidationResult indicating if the event is valid and any error message.
 func ValidateATTNEvent(event *nostr.Event) ValidationResult {
-	// Validate that only official Nostr tags are used for ATTN Protocol events
-	if ATTNProtocolKinds[event.Kind] {
-		if tag_result := validateOfficialTagsOnly(event); !tag_result.Valid {
-			return tag_result
-		}
-	}
+	return ValidateATTNEventWithOptions(event, DefaultOptions())
+}
 
+// validateKind runs the validator for the event kind.
+func validateKind(event *nostr.Event) ValidationResult {
 	switch event.Kind {
 	case 38188:
 		return ValidateMarketplaceEvent(event)
@@ -74,6 +78,7 @@ func ValidateATTNEvent(event *nostr.Event) ValidationResult {
 	default:
 		return ValidationResult{
 			Valid:   false,
+			Code:    CodeUnsupportedKind,
 			Message: "Not an ATTN Protocol event kind",
 		}
 	}
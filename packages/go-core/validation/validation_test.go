@@ -1,6 +1,9 @@
 package validation
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -205,3 +208,540 @@ func TestIsATTNProtocolKind(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateCityBlockEvent_Valid(t *testing.T) {
+	pubkey := generateTestPubkey()
+	event := createTestBlockEvent(pubkey, 870500, strings.Repeat("ab", 32))
+
+	result := ValidateCityBlockEvent(event)
+	if !result.Valid {
+		t.Errorf("Expected valid block event, got: %s", result.Message)
+	}
+}
+
+func TestValidateCityBlockEvent_HeightMismatch(t *testing.T) {
+	pubkey := generateTestPubkey()
+	block_hash := strings.Repeat("ab", 32)
+	event := createTestBlockEvent(pubkey, 870500, block_hash)
+	event.Tags[0] = nostr.Tag{"d", "org.cityprotocol:block:870501:" + block_hash}
+
+	result := ValidateCityBlockEvent(event)
+	if result.Valid {
+		t.Error("Expected invalid block event (d tag height disagrees with content), got valid")
+	}
+}
+
+func TestValidateCityBlockEvent_InvalidHash(t *testing.T) {
+	pubkey := generateTestPubkey()
+	event := createTestBlockEvent(pubkey, 870500, "not-a-hash")
+
+	result := ValidateCityBlockEvent(event)
+	if result.Valid {
+		t.Error("Expected invalid block event (malformed hash), got valid")
+	}
+}
+
+func TestValidateATTNEvent_ErrorCodes(t *testing.T) {
+	pubkey := generateTestPubkey()
+
+	without_tag := func(event *nostr.Event, name string) *nostr.Event {
+		var new_tags nostr.Tags
+		for _, tag := range event.Tags {
+			if tag[0] != name {
+				new_tags = append(new_tags, tag)
+			}
+		}
+		event.Tags = new_tags
+		return event
+	}
+	with_tag := func(event *nostr.Event, tag nostr.Tag) *nostr.Event {
+		for i, existing := range event.Tags {
+			if existing[0] == tag[0] {
+				event.Tags[i] = tag
+				return event
+			}
+		}
+		event.Tags = append(event.Tags, tag)
+		return event
+	}
+
+	tests := []struct {
+		name  string
+		event *nostr.Event
+		code  ErrorCode
+		field string
+	}{
+		{"unsupported kind", createTestEvent(1, pubkey, "{}"), CodeUnsupportedKind, ""},
+		{"non-standard tag", with_tag(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), nostr.Tag{"x", "1"}), CodeNonStandardTag, "x"},
+		{"missing d tag", without_tag(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), "d"), CodeMissingDTag, "d"},
+		{"bad d tag", with_tag(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), nostr.Tag{"d", "org.attnprotocol:match:p1"}), CodeBadDTag, "d"},
+		{"missing block height", without_tag(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), "t"), CodeMissingBlockHeight, "t"},
+		{"bad block height", with_tag(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), nostr.Tag{"t", "invalid"}), CodeBadBlockHeight, "t"},
+		{"missing coordinate", without_tag(createTestAttentionEvent(pubkey, 870500, pubkey), "a"), CodeMissingCoordinate, "a"},
+		{"invalid json", func() *nostr.Event {
+			event := createTestMarketplaceEvent(pubkey, 870500)
+			event.Content = "invalid json"
+			return event
+		}(), CodeInvalidJSON, ""},
+		{"missing field", func() *nostr.Event {
+			event := createTestMarketplaceEvent(pubkey, 870500)
+			event.Content = "{}"
+			return event
+		}(), CodeMissingField, "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateATTNEvent(tt.event)
+			if result.Valid {
+				t.Fatal("Expected invalid event, got valid")
+			}
+			if result.Code != tt.code {
+				t.Errorf("Expected code %q, got %q (%s)", tt.code, result.Code, result.Message)
+			}
+			if result.Field != tt.field {
+				t.Errorf("Expected field %q, got %q", tt.field, result.Field)
+			}
+		})
+	}
+}
+
+func TestValidationResult_Reason(t *testing.T) {
+	result := ValidationResult{Valid: false, Code: CodeMissingDTag, Field: "d", Message: "Missing 'd' tag"}
+	if got := result.Reason(); got != "invalid: missing_d_tag: Missing 'd' tag" {
+		t.Errorf("Unexpected reason: %s", got)
+	}
+
+	valid := ValidationResult{Valid: true, Message: "Valid"}
+	if got := valid.Reason(); got != "" {
+		t.Errorf("Expected empty reason for valid result, got: %s", got)
+	}
+}
+
+func TestValidateMatchEvent_Valid(t *testing.T) {
+	pubkey := generateTestPubkey()
+	event := createTestMatchEvent(pubkey, 870500)
+
+	result := ValidateMatchEvent(event)
+	if !result.Valid {
+		t.Errorf("Expected valid match event, got: %s", result.Message)
+	}
+}
+
+func TestValidateBillboardConfirmationEvent_Valid(t *testing.T) {
+	pubkey := generateTestPubkey()
+	event := createTestConfirmationEvent(38588, pubkey, 870500, generateTestPubkey())
+
+	result := ValidateBillboardConfirmationEvent(event)
+	if !result.Valid {
+		t.Errorf("Expected valid billboard confirmation event, got: %s", result.Message)
+	}
+}
+
+func TestValidateATTNEvent_ContentMismatch(t *testing.T) {
+	pubkey := generateTestPubkey()
+	other_pubkey := generateTestPubkey()
+
+	replace_content := func(event *nostr.Event, old string, new string) *nostr.Event {
+		event.Content = strings.Replace(event.Content, old, new, 1)
+		return event
+	}
+
+	tests := []struct {
+		name  string
+		event *nostr.Event
+		field string
+	}{
+		{
+			"promotion marketplace id differs from a tag",
+			replace_content(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), `"ref_marketplace_id": "test-marketplace"`, `"ref_marketplace_id": "other-marketplace"`),
+			"ref_marketplace_id",
+		},
+		{
+			"promotion id differs from own d tag",
+			replace_content(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), `"ref_promotion_id": "test-promotion"`, `"ref_promotion_id": "other-promotion"`),
+			"ref_promotion_id",
+		},
+		{
+			"promotion pubkey differs from author",
+			replace_content(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), `"ref_promotion_pubkey": "`+pubkey, `"ref_promotion_pubkey": "`+other_pubkey),
+			"ref_promotion_pubkey",
+		},
+		{
+			"marketplace block id differs from block coordinate",
+			replace_content(createTestMarketplaceEvent(pubkey, 870500), `"ref_block_id": "org.cityprotocol:block:870500`, `"ref_block_id": "org.cityprotocol:block:870501`),
+			"ref_block_id",
+		},
+		{
+			"attention blocked list differs from list coordinate",
+			replace_content(createTestAttentionEvent(pubkey, 870500, pubkey), `"blocked_promotions_id": "org.attnprotocol:promotion:blocked"`, `"blocked_promotions_id": "org.attnprotocol:promotion:other"`),
+			"blocked_promotions_id",
+		},
+		{
+			"match attention id differs from a tag",
+			replace_content(createTestMatchEvent(pubkey, 870500), `"ref_attention_id": "test-attention"`, `"ref_attention_id": "other-attention"`),
+			"ref_attention_id",
+		},
+		{
+			"confirmation match event id differs from marked e tag",
+			func() *nostr.Event {
+				event := createTestConfirmationEvent(38588, pubkey, 870500, generateTestPubkey())
+				event.Tags[2] = nostr.Tag{"e", generateTestPubkey(), "", "match"}
+				return event
+			}(),
+			"ref_match_event_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateATTNEvent(tt.event)
+			if result.Valid {
+				t.Fatal("Expected invalid event (content disagrees with tags), got valid")
+			}
+			if result.Code != CodeContentMismatch {
+				t.Errorf("Expected code %q, got %q (%s)", CodeContentMismatch, result.Code, result.Message)
+			}
+			if result.Field != tt.field {
+				t.Errorf("Expected field %q, got %q", tt.field, result.Field)
+			}
+		})
+	}
+}
+
+func TestValidateWithResolver_MatchChain(t *testing.T) {
+	pubkey := generateTestPubkey()
+	promotion := createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey)
+	attention := createTestAttentionEvent(pubkey, 870500, pubkey)
+	match := createTestMatchEvent(pubkey, 870500)
+
+	result := ValidateWithResolver(match, newTestResolver(promotion, attention))
+	if !result.Valid {
+		t.Errorf("Expected valid match chain, got: %s", result.Message)
+	}
+
+	result = ValidateWithResolver(match, newTestResolver(promotion))
+	if result.Valid || result.Code != CodeUnresolvedReference {
+		t.Errorf("Expected unresolved attention reference, got valid=%v code=%q", result.Valid, result.Code)
+	}
+}
+
+func TestValidateWithResolver_ConfirmationChain(t *testing.T) {
+	pubkey := generateTestPubkey()
+	match := createTestMatchEvent(pubkey, 870500)
+	billboard_confirmation := createTestConfirmationEvent(38588, pubkey, 870500, match.ID)
+	attention_confirmation := createTestConfirmationEvent(38688, pubkey, 870500, match.ID)
+	marketplace_confirmation := createTestMarketplaceConfirmationEvent(pubkey, 870500, match.ID, billboard_confirmation.ID, attention_confirmation.ID)
+	resolver := newTestResolver(match, billboard_confirmation, attention_confirmation)
+
+	for _, event := range []*nostr.Event{billboard_confirmation, attention_confirmation, marketplace_confirmation} {
+		if result := ValidateWithResolver(event, resolver); !result.Valid {
+			t.Errorf("Expected valid kind %d confirmation chain, got: %s", event.Kind, result.Message)
+		}
+	}
+}
+
+func TestValidateWithResolver_UnknownMatch(t *testing.T) {
+	pubkey := generateTestPubkey()
+	confirmation := createTestConfirmationEvent(38588, pubkey, 870500, generateTestPubkey())
+
+	result := ValidateWithResolver(confirmation, newTestResolver())
+	if result.Valid || result.Code != CodeUnresolvedReference {
+		t.Errorf("Expected unresolved match reference, got valid=%v code=%q", result.Valid, result.Code)
+	}
+}
+
+func TestValidateWithResolver_MatchCoordinateMismatch(t *testing.T) {
+	pubkey := generateTestPubkey()
+	match := createTestMatchEvent(pubkey, 870500)
+	// Point the match at a different promotion than the confirmation names
+	for i, tag := range match.Tags {
+		if tag[0] == "a" && strings.HasPrefix(tag[1], "38388:") {
+			match.Tags[i] = nostr.Tag{"a", fmt.Sprintf("38388:%s:org.attnprotocol:promotion:other-promotion", pubkey)}
+		}
+	}
+	match.ID = match.GetID()
+	confirmation := createTestConfirmationEvent(38588, pubkey, 870500, match.ID)
+
+	result := ValidateWithResolver(confirmation, newTestResolver(match))
+	if result.Valid || result.Code != CodeReferenceMismatch {
+		t.Errorf("Expected reference mismatch, got valid=%v code=%q (%s)", result.Valid, result.Code, result.Message)
+	}
+}
+
+func TestValidateWithResolver_PartyConfirmationForOtherMatch(t *testing.T) {
+	pubkey := generateTestPubkey()
+	match := createTestMatchEvent(pubkey, 870500)
+	billboard_confirmation := createTestConfirmationEvent(38588, pubkey, 870500, generateTestPubkey())
+	attention_confirmation := createTestConfirmationEvent(38688, pubkey, 870500, match.ID)
+	marketplace_confirmation := createTestMarketplaceConfirmationEvent(pubkey, 870500, match.ID, billboard_confirmation.ID, attention_confirmation.ID)
+
+	result := ValidateWithResolver(marketplace_confirmation, newTestResolver(match, billboard_confirmation, attention_confirmation))
+	if result.Valid || result.Code != CodeReferenceMismatch {
+		t.Errorf("Expected reference mismatch, got valid=%v code=%q (%s)", result.Valid, result.Code, result.Message)
+	}
+}
+
+func TestVerifyEvent(t *testing.T) {
+	secret_key := nostr.GeneratePrivateKey()
+	pubkey, _ := nostr.GetPublicKey(secret_key)
+
+	signed := func() *nostr.Event {
+		event := createTestMarketplaceEvent(pubkey, 870500)
+		if err := event.Sign(secret_key); err != nil {
+			t.Fatalf("failed to sign event: %v", err)
+		}
+		return event
+	}
+
+	if result := ValidateEvent(signed()); !result.Valid {
+		t.Fatalf("Expected signed event to be valid, got: %s", result.Message)
+	}
+
+	tampered := signed()
+	tampered.Content = strings.Replace(tampered.Content, "test-marketplace", "other-marketplace", 1)
+	if result := VerifyEvent(tampered); result.Code != CodeBadID {
+		t.Errorf("Expected %s for tampered content, got %s: %s", CodeBadID, result.Code, result.Message)
+	}
+
+	// Recomputing the id does not make the old signature valid
+	tampered.ID = tampered.GetID()
+	if result := VerifyEvent(tampered); result.Code != CodeBadSignature {
+		t.Errorf("Expected %s for stale signature, got %s: %s", CodeBadSignature, result.Code, result.Message)
+	}
+
+	// Unsigned fixtures only pass when verification is skipped
+	unsigned := createTestMarketplaceEvent(pubkey, 870500)
+	if result := ValidateEvent(unsigned); result.Code != CodeBadSignature {
+		t.Errorf("Expected %s for unsigned event, got %s: %s", CodeBadSignature, result.Code, result.Message)
+	}
+	if result := ValidateTrustedEvent(unsigned); !result.Valid {
+		t.Errorf("Expected trusted event to be valid, got: %s", result.Message)
+	}
+}
+
+func TestValidateATTNEventWithOptions_Profiles(t *testing.T) {
+	pubkey := generateTestPubkey()
+	marketplace := createTestMarketplaceEvent(pubkey, 870500)
+
+	unknown_tag := createTestMarketplaceEvent(pubkey, 870500)
+	unknown_tag.Tags = append(unknown_tag.Tags, nostr.Tag{"client", "attn-test"})
+
+	unknown_field := withContentField(marketplace, "future_field", "value")
+	mistyped_optional := withContentField(marketplace, "billboard_count", "12")
+
+	tests := []struct {
+		name     string
+		event    *nostr.Event
+		standard ErrorCode
+		strict   ErrorCode
+		lenient  ErrorCode
+	}{
+		{"spec event", marketplace, "", "", ""},
+		{"unknown tag", unknown_tag, CodeNonStandardTag, CodeNonStandardTag, ""},
+		{"unknown content field", unknown_field, "", CodeUnknownField, ""},
+		{"mistyped optional field", mistyped_optional, "", CodeBadField, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profiles := []struct {
+				name string
+				opts Options
+				want ErrorCode
+			}{
+				{"default", DefaultOptions(), tt.standard},
+				{"strict", StrictOptions(), tt.strict},
+				{"lenient", LenientOptions(), tt.lenient},
+			}
+			for _, profile := range profiles {
+				result := ValidateATTNEventWithOptions(tt.event, profile.opts)
+				if result.Code != profile.want || result.Valid != (profile.want == "") {
+					t.Errorf("%s: expected code %q, got %q: %s", profile.name, profile.want, result.Code, result.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateATTNEventWithOptions_StrictAcceptsFixtures(t *testing.T) {
+	pubkey := generateTestPubkey()
+	match := createTestMatchEvent(pubkey, 870500)
+	events := []*nostr.Event{
+		createTestMarketplaceEvent(pubkey, 870500),
+		createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey),
+		createTestAttentionEvent(pubkey, 870500, pubkey),
+		match,
+		createTestConfirmationEvent(38588, pubkey, 870500, match.ID),
+		createTestMarketplaceConfirmationEvent(pubkey, 870500, match.ID, generateTestPubkey(), generateTestPubkey()),
+	}
+
+	for _, event := range events {
+		if result := ValidateATTNEventWithOptions(event, StrictOptions()); !result.Valid {
+			t.Errorf("kind %d: expected strict profile to accept fixture, got %s: %s", event.Kind, result.Code, result.Message)
+		}
+	}
+}
+
+func TestValidateATTNEventWithOptions_KindOverride(t *testing.T) {
+	pubkey := generateTestPubkey()
+	promotion := createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey)
+	promotion.Tags = append(promotion.Tags, nostr.Tag{"alt", "Promotion"})
+	promotion = withContentField(promotion, "campaign", "spring")
+
+	opts := StrictOptions().WithKind(38388, Rules{ExtraTags: []string{"alt"}, TypedFields: true})
+	if result := ValidateATTNEventWithOptions(promotion, opts); !result.Valid {
+		t.Fatalf("Expected kind override to accept promotion, got %s: %s", result.Code, result.Message)
+	}
+
+	// Other kinds keep the strict profile
+	marketplace := withContentField(createTestMarketplaceEvent(pubkey, 870500), "campaign", "spring")
+	if result := ValidateATTNEventWithOptions(marketplace, opts); result.Code != CodeUnknownField {
+		t.Errorf("Expected %s for marketplace, got %s: %s", CodeUnknownField, result.Code, result.Message)
+	}
+	if !opts.RulesFor(38188).RejectUnknownFields || StrictOptions().Kinds != nil {
+		t.Errorf("WithKind must not change the base profile")
+	}
+}
+
+func TestValidateATTNEvent_BillboardAndPaymentConfirmation(t *testing.T) {
+	pubkey := generateTestPubkey()
+	match := createTestMatchEvent(pubkey, 870500)
+	events := []*nostr.Event{
+		createTestBillboardEvent(pubkey, 870500),
+		createTestPaymentConfirmationEvent(pubkey, 870500, match.ID, generateTestPubkey()),
+	}
+
+	for _, event := range events {
+		if result := ValidateATTNEvent(event); !result.Valid {
+			t.Errorf("kind %d: expected valid event, got: %s", event.Kind, result.Message)
+		}
+	}
+}
+
+func TestValidateBatch(t *testing.T) {
+	secret_key := nostr.GeneratePrivateKey()
+	pubkey, _ := nostr.GetPublicKey(secret_key)
+
+	var events []*nostr.Event
+	for i := 0; i < 50; i++ {
+		event := createTestMarketplaceEvent(pubkey, 870500+i)
+		switch i % 3 {
+		case 1:
+			event.Tags = append(event.Tags, nostr.Tag{"client", "test"})
+		case 2:
+			event.Content = "not json"
+		}
+		if err := event.Sign(secret_key); err != nil {
+			t.Fatalf("failed to sign event: %v", err)
+		}
+		events = append(events, event)
+	}
+	// Unsigned fixtures only pass when verification is skipped
+	events = append(events, createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey))
+
+	results, err := ValidateBatch(context.Background(), events, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(events) {
+		t.Fatalf("expected %d results, got %d", len(events), len(results))
+	}
+	for i, event := range events {
+		if expected := ValidateEvent(event); results[i] != expected {
+			t.Errorf("event %d: expected %+v, got %+v", i, expected, results[i])
+		}
+	}
+
+	results, err = ValidateBatchWithOptions(context.Background(), events, 0, BatchOptions{SkipVerification: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last := results[len(results)-1]; !last.Valid {
+		t.Errorf("Expected unsigned event to be valid without verification, got: %s", last.Message)
+	}
+}
+
+func TestValidateBatch_Cancelled(t *testing.T) {
+	pubkey := generateTestPubkey()
+	events := []*nostr.Event{createTestMarketplaceEvent(pubkey, 870500), createTestMarketplaceEvent(pubkey, 870501)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := ValidateBatch(ctx, events, 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(results) != len(events) {
+		t.Errorf("expected %d results, got %d", len(events), len(results))
+	}
+
+	if results, err := ValidateBatch(ctx, nil, 2); err != nil || len(results) != 0 {
+		t.Errorf("expected empty batch to succeed, got %d results, %v", len(results), err)
+	}
+}
+
+func TestValidateATTNEvent_SatsAmounts(t *testing.T) {
+	pubkey := generateTestPubkey()
+
+	tests := []struct {
+		name  string
+		event *nostr.Event
+		field string
+	}{
+		{"fractional bid", withContentField(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), "bid", 5000.5), "bid"},
+		{"bid beyond supply", withContentField(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), "bid", 2.2e15), "bid"},
+		{"negative ask", withContentField(createTestAttentionEvent(pubkey, 870500, pubkey), "ask", -1), "ask"},
+		{"fee beyond supply", withContentField(createTestMarketplaceEvent(pubkey, 870500), "match_fee_sats", 1e300), "match_fee_sats"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateATTNEvent(tt.event)
+			if result.Valid || result.Code != CodeBadField || result.Field != tt.field {
+				t.Errorf("expected %s on %s, got valid=%t %s/%s: %s", CodeBadField, tt.field, result.Valid, result.Code, result.Field, result.Message)
+			}
+		})
+	}
+
+	if result := ValidateATTNEvent(withContentField(createTestPromotionEvent(pubkey, 870500, pubkey, pubkey, pubkey), "bid", 2.1e15)); !result.Valid {
+		t.Errorf("Expected bid of the full supply to be valid, got: %s", result.Message)
+	}
+}
+
+func TestValidateListEvent(t *testing.T) {
+	pubkey := generateTestPubkey()
+	list := func(kind int, tags ...nostr.Tag) *nostr.Event {
+		return &nostr.Event{Kind: kind, PubKey: pubkey, Tags: tags, Content: "encrypted-private-entries"}
+	}
+
+	tests := []struct {
+		name  string
+		event *nostr.Event
+		code  ErrorCode
+		field string
+	}{
+		{"blocked promotions", list(30000, nostr.Tag{"d", "org.attnprotocol:promotion:blocked"}, nostr.Tag{"t", "870500"}, nostr.Tag{"a", fmt.Sprintf("38388:%s:org.attnprotocol:promotion:p1", pubkey)}, nostr.Tag{"e", pubkey}), "", ""},
+		{"empty trust list", list(30003, nostr.Tag{"d", "org.attnprotocol:billboard:trusted"}), "", ""},
+		{"wrong kind", list(38488, nostr.Tag{"d", "org.attnprotocol:promoter:blocked"}), CodeUnsupportedKind, ""},
+		{"missing d tag", list(30000), CodeMissingDTag, "d"},
+		{"unknown list type", list(30000, nostr.Tag{"d", "org.attnprotocol:video:blocked"}), CodeBadDTag, "d"},
+		{"bad block height", list(30000, nostr.Tag{"d", "org.attnprotocol:promoter:blocked"}, nostr.Tag{"t", "abc"}), CodeBadBlockHeight, "t"},
+		{"wrong coordinate kind", list(30000, nostr.Tag{"d", "org.attnprotocol:marketplace:trusted"}, nostr.Tag{"a", fmt.Sprintf("38288:%s:org.attnprotocol:billboard:b1", pubkey)}), CodeBadCoordinate, "a"},
+		{"bad pubkey", list(30000, nostr.Tag{"d", "org.attnprotocol:promoter:blocked"}, nostr.Tag{"p", "npub1"}), CodeBadTag, "p"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateListEvent(tt.event)
+			if tt.code == "" {
+				if !result.Valid {
+					t.Errorf("Expected valid list, got: %s", result.Message)
+				}
+				return
+			}
+			if result.Valid || result.Code != tt.code || result.Field != tt.field {
+				t.Errorf("expected %s on %q, got valid=%t %s/%s: %s", tt.code, tt.field, result.Valid, result.Code, result.Field, result.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package validation
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// The golden corpus and content schemas are shared with the TypeScript packages and the
+// other Go modules. Regenerate them with: go test ./validation -run 'TestVectors|TestContentSchema' -update
+var update_golden = flag.Bool("update", false, "regenerate the golden test vectors and content schemas")
+
+const (
+	vectorsDir = "../../protocol/test-vectors"
+
+	// vectorSecretKey signs every vector. It is a published test key; never use it for real events.
+	vectorSecretKey = "0000000000000000000000000000000000000000000000000000000000000001"
+
+	vectorCreatedAt   = 1700000000
+	vectorBlockHeight = 870500
+)
+
+// vectorFile is the on-disk layout of one kind's vectors.
+type vectorFile struct {
+	Kind    int          `json:"kind"`
+	Name    string       `json:"name"`
+	Vectors []testVector `json:"vectors"`
+}
+
+// testVector is a signed event and the result every implementation must report for it.
+type testVector struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Valid       bool         `json:"valid"`
+	Code        ErrorCode    `json:"code,omitempty"`
+	Field       string       `json:"field,omitempty"`
+	Event       *nostr.Event `json:"event"`
+}
+
+// vectorMutation breaks a valid event for one failure reason.
+type vectorMutation struct {
+	code        ErrorCode
+	description string
+	kinds       []int
+
+	// mutate edits the event before it is signed; sign edits it after.
+	mutate func(event *nostr.Event)
+	sign   func(event *nostr.Event)
+}
+
+var confirmationKinds = []int{38588, 38688, 38788, 38988}
+
+var vectorMutations = []vectorMutation{
+	{
+		code:        CodeBadID,
+		description: "id is not the hash of the event",
+		sign:        func(event *nostr.Event) { event.ID = strings.Repeat("0", 64) },
+	},
+	{
+		code:        CodeBadSignature,
+		description: "signature does not verify against the pubkey",
+		sign:        func(event *nostr.Event) { event.Sig = strings.Repeat("0", 128) },
+	},
+	{
+		code:        CodeNonStandardTag,
+		description: "carries a tag outside d, t, a, e, p, r, k, u",
+		kinds:       attnKinds(),
+		mutate:      func(event *nostr.Event) { event.Tags = append(event.Tags, nostr.Tag{"client", "attn-test-vectors"}) },
+	},
+	{
+		code:        CodeMissingDTag,
+		description: "has no d tag",
+		mutate:      func(event *nostr.Event) { event.Tags = removeTags(event.Tags, "d") },
+	},
+	{
+		code:        CodeBadDTag,
+		description: "d tag is not in the protocol namespace",
+		mutate:      func(event *nostr.Event) { setTag(event.Tags, "d", "not-a-namespaced-identifier") },
+	},
+	{
+		code:        CodeMissingBlockHeight,
+		description: "has no t tag",
+		kinds:       attnKinds(),
+		mutate:      func(event *nostr.Event) { event.Tags = removeTags(event.Tags, "t") },
+	},
+	{
+		code:        CodeBadBlockHeight,
+		description: "t tag is not numeric",
+		kinds:       attnKinds(),
+		mutate:      func(event *nostr.Event) { setTag(event.Tags, "t", "eight-hundred") },
+	},
+	{
+		code:        CodeMissingCoordinate,
+		description: "has no a tags",
+		kinds:       attnKinds(),
+		mutate:      func(event *nostr.Event) { event.Tags = removeTags(event.Tags, "a") },
+	},
+	{
+		code:        CodeBadCoordinate,
+		description: "protocol a tag coordinates have a non-namespaced identifier",
+		kinds:       attnKinds(),
+		mutate: func(event *nostr.Event) {
+			for _, tag := range event.Tags {
+				if len(tag) < 2 || tag[0] != "a" {
+					continue
+				}
+				if coordinate, err := core.ParseCoordinate(tag[1]); err == nil && coordinate.IsProtocol() {
+					coordinate.DTag = "not-a-namespaced-identifier"
+					tag[1] = coordinate.String()
+				}
+			}
+		},
+	},
+	{
+		code:        CodeMissingMarker,
+		description: "has no marked e tags",
+		kinds:       confirmationKinds,
+		mutate: func(event *nostr.Event) {
+			var tags nostr.Tags
+			for _, tag := range event.Tags {
+				if len(tag) < 4 || tag[0] != "e" {
+					tags = append(tags, tag)
+				}
+			}
+			event.Tags = tags
+		},
+	},
+	{
+		code:        CodeMissingTag,
+		description: "has no p tags",
+		kinds:       attnKinds(),
+		mutate:      func(event *nostr.Event) { event.Tags = removeTags(event.Tags, "p") },
+	},
+	{
+		code:        CodeInvalidJSON,
+		description: "content is not JSON",
+		mutate:      func(event *nostr.Event) { event.Content = "not json" },
+	},
+	{
+		code:        CodeMissingField,
+		description: "content lacks ref_marketplace_id",
+		kinds:       attnKinds(),
+		mutate: func(event *nostr.Event) {
+			editContent(event, func(c map[string]interface{}) { delete(c, "ref_marketplace_id") })
+		},
+	},
+	{
+		code:        CodeBadField,
+		description: "content ref_marketplace_id is not a string",
+		kinds:       attnKinds(),
+		mutate: func(event *nostr.Event) {
+			editContent(event, func(c map[string]interface{}) { c["ref_marketplace_id"] = 42 })
+		},
+	},
+	{
+		code:        CodeContentMismatch,
+		description: "content ref_marketplace_id disagrees with the marketplace coordinate",
+		kinds:       attnKinds(),
+		mutate: func(event *nostr.Event) {
+			editContent(event, func(c map[string]interface{}) { c["ref_marketplace_id"] = "other-marketplace" })
+		},
+	},
+	{
+		code:        CodeContentMismatch,
+		description: "content block_height disagrees with the d tag",
+		kinds:       []int{core.KindCityBlock},
+		mutate: func(event *nostr.Event) {
+			editContent(event, func(c map[string]interface{}) { c["block_height"] = vectorBlockHeight + 1 })
+		},
+	},
+}
+
+func TestVectors(t *testing.T) {
+	if *update_golden {
+		writeVectors(t)
+	}
+
+	files := loadVectorFiles(t)
+	if len(files) != len(ATTNProtocolKinds)+1 {
+		t.Fatalf("expected vectors for %d kinds, found %d", len(ATTNProtocolKinds)+1, len(files))
+	}
+
+	for _, file := range files {
+		for _, vector := range file.Vectors {
+			t.Run(file.Name+"/"+vector.Name, func(t *testing.T) {
+				result := ValidateEvent(vector.Event)
+				if result.Valid != vector.Valid || result.Code != vector.Code || result.Field != vector.Field {
+					t.Errorf("expected valid=%t code=%q field=%q, got valid=%t code=%q field=%q: %s",
+						vector.Valid, vector.Code, vector.Field, result.Valid, result.Code, result.Field, result.Message)
+				}
+			})
+		}
+	}
+}
+
+// writeVectors regenerates the corpus from the test fixtures.
+func writeVectors(t *testing.T) {
+	pubkey, err := nostr.GetPublicKey(vectorSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sign the chain in order so confirmations reference real event ids
+	match := signVector(t, createTestMatchEvent(pubkey, vectorBlockHeight))
+	billboard_confirmation := signVector(t, createTestConfirmationEvent(38588, pubkey, vectorBlockHeight, match.ID))
+	attention_confirmation := signVector(t, createTestConfirmationEvent(38688, pubkey, vectorBlockHeight, match.ID))
+	marketplace_confirmation := signVector(t, createTestMarketplaceConfirmationEvent(pubkey, vectorBlockHeight, match.ID, billboard_confirmation.ID, attention_confirmation.ID))
+
+	fixtures := []*nostr.Event{
+		createTestMarketplaceEvent(pubkey, vectorBlockHeight),
+		createTestBillboardEvent(pubkey, vectorBlockHeight),
+		createTestPromotionEvent(pubkey, vectorBlockHeight, pubkey, pubkey, pubkey),
+		createTestAttentionEvent(pubkey, vectorBlockHeight, pubkey),
+		billboard_confirmation,
+		attention_confirmation,
+		marketplace_confirmation,
+		match,
+		createTestPaymentConfirmationEvent(pubkey, vectorBlockHeight, match.ID, marketplace_confirmation.ID),
+		createTestBlockEvent(pubkey, vectorBlockHeight, strings.Repeat("ab", 32)),
+	}
+
+	if err := os.MkdirAll(vectorsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fixture := range fixtures {
+		event_type, _ := core.EventTypeForKind(fixture.Kind)
+		file := vectorFile{Kind: fixture.Kind, Name: strings.ToLower(event_type)}
+
+		valid := signVector(t, copyEvent(fixture))
+		file.Vectors = append(file.Vectors, testVector{Name: "valid", Description: "conforms to ATTN-01", Valid: true, Event: valid})
+
+		for _, mutation := range vectorMutations {
+			if mutation.kinds != nil && !containsKind(mutation.kinds, fixture.Kind) {
+				continue
+			}
+
+			event := copyEvent(fixture)
+			if mutation.mutate != nil {
+				mutation.mutate(event)
+			}
+			event = signVector(t, event)
+			if mutation.sign != nil {
+				mutation.sign(event)
+			}
+
+			// Every vector must fail for exactly the reason it was written for
+			result := ValidateEvent(event)
+			if result.Valid || result.Code != mutation.code {
+				t.Fatalf("%s %s: mutation produced code %q: %s", file.Name, mutation.code, result.Code, result.Message)
+			}
+
+			file.Vectors = append(file.Vectors, testVector{
+				Name:        string(mutation.code),
+				Description: mutation.description,
+				Code:        result.Code,
+				Field:       result.Field,
+				Event:       event,
+			})
+		}
+
+		data, err := json.MarshalIndent(file, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(vectorsDir, fmt.Sprintf("%d-%s.json", file.Kind, strings.ReplaceAll(file.Name, "_", "-")))
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func loadVectorFiles(t *testing.T) []vectorFile {
+	paths, err := filepath.Glob(filepath.Join(vectorsDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(paths)
+
+	var files []vectorFile
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var file vectorFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// signVector fixes the timestamp and signs the event with the vector key.
+func signVector(t *testing.T, event *nostr.Event) *nostr.Event {
+	event.CreatedAt = vectorCreatedAt
+	if err := event.Sign(vectorSecretKey); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func copyEvent(event *nostr.Event) *nostr.Event {
+	copied := *event
+	copied.Tags = make(nostr.Tags, len(event.Tags))
+	for i, tag := range event.Tags {
+		copied.Tags[i] = append(nostr.Tag{}, tag...)
+	}
+	return &copied
+}
+
+func attnKinds() []int {
+	kinds := make([]int, 0, len(ATTNProtocolKinds))
+	for kind := range ATTNProtocolKinds {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+func containsKind(kinds []int, kind int) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func removeTags(tags nostr.Tags, name string) nostr.Tags {
+	var kept nostr.Tags
+	for _, tag := range tags {
+		if len(tag) == 0 || tag[0] != name {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+func setTag(tags nostr.Tags, name string, value string) {
+	for _, tag := range tags {
+		if len(tag) >= 2 && tag[0] == name {
+			tag[1] = value
+			return
+		}
+	}
+}
+
+func editContent(event *nostr.Event, edit func(content map[string]interface{})) {
+	var content map[string]interface{}
+	json.Unmarshal([]byte(event.Content), &content)
+	edit(content)
+	data, _ := json.Marshal(content)
+	event.Content = string(data)
+}
@@ -8,7 +8,7 @@
 //
 //	attn := framework.NewAttn(framework.Config{
 //	    RelaysNoAuth: []string{"wss://relay.example.com"},
-//	    PrivateKey:   privateKeyBytes,
+//	    Signer:       signer,
 //	})
 //
 //	attn.OnPromotionEvent(func(ctx context.Context, hookCtx hooks.PromotionEventContext) error {
@@ -23,17 +23,21 @@ package framework
 
 import (
 	"context"
-	"encoding/json"
+	"encoding/hex"
+	"errors"
 	"sync"
 
 	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/validation"
 	"github.com/joinnextblock/attn-protocol/go-framework/hooks"
+	sdkrelay "github.com/joinnextblock/attn-protocol/go-sdk/relay"
 	"github.com/nbd-wtf/go-nostr"
 )
 
 // Config holds configuration for the ATTN framework.
 type Config struct {
-	// RelaysAuth contains relay URLs requiring NIP-42 authentication.
+	// RelaysAuth contains relay URLs requiring NIP-42 authentication. The framework
+	// answers their AUTH challenge with the configured signer.
 	RelaysAuth []string
 
 	// RelaysNoAuth contains relay URLs not requiring authentication.
@@ -45,7 +49,11 @@ type Config struct {
 	// RelaysWriteNoAuth contains write relay URLs not requiring auth.
 	RelaysWriteNoAuth []string
 
-	// PrivateKey is the 32-byte private key for signing events.
+	// Signer signs events published by the framework, e.g. a NIP-46 remote signer
+	// that keeps the key out of process memory. Takes precedence over PrivateKey.
+	Signer core.Signer
+
+	// PrivateKey is the 32-byte private key for signing events when no Signer is set.
 	PrivateKey []byte
 
 	// NodePubkeys contains trusted node pubkeys for block events.
@@ -65,33 +73,90 @@ type Config struct {
 
 	// DeduplicateEvents enables event deduplication.
 	DeduplicateEvents bool
+
+	// ValidateEvents runs go-core validation before dispatch and drops invalid events.
+	ValidateEvents bool
+
+	// ValidationOptions selects the validation profile used when ValidateEvents is set.
+	// The zero value applies go-core's default rules.
+	ValidationOptions validation.Options
+
+	// SkipVerification disables event id and signature checks before dispatch.
+	// Only set it when every configured relay is trusted to verify events itself.
+	SkipVerification bool
 }
 
 // Attn is the main framework class for ATTN Protocol applications.
 type Attn struct {
 	config     Config
+	signer     core.Signer
+	signerErr  error
 	emitter    *hooks.Emitter
 	relays     []*nostr.Relay
 	mu         sync.RWMutex
 	connected  bool
 	seenEvents map[string]struct{}
+	rejects    map[validation.ErrorCode]int
 }
 
 // NewAttn creates a new ATTN framework instance.
 func NewAttn(config Config) *Attn {
-	return &Attn{
+	a := &Attn{
 		config:     config,
+		signer:     config.Signer,
 		emitter:    hooks.NewEmitter(),
 		relays:     make([]*nostr.Relay, 0),
 		seenEvents: make(map[string]struct{}),
+		rejects:    make(map[validation.ErrorCode]int),
+	}
+
+	// Fall back to an in-memory signer; a bad key is reported on first use
+	if a.signer == nil && len(config.PrivateKey) > 0 {
+		signer, err := core.NewKeySigner(hex.EncodeToString(config.PrivateKey))
+		if err != nil {
+			a.signerErr = ErrInvalidPrivateKey
+		} else {
+			a.signer = signer
+		}
+	}
+
+	return a
+}
+
+// Signer returns the configured signer, or nil if none is configured.
+func (a *Attn) Signer() core.Signer {
+	return a.signer
+}
+
+// SignEvent signs an event with the configured signer.
+// Returns ErrPrivateKeyRequired if neither Signer nor PrivateKey is configured and
+// ErrInvalidPrivateKey if PrivateKey is not a valid key.
+func (a *Attn) SignEvent(ctx context.Context, event *nostr.Event) error {
+	if a.signerErr != nil {
+		return a.signerErr
+	}
+	if a.signer == nil {
+		return ErrPrivateKeyRequired
 	}
+	return a.signer.SignEvent(ctx, event)
 }
 
 // Connect establishes connections to all configured relays.
 func (a *Attn) Connect(ctx context.Context) error {
-	all_relays := append(a.config.RelaysAuth, a.config.RelaysNoAuth...)
+	a.connectRelays(ctx, a.config.RelaysAuth, true)
+	a.connectRelays(ctx, a.config.RelaysNoAuth, false)
 
-	for _, url := range all_relays {
+	if len(a.relays) == 0 {
+		return ErrNoRelaysConnected
+	}
+
+	a.connected = true
+	return nil
+}
+
+// connectRelays connects to each relay and starts its subscription.
+func (a *Attn) connectRelays(ctx context.Context, urls []string, auth bool) {
+	for _, url := range urls {
 		relay, err := nostr.RelayConnect(ctx, url)
 		if err != nil {
 			// Log but continue with other relays
@@ -105,15 +170,8 @@ func (a *Attn) Connect(ctx context.Context) error {
 		})
 
 		// Start subscription for this relay
-		go a.subscribe(ctx, relay)
+		go a.subscribe(ctx, relay, auth)
 	}
-
-	if len(a.relays) == 0 {
-		return ErrNoRelaysConnected
-	}
-
-	a.connected = true
-	return nil
 }
 
 // Disconnect closes all relay connections.
@@ -135,8 +193,9 @@ func (a *Attn) Connected() bool {
 	return a.connected
 }
 
-// subscribe sets up event subscriptions for a relay.
-func (a *Attn) subscribe(ctx context.Context, relay *nostr.Relay) {
+// subscribe sets up event subscriptions for a relay. When an auth relay refuses the
+// subscription with "auth-required:", it authenticates with the signer and subscribes again.
+func (a *Attn) subscribe(ctx context.Context, relay *nostr.Relay, auth bool) {
 	// Build filters for ATTN Protocol events
 	filters := nostr.Filters{
 		{
@@ -149,26 +208,58 @@ func (a *Attn) subscribe(ctx context.Context, relay *nostr.Relay) {
 		filters[0].Authors = a.config.MarketplacePubkeys
 	}
 
-	sub, err := relay.Subscribe(ctx, filters)
-	if err != nil {
-		return
-	}
+	for authed := false; ; authed = true {
+		sub, err := relay.Subscribe(ctx, filters)
+		if err != nil {
+			return
+		}
 
-	// Emit subscription hook
-	a.emitter.Emit(ctx, hooks.HookSubscription, hooks.SubscriptionContext{
-		RelayURL:       relay.URL,
-		SubscriptionID: sub.GetID(),
-		Filters:        filters,
-	})
+		// Emit subscription hook
+		a.emitter.Emit(ctx, hooks.HookSubscription, hooks.SubscriptionContext{
+			RelayURL:       relay.URL,
+			SubscriptionID: sub.GetID(),
+			Filters:        filters,
+		})
+
+		for event := range sub.Events {
+			a.handleEvent(ctx, event, relay.URL)
+		}
 
-	for event := range sub.Events {
-		a.handleEvent(ctx, event, relay.URL)
+		// Events closes after CLOSED, whose reason is already buffered
+		var reason string
+		select {
+		case reason = <-sub.ClosedReason:
+		default:
+		}
+		if !auth || authed || !sdkrelay.IsAuthRequired(reason) || a.signerErr != nil || a.signer == nil {
+			return
+		}
+		if err := sdkrelay.Authenticate(ctx, relay, a.signer); err != nil {
+			return
+		}
 	}
 }
 
 // handleEvent dispatches events to appropriate hooks.
 func (a *Attn) handleEvent(ctx context.Context, event *nostr.Event, relay_url string) {
-	// Deduplicate if enabled
+	base_ctx := hooks.BaseContext{Event: event, RelayURL: relay_url}
+
+	if !a.config.SkipVerification {
+		if result := validation.VerifyEvent(event); !result.Valid {
+			a.reject(ctx, base_ctx, result)
+			return
+		}
+	}
+
+	if a.config.ValidateEvents {
+		if result := a.validateEvent(event); !result.Valid {
+			a.reject(ctx, base_ctx, result)
+			return
+		}
+	}
+
+	// Deduplicate only accepted events, so a forged copy reusing a genuine event's
+	// id cannot get the genuine event dropped as already seen
 	if a.config.DeduplicateEvents {
 		a.mu.Lock()
 		if _, seen := a.seenEvents[event.ID]; seen {
@@ -179,8 +270,7 @@ func (a *Attn) handleEvent(ctx context.Context, event *nostr.Event, relay_url st
 		a.mu.Unlock()
 	}
 
-	base_ctx := hooks.BaseContext{Event: event, RelayURL: relay_url}
-
+	// Each handler decodes via core and rejects events that fail to decode
 	switch event.Kind {
 	case core.KindCityBlock:
 		a.handleBlockEvent(ctx, event, base_ctx)
@@ -205,15 +295,70 @@ func (a *Attn) handleEvent(ctx context.Context, event *nostr.Event, relay_url st
 	}
 }
 
+// validateEvent runs the go-core validator for the event kind with the configured profile.
+func (a *Attn) validateEvent(event *nostr.Event) validation.ValidationResult {
+	if event.Kind == core.KindCityBlock {
+		return validation.ValidateCityBlockEvent(event)
+	}
+	return validation.ValidateATTNEventWithOptions(event, a.config.ValidationOptions)
+}
+
+// reject counts a verification, validation or decode failure by code and emits the rejected hook.
+func (a *Attn) reject(ctx context.Context, base_ctx hooks.BaseContext, result validation.ValidationResult) {
+	a.mu.Lock()
+	a.rejects[result.Code]++
+	a.mu.Unlock()
+
+	a.emitter.Emit(ctx, hooks.HookEventRejected, hooks.EventRejectedContext{
+		BaseContext: base_ctx,
+		Code:        result.Code,
+		Field:       result.Field,
+		Message:     result.Message,
+	})
+}
+
+// decodeFailure describes a go-core decode error as a rejection, so events that
+// pass validation but do not decode are reported rather than dropped silently.
+func decodeFailure(err error) validation.ValidationResult {
+	result := validation.ValidationResult{Valid: false, Message: err.Error()}
+	switch {
+	case errors.Is(err, core.ErrMissingDTag):
+		result.Code, result.Field = validation.CodeMissingDTag, "d"
+	case errors.Is(err, core.ErrInvalidBlockHeight):
+		result.Code, result.Field = validation.CodeBadBlockHeight, "t"
+	case errors.Is(err, core.ErrKindMismatch), errors.Is(err, core.ErrUnsupportedKind):
+		result.Code = validation.CodeUnsupportedKind
+	default:
+		result.Code = validation.CodeInvalidJSON
+	}
+	return result
+}
+
+// RejectCounts returns the number of events dropped by verification, validation or decoding, keyed by error code.
+func (a *Attn) RejectCounts() map[validation.ErrorCode]int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	counts := make(map[validation.ErrorCode]int, len(a.rejects))
+	for code, count := range a.rejects {
+		counts[code] = count
+	}
+	return counts
+}
+
 func (a *Attn) handleBlockEvent(ctx context.Context, event *nostr.Event, base_ctx hooks.BaseContext) {
-	var data core.CityBlockData
-	json.Unmarshal([]byte(event.Content), &data)
+	block, err := core.DecodeCityBlock(event)
+	if err != nil {
+		a.reject(ctx, base_ctx, decodeFailure(err))
+		return
+	}
 
 	hook_ctx := hooks.BlockEventContext{
 		BaseContext: base_ctx,
-		BlockHeight: data.BlockHeight,
-		BlockHash:   data.BlockHash,
-		BlockData:   &data,
+		BlockHeight: block.Data.BlockHeight,
+		BlockHash:   block.Data.BlockHash,
+		BlockData:   block.Data,
+		Block:       block,
 	}
 
 	a.emitter.Emit(ctx, hooks.HookBeforeBlockEvent, hook_ctx)
@@ -222,14 +367,18 @@ func (a *Attn) handleBlockEvent(ctx context.Context, event *nostr.Event, base_ct
 }
 
 func (a *Attn) handleMarketplaceEvent(ctx context.Context, event *nostr.Event, base_ctx hooks.BaseContext) {
-	var data core.MarketplaceData
-	json.Unmarshal([]byte(event.Content), &data)
+	marketplace, err := core.DecodeMarketplace(event)
+	if err != nil {
+		a.reject(ctx, base_ctx, decodeFailure(err))
+		return
+	}
 
 	hook_ctx := hooks.MarketplaceEventContext{
 		BaseContext:     base_ctx,
 		EventID:         event.ID,
 		Pubkey:          event.PubKey,
-		MarketplaceData: &data,
+		MarketplaceData: marketplace.Data,
+		Marketplace:     marketplace,
 	}
 
 	a.emitter.Emit(ctx, hooks.HookBeforeMarketplaceEvent, hook_ctx)
@@ -238,14 +387,18 @@ func (a *Attn) handleMarketplaceEvent(ctx context.Context, event *nostr.Event, b
 }
 
 func (a *Attn) handleBillboardEvent(ctx context.Context, event *nostr.Event, base_ctx hooks.BaseContext) {
-	var data core.BillboardData
-	json.Unmarshal([]byte(event.Content), &data)
+	billboard, err := core.DecodeBillboard(event)
+	if err != nil {
+		a.reject(ctx, base_ctx, decodeFailure(err))
+		return
+	}
 
 	hook_ctx := hooks.BillboardEventContext{
 		BaseContext:   base_ctx,
 		EventID:       event.ID,
 		Pubkey:        event.PubKey,
-		BillboardData: &data,
+		BillboardData: billboard.Data,
+		Billboard:     billboard,
 	}
 
 	a.emitter.Emit(ctx, hooks.HookBeforeBillboardEvent, hook_ctx)
@@ -254,14 +407,18 @@ func (a *Attn) handleBillboardEvent(ctx context.Context, event *nostr.Event, bas
 }
 
 func (a *Attn) handlePromotionEvent(ctx context.Context, event *nostr.Event, base_ctx hooks.BaseContext) {
-	var data core.PromotionData
-	json.Unmarshal([]byte(event.Content), &data)
+	promotion, err := core.DecodePromotion(event)
+	if err != nil {
+		a.reject(ctx, base_ctx, decodeFailure(err))
+		return
+	}
 
 	hook_ctx := hooks.PromotionEventContext{
 		BaseContext:   base_ctx,
 		EventID:       event.ID,
 		Pubkey:        event.PubKey,
-		PromotionData: &data,
+		PromotionData: promotion.Data,
+		Promotion:     promotion,
 	}
 
 	a.emitter.Emit(ctx, hooks.HookBeforePromotionEvent, hook_ctx)
@@ -270,14 +427,18 @@ func (a *Attn) handlePromotionEvent(ctx context.Context, event *nostr.Event, bas
 }
 
 func (a *Attn) handleAttentionEvent(ctx context.Context, event *nostr.Event, base_ctx hooks.BaseContext) {
-	var data core.AttentionData
-	json.Unmarshal([]byte(event.Content), &data)
+	attention, err := core.DecodeAttention(event)
+	if err != nil {
+		a.reject(ctx, base_ctx, decodeFailure(err))
+		return
+	}
 
 	hook_ctx := hooks.AttentionEventContext{
 		BaseContext:   base_ctx,
 		EventID:       event.ID,
 		Pubkey:        event.PubKey,
-		AttentionData: &data,
+		AttentionData: attention.Data,
+		Attention:     attention,
 	}
 
 	a.emitter.Emit(ctx, hooks.HookBeforeAttentionEvent, hook_ctx)
@@ -286,14 +447,18 @@ func (a *Attn) handleAttentionEvent(ctx context.Context, event *nostr.Event, bas
 }
 
 func (a *Attn) handleMatchEvent(ctx context.Context, event *nostr.Event, base_ctx hooks.BaseContext) {
-	var data core.MatchData
-	json.Unmarshal([]byte(event.Content), &data)
+	match, err := core.DecodeMatch(event)
+	if err != nil {
+		a.reject(ctx, base_ctx, decodeFailure(err))
+		return
+	}
 
 	hook_ctx := hooks.MatchEventContext{
 		BaseContext: base_ctx,
 		EventID:     event.ID,
 		Pubkey:      event.PubKey,
-		MatchData:   &data,
+		MatchData:   match.Data,
+		Match:       match,
 	}
 
 	a.emitter.Emit(ctx, hooks.HookBeforeMatchEvent, hook_ctx)
@@ -302,14 +467,18 @@ func (a *Attn) handleMatchEvent(ctx context.Context, event *nostr.Event, base_ct
 }
 
 func (a *Attn) handleBillboardConfirmationEvent(ctx context.Context, event *nostr.Event, base_ctx hooks.BaseContext) {
-	var data core.BillboardConfirmationData
-	json.Unmarshal([]byte(event.Content), &data)
+	confirmation, err := core.DecodeBillboardConfirmation(event)
+	if err != nil {
+		a.reject(ctx, base_ctx, decodeFailure(err))
+		return
+	}
 
 	hook_ctx := hooks.BillboardConfirmationEventContext{
 		BaseContext:      base_ctx,
 		EventID:          event.ID,
 		Pubkey:           event.PubKey,
-		ConfirmationData: &data,
+		ConfirmationData: confirmation.Data,
+		Confirmation:     confirmation,
 	}
 
 	a.emitter.Emit(ctx, hooks.HookBeforeBillboardConfirmationEvent, hook_ctx)
@@ -318,14 +487,18 @@ func (a *Attn) handleBillboardConfirmationEvent(ctx context.Context, event *nost
 }
 
 func (a *Attn) handleAttentionConfirmationEvent(ctx context.Context, event *nostr.Event, base_ctx hooks.BaseContext) {
-	var data core.AttentionConfirmationData
-	json.Unmarshal([]byte(event.Content), &data)
+	confirmation, err := core.DecodeAttentionConfirmation(event)
+	if err != nil {
+		a.reject(ctx, base_ctx, decodeFailure(err))
+		return
+	}
 
 	hook_ctx := hooks.AttentionConfirmationEventContext{
 		BaseContext:      base_ctx,
 		EventID:          event.ID,
 		Pubkey:           event.PubKey,
-		ConfirmationData: &data,
+		ConfirmationData: confirmation.Data,
+		Confirmation:     confirmation,
 	}
 
 	a.emitter.Emit(ctx, hooks.HookBeforeAttentionConfirmationEvent, hook_ctx)
@@ -334,14 +507,18 @@ func (a *Attn) handleAttentionConfirmationEvent(ctx context.Context, event *nost
 }
 
 func (a *Attn) handleMarketplaceConfirmationEvent(ctx context.Context, event *nostr.Event, base_ctx hooks.BaseContext) {
-	var data core.MarketplaceConfirmationData
-	json.Unmarshal([]byte(event.Content), &data)
+	confirmation, err := core.DecodeMarketplaceConfirmation(event)
+	if err != nil {
+		a.reject(ctx, base_ctx, decodeFailure(err))
+		return
+	}
 
 	hook_ctx := hooks.MarketplaceConfirmationEventContext{
 		BaseContext:    base_ctx,
 		EventID:        event.ID,
 		Pubkey:         event.PubKey,
-		SettlementData: &data,
+		SettlementData: confirmation.Data,
+		Confirmation:   confirmation,
 	}
 
 	a.emitter.Emit(ctx, hooks.HookBeforeMarketplaceConfirmationEvent, hook_ctx)
@@ -350,14 +527,18 @@ func (a *Attn) handleMarketplaceConfirmationEvent(ctx context.Context, event *no
 }
 
 func (a *Attn) handleAttentionPaymentConfirmationEvent(ctx context.Context, event *nostr.Event, base_ctx hooks.BaseContext) {
-	var data core.AttentionPaymentConfirmationData
-	json.Unmarshal([]byte(event.Content), &data)
+	confirmation, err := core.DecodeAttentionPaymentConfirmation(event)
+	if err != nil {
+		a.reject(ctx, base_ctx, decodeFailure(err))
+		return
+	}
 
 	hook_ctx := hooks.AttentionPaymentConfirmationEventContext{
-		BaseContext: base_ctx,
-		EventID:     event.ID,
-		Pubkey:      event.PubKey,
-		PaymentData: &data,
+		BaseContext:  base_ctx,
+		EventID:      event.ID,
+		Pubkey:       event.PubKey,
+		PaymentData:  confirmation.Data,
+		Confirmation: confirmation,
 	}
 
 	a.emitter.Emit(ctx, hooks.HookBeforeAttentionPaymentConfirmationEvent, hook_ctx)
@@ -467,6 +648,16 @@ func (a *Attn) OnMatchEvent(handler func(ctx context.Context, hookCtx hooks.Matc
 	})
 }
 
+// OnEventRejected registers a handler for events dropped by verification, validation or decoding.
+func (a *Attn) OnEventRejected(handler func(ctx context.Context, hookCtx hooks.EventRejectedContext) error) *hooks.Handle {
+	return a.emitter.Register(hooks.HookEventRejected, func(ctx context.Context, data any) error {
+		if hookCtx, ok := data.(hooks.EventRejectedContext); ok {
+			return handler(ctx, hookCtx)
+		}
+		return nil
+	})
+}
+
 // Emitter returns the underlying hook emitter for advanced usage.
 func (a *Attn) Emitter() *hooks.Emitter {
 	return a.emitter
@@ -0,0 +1,148 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/validation"
+	"github.com/joinnextblock/attn-protocol/go-framework/hooks"
+	sdkrelay "github.com/joinnextblock/attn-protocol/go-sdk/relay"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relaytest"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// TestDeduplicationIgnoresForgedCopies checks a forged event reusing a genuine event's
+// id does not get the genuine event dropped as a duplicate.
+func TestDeduplicationIgnoresForgedCopies(t *testing.T) {
+	signer, err := core.NewKeySigner(strings.Repeat("01", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	genuine := &nostr.Event{
+		Kind:      core.KindMarketplace,
+		CreatedAt: nostr.Now(),
+		Tags:      nostr.Tags{{"d", "org.attnprotocol:marketplace:m1"}, {"t", "870500"}},
+		Content:   `{"name":"Genuine"}`,
+	}
+	if err := signer.SignEvent(context.Background(), genuine); err != nil {
+		t.Fatal(err)
+	}
+	forged := *genuine
+	forged.Content = `{"name":"Forged"}`
+
+	attn := NewAttn(Config{DeduplicateEvents: true})
+	var delivered []string
+	attn.OnMarketplaceEvent(func(ctx context.Context, hookCtx hooks.MarketplaceEventContext) error {
+		delivered = append(delivered, hookCtx.MarketplaceData.Name)
+		return nil
+	})
+	var rejected int
+	attn.OnEventRejected(func(ctx context.Context, hookCtx hooks.EventRejectedContext) error {
+		rejected++
+		return nil
+	})
+
+	attn.handleEvent(context.Background(), &forged, "wss://attacker.example.com")
+	attn.handleEvent(context.Background(), genuine, "wss://relay.example.com")
+	attn.handleEvent(context.Background(), genuine, "wss://mirror.example.com")
+
+	if rejected != 1 {
+		t.Errorf("expected the forged copy to be rejected, got %d rejections", rejected)
+	}
+	if len(delivered) != 1 || delivered[0] != "Genuine" {
+		t.Errorf("expected the genuine event to be delivered once, got %v", delivered)
+	}
+}
+
+// TestConnectAuthenticatesWithSigner checks events from a relay that requires NIP-42
+// AUTH are delivered once the framework authenticates with its signer.
+func TestConnectAuthenticatesWithSigner(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	server.RequireAuth()
+
+	signer, err := core.NewKeySigner(strings.Repeat("01", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &nostr.Event{
+		Kind:      core.KindMarketplace,
+		CreatedAt: nostr.Now(),
+		Tags:      nostr.Tags{{"d", "org.attnprotocol:marketplace:m1"}, {"t", "870500"}},
+		Content:   `{"name":"Authed"}`,
+	}
+	if err := signer.SignEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := sdkrelay.NewPoolWithOptions([]string{server.URL}, sdkrelay.PoolOptions{Signer: signer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	if _, err := pool.Publish(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	attn := NewAttn(Config{RelaysAuth: []string{server.URL}, Signer: signer})
+	delivered := make(chan string, 1)
+	attn.OnMarketplaceEvent(func(ctx context.Context, hookCtx hooks.MarketplaceEventContext) error {
+		delivered <- hookCtx.MarketplaceData.Name
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := attn.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer attn.Disconnect()
+
+	select {
+	case name := <-delivered:
+		if name != "Authed" {
+			t.Errorf("expected the stored marketplace, got %q", name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the framework to authenticate and receive the stored event")
+	}
+}
+
+// TestDecodeFailuresAreRejected checks an event that passes verification but does not
+// decode is reported and counted instead of dropped silently.
+func TestDecodeFailuresAreRejected(t *testing.T) {
+	signer, err := core.NewKeySigner(strings.Repeat("01", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &nostr.Event{
+		Kind:      core.KindMarketplace,
+		CreatedAt: nostr.Now(),
+		Tags:      nostr.Tags{{"d", "org.attnprotocol:marketplace:m1"}, {"t", "870500"}},
+		Content:   `not json`,
+	}
+	if err := signer.SignEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	attn := NewAttn(Config{})
+	var codes []validation.ErrorCode
+	attn.OnEventRejected(func(ctx context.Context, hookCtx hooks.EventRejectedContext) error {
+		codes = append(codes, hookCtx.Code)
+		return nil
+	})
+	attn.OnMarketplaceEvent(func(ctx context.Context, hookCtx hooks.MarketplaceEventContext) error {
+		t.Error("expected the undecodable event not to be dispatched")
+		return nil
+	})
+
+	attn.handleEvent(context.Background(), event, "wss://relay.example.com")
+
+	if len(codes) != 1 || codes[0] != validation.CodeInvalidJSON {
+		t.Errorf("expected one invalid_json rejection, got %v", codes)
+	}
+	if count := attn.RejectCounts()[validation.CodeInvalidJSON]; count != 1 {
+		t.Errorf("expected the rejection to be counted, got %d", count)
+	}
+}
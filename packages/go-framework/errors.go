@@ -9,7 +9,8 @@ var (
 	// ErrNotConnected is returned when an operation requires a connection but none exists.
 	ErrNotConnected = errors.New("not connected to any relay")
 
-	// ErrPrivateKeyRequired is returned when a private key is required but not provided.
+	// ErrPrivateKeyRequired is returned when signing is required but neither a signer
+	// nor a private key is configured.
 	ErrPrivateKeyRequired = errors.New("private key is required")
 
 	// ErrInvalidPrivateKey is returned when the private key is invalid.
@@ -6,6 +6,7 @@ toolchain go1.24.3
 
 require (
 	github.com/joinnextblock/attn-protocol/go-core v0.1.0
+	github.com/joinnextblock/attn-protocol/go-sdk v0.1.0
 	github.com/nbd-wtf/go-nostr v0.52.3
 )
 
@@ -31,8 +32,12 @@ require (
 	github.com/tidwall/pretty v1.2.1 // indirect
 	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
 	golang.org/x/arch v0.15.0 // indirect
+	golang.org/x/crypto v0.36.0 // indirect
 	golang.org/x/exp v0.0.0-20251113190631-e25ba8c21ef6 // indirect
 	golang.org/x/sys v0.38.0 // indirect
 )
 
-replace github.com/joinnextblock/attn-protocol/go-core => ../go-core
+replace (
+	github.com/joinnextblock/attn-protocol/go-core => ../go-core
+	github.com/joinnextblock/attn-protocol/go-sdk => ../go-sdk
+)
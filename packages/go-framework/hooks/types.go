@@ -2,6 +2,7 @@ package hooks
 
 import (
 	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/validation"
 	"github.com/nbd-wtf/go-nostr"
 )
 
@@ -13,6 +14,7 @@ const (
 	HookSubscription    = "subscription"
 	HookRateLimit       = "rate_limit"
 	HookHealthChange    = "health_change"
+	HookEventRejected   = "event_rejected"
 
 	// Block event hooks
 	HookBeforeBlockEvent = "before_block_event"
@@ -112,12 +114,21 @@ type HealthChangeContext struct {
 	HealthStatus string
 }
 
+// EventRejectedContext contains context for events dropped by verification, validation or decoding.
+type EventRejectedContext struct {
+	BaseContext
+	Code    validation.ErrorCode
+	Field   string
+	Message string
+}
+
 // BlockEventContext contains context for block events.
 type BlockEventContext struct {
 	BaseContext
 	BlockHeight int64
 	BlockHash   string
 	BlockData   *core.CityBlockData
+	Block       *core.CityBlock
 }
 
 // BlockGapDetectedContext contains context for block gap detection events.
@@ -133,6 +144,7 @@ type MarketplaceEventContext struct {
 	EventID         string
 	Pubkey          string
 	MarketplaceData *core.MarketplaceData
+	Marketplace     *core.Marketplace
 }
 
 // BillboardEventContext contains context for billboard events.
@@ -141,6 +153,7 @@ type BillboardEventContext struct {
 	EventID       string
 	Pubkey        string
 	BillboardData *core.BillboardData
+	Billboard     *core.Billboard
 }
 
 // PromotionEventContext contains context for promotion events.
@@ -149,6 +162,7 @@ type PromotionEventContext struct {
 	EventID       string
 	Pubkey        string
 	PromotionData *core.PromotionData
+	Promotion     *core.Promotion
 }
 
 // AttentionEventContext contains context for attention events.
@@ -157,6 +171,7 @@ type AttentionEventContext struct {
 	EventID       string
 	Pubkey        string
 	AttentionData *core.AttentionData
+	Attention     *core.Attention
 }
 
 // MatchEventContext contains context for match events.
@@ -165,6 +180,7 @@ type MatchEventContext struct {
 	EventID   string
 	Pubkey    string
 	MatchData *core.MatchData
+	Match     *core.Match
 }
 
 // MatchPublishedContext contains context for match published events.
@@ -181,6 +197,7 @@ type BillboardConfirmationEventContext struct {
 	EventID          string
 	Pubkey           string
 	ConfirmationData *core.BillboardConfirmationData
+	Confirmation     *core.BillboardConfirmation
 }
 
 // AttentionConfirmationEventContext contains context for attention confirmation events.
@@ -189,6 +206,7 @@ type AttentionConfirmationEventContext struct {
 	EventID          string
 	Pubkey           string
 	ConfirmationData *core.AttentionConfirmationData
+	Confirmation     *core.AttentionConfirmation
 }
 
 // MarketplaceConfirmationEventContext contains context for marketplace confirmation events.
@@ -197,14 +215,16 @@ type MarketplaceConfirmationEventContext struct {
 	EventID        string
 	Pubkey         string
 	SettlementData *core.MarketplaceConfirmationData
+	Confirmation   *core.MarketplaceConfirmation
 }
 
 // AttentionPaymentConfirmationEventContext contains context for attention payment confirmation events.
 type AttentionPaymentConfirmationEventContext struct {
 	BaseContext
-	EventID     string
-	Pubkey      string
-	PaymentData *core.AttentionPaymentConfirmationData
+	EventID      string
+	Pubkey       string
+	PaymentData  *core.AttentionPaymentConfirmationData
+	Confirmation *core.AttentionPaymentConfirmation
 }
 
 // ProfilePublishedContext contains context for profile published events.
@@ -13,8 +13,11 @@ import (
 	"syscall"
 
 	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/validation"
 	"github.com/joinnextblock/attn-protocol/go-framework/hooks"
 	"github.com/joinnextblock/attn-protocol/go-marketplace"
+	"github.com/joinnextblock/attn-protocol/go-sdk/nip19"
+	"github.com/joinnextblock/attn-protocol/go-sdk/nip46"
 	"github.com/nbd-wtf/go-nostr"
 )
 
@@ -24,6 +27,24 @@ func main() {
 	// Load configuration from environment
 	config := loadConfig()
 
+	// Sign through a remote signer when configured, so the marketplace key stays off this host
+	if bunker_url := os.Getenv("CITY_MARKETPLACE_BUNKER_URL"); bunker_url != "" {
+		client, err := nip46.Connect(context.Background(), bunker_url, nip46.ClientOptions{
+			SecretKey: os.Getenv("CITY_MARKETPLACE_BUNKER_CLIENT_KEY"),
+			OnAuthURL: func(auth_url string) {
+				log.Printf("Remote signer asks to authorize a request at %s", auth_url)
+			},
+		})
+		if err != nil {
+			log.Fatalf("Failed to connect to remote signer: %v", err)
+		}
+		defer client.Close()
+
+		config.Signer = client.Signer()
+		config.PrivateKey = ""
+		log.Println("Signing with remote signer")
+	}
+
 	// Create in-memory storage (can be replaced with persistent storage)
 	storage := NewInMemoryStorage()
 
@@ -40,7 +61,8 @@ func main() {
 	})
 
 	mp.Framework().OnPromotionEvent(func(ctx context.Context, hookCtx hooks.PromotionEventContext) error {
-		log.Printf("Promotion received: %s (bid: %d sats)", hookCtx.EventID, hookCtx.PromotionData.Bid)
+		naddr, _ := nip19.EncodeEventAddress(hookCtx.Event, hookCtx.RelayURL)
+		log.Printf("Promotion received: %s (bid: %d sats) %s", hookCtx.EventID, hookCtx.PromotionData.Bid, naddr)
 		return nil
 	})
 
@@ -142,7 +164,7 @@ func loadConfig() marketplace.Config {
 	}
 }
 
-// InMemoryStorage implements the marketplace.Storage interface
+// InMemoryStorage implements the marketplace.Storage interface and validation.Resolver
 type InMemoryStorage struct {
 	mu         sync.RWMutex
 	billboards map[string]*StoredEvent
@@ -220,6 +242,36 @@ func (s *InMemoryStorage) Exists(ctx context.Context, event_type string, event_i
 	return false, nil
 }
 
+// GetEventByID returns a stored event by ID, or nil if unknown.
+func (s *InMemoryStorage) GetEventByID(ctx context.Context, id string) (*nostr.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, events := range []map[string]*StoredEvent{s.billboards, s.promotions, s.attention, s.matches} {
+		if stored, ok := events[id]; ok {
+			return stored.Event, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetEventByCoordinate returns the latest stored event at a coordinate, or nil if unknown.
+func (s *InMemoryStorage) GetEventByCoordinate(ctx context.Context, coordinate core.Coordinate) (*nostr.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	target := coordinate.String()
+	var latest *nostr.Event
+	for _, events := range []map[string]*StoredEvent{s.billboards, s.promotions, s.attention, s.matches} {
+		for _, stored := range events {
+			if stored.Coordinate == target && (latest == nil || stored.Event.CreatedAt > latest.CreatedAt) {
+				latest = stored.Event
+			}
+		}
+	}
+	return latest, nil
+}
+
+var _ validation.Resolver = (*InMemoryStorage)(nil)
+
 func (s *InMemoryStorage) QueryPromotions(ctx context.Context, params marketplace.QueryPromotionsParams) ([]marketplace.PromotionRecord, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -24,18 +24,23 @@ package marketplace
 
 import (
 	"context"
-	"fmt"
 	"sync"
 
 	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/validation"
 	"github.com/joinnextblock/attn-protocol/go-framework"
 	"github.com/joinnextblock/attn-protocol/go-framework/hooks"
+	"github.com/joinnextblock/attn-protocol/go-sdk/nip19"
 	"github.com/nbd-wtf/go-nostr"
 )
 
 // Config holds marketplace configuration.
 type Config struct {
-	// PrivateKey is the marketplace signing key (hex or nsec).
+	// Signer signs marketplace events, e.g. a NIP-46 remote signer that keeps the
+	// marketplace key off this host. Takes precedence over PrivateKey.
+	Signer core.Signer
+
+	// PrivateKey is the marketplace signing key (hex or nsec), used when no Signer is set.
 	PrivateKey string
 
 	// MarketplaceID is the unique marketplace identifier.
@@ -57,10 +62,10 @@ type Config struct {
 	MaxDuration int64
 
 	// MatchFeeSats is the fee per match in satoshis.
-	MatchFeeSats int64
+	MatchFeeSats core.Sats
 
 	// ConfirmationFeeSats is the fee per confirmation in satoshis.
-	ConfirmationFeeSats int64
+	ConfirmationFeeSats core.Sats
 
 	// KindList is the list of supported content kinds.
 	KindList []int
@@ -74,6 +79,13 @@ type Config struct {
 	// AutoMatch auto-runs matching when attention/promotion received.
 	AutoMatch bool
 
+	// ValidateEvents validates received events with go-core before handling them.
+	ValidateEvents bool
+
+	// ValidationOptions selects the validation profile for received events and matches.
+	// The zero value applies go-core's default rules.
+	ValidationOptions validation.Options
+
 	// RelayConfig holds relay URL configuration.
 	RelayConfig RelayConfig
 }
@@ -90,6 +102,7 @@ type RelayConfig struct {
 type Marketplace struct {
 	config             Config
 	framework          *framework.Attn
+	signerErr          error
 	storage            Storage
 	matcher            Matcher
 	currentBlockHeight int64
@@ -118,11 +131,28 @@ func New(config Config, storage Storage, matcher Matcher) *Marketplace {
 		RelaysWriteNoAuth: config.RelayConfig.WriteNoAuth,
 		NodePubkeys:       []string{config.NodePubkey},
 		DeduplicateEvents: true,
+		ValidateEvents:    config.ValidateEvents,
+		ValidationOptions: config.ValidationOptions,
+		Signer:            config.Signer,
+	}
+
+	// Fall back to an in-memory signer; a bad key fails Start
+	var signer_err error
+	if fw_config.Signer == nil && config.PrivateKey != "" {
+		private_key, err := nip19.DecodePrivateKey(config.PrivateKey)
+		if err == nil {
+			var signer *core.KeySigner
+			if signer, err = core.NewKeySigner(private_key); err == nil {
+				fw_config.Signer = signer
+			}
+		}
+		signer_err = err
 	}
 
 	m := &Marketplace{
 		config:    config,
 		framework: framework.NewAttn(fw_config),
+		signerErr: signer_err,
 		storage:   storage,
 		matcher:   matcher,
 	}
@@ -137,22 +167,22 @@ func New(config Config, storage Storage, matcher Matcher) *Marketplace {
 func (m *Marketplace) wireFrameworkEvents() {
 	// Billboard events
 	m.framework.OnBillboardEvent(func(ctx context.Context, hookCtx hooks.BillboardEventContext) error {
-		return m.handleBillboard(ctx, hookCtx.Event, hookCtx.BillboardData)
+		return m.handleBillboard(ctx, hookCtx.Billboard)
 	})
 
 	// Promotion events
 	m.framework.OnPromotionEvent(func(ctx context.Context, hookCtx hooks.PromotionEventContext) error {
-		return m.handlePromotion(ctx, hookCtx.Event, hookCtx.PromotionData)
+		return m.handlePromotion(ctx, hookCtx.Promotion)
 	})
 
 	// Attention events
 	m.framework.OnAttentionEvent(func(ctx context.Context, hookCtx hooks.AttentionEventContext) error {
-		return m.handleAttention(ctx, hookCtx.Event, hookCtx.AttentionData)
+		return m.handleAttention(ctx, hookCtx.Attention)
 	})
 
 	// Match events
 	m.framework.OnMatchEvent(func(ctx context.Context, hookCtx hooks.MatchEventContext) error {
-		return m.handleMatch(ctx, hookCtx.Event, hookCtx.MatchData)
+		return m.handleMatch(ctx, hookCtx.Match)
 	})
 
 	// Block events
@@ -162,17 +192,13 @@ func (m *Marketplace) wireFrameworkEvents() {
 }
 
 // handleBillboard processes billboard events.
-func (m *Marketplace) handleBillboard(ctx context.Context, event *nostr.Event, data *core.BillboardData) error {
-	block_height := extractBlockHeight(event)
-	d_tag := extractDTag(event)
-	coordinate := buildCoordinate(event)
-
-	if block_height == 0 || d_tag == "" || coordinate == "" {
+func (m *Marketplace) handleBillboard(ctx context.Context, billboard *core.Billboard) error {
+	if billboard == nil || billboard.BlockHeight == 0 {
 		return nil // Invalid event
 	}
 
 	// Check if already processed
-	exists, err := m.storage.Exists(ctx, "billboard", event.ID)
+	exists, err := m.storage.Exists(ctx, "billboard", billboard.Event.ID)
 	if err != nil {
 		return err
 	}
@@ -180,21 +206,17 @@ func (m *Marketplace) handleBillboard(ctx context.Context, event *nostr.Event, d
 		return nil
 	}
 
-	return m.storage.StoreBillboard(ctx, event, data, block_height, d_tag, coordinate)
+	return m.storage.StoreBillboard(ctx, billboard.Event, billboard.Data, billboard.BlockHeight, billboard.DTag, billboard.Coordinate)
 }
 
 // handlePromotion processes promotion events.
-func (m *Marketplace) handlePromotion(ctx context.Context, event *nostr.Event, data *core.PromotionData) error {
-	block_height := extractBlockHeight(event)
-	d_tag := extractDTag(event)
-	coordinate := buildCoordinate(event)
-
-	if block_height == 0 || d_tag == "" || coordinate == "" {
-		return nil
+func (m *Marketplace) handlePromotion(ctx context.Context, promotion *core.Promotion) error {
+	if promotion == nil || promotion.BlockHeight == 0 {
+		return nil // Invalid event
 	}
 
 	// Check if already processed
-	exists, err := m.storage.Exists(ctx, "promotion", event.ID)
+	exists, err := m.storage.Exists(ctx, "promotion", promotion.Event.ID)
 	if err != nil {
 		return err
 	}
@@ -202,7 +224,7 @@ func (m *Marketplace) handlePromotion(ctx context.Context, event *nostr.Event, d
 		return nil
 	}
 
-	if err := m.storage.StorePromotion(ctx, event, data, block_height, d_tag, coordinate); err != nil {
+	if err := m.storage.StorePromotion(ctx, promotion.Event, promotion.Data, promotion.BlockHeight, promotion.DTag, promotion.Coordinate); err != nil {
 		return err
 	}
 
@@ -213,17 +235,13 @@ func (m *Marketplace) handlePromotion(ctx context.Context, event *nostr.Event, d
 }
 
 // handleAttention processes attention events.
-func (m *Marketplace) handleAttention(ctx context.Context, event *nostr.Event, data *core.AttentionData) error {
-	block_height := extractBlockHeight(event)
-	d_tag := extractDTag(event)
-	coordinate := buildCoordinate(event)
-
-	if block_height == 0 || d_tag == "" || coordinate == "" {
-		return nil
+func (m *Marketplace) handleAttention(ctx context.Context, attention *core.Attention) error {
+	if attention == nil || attention.BlockHeight == 0 {
+		return nil // Invalid event
 	}
 
 	// Check if already processed
-	exists, err := m.storage.Exists(ctx, "attention", event.ID)
+	exists, err := m.storage.Exists(ctx, "attention", attention.Event.ID)
 	if err != nil {
 		return err
 	}
@@ -231,30 +249,26 @@ func (m *Marketplace) handleAttention(ctx context.Context, event *nostr.Event, d
 		return nil
 	}
 
-	if err := m.storage.StoreAttention(ctx, event, data, block_height, d_tag, coordinate); err != nil {
+	if err := m.storage.StoreAttention(ctx, attention.Event, attention.Data, attention.BlockHeight, attention.DTag, attention.Coordinate); err != nil {
 		return err
 	}
 
 	// Trigger matching
 	if m.config.AutoMatch {
-		return m.tryMatchAttention(ctx, event, data, coordinate, block_height)
+		return m.tryMatchAttention(ctx, attention)
 	}
 
 	return nil
 }
 
 // handleMatch processes match events.
-func (m *Marketplace) handleMatch(ctx context.Context, event *nostr.Event, data *core.MatchData) error {
-	block_height := extractBlockHeight(event)
-	d_tag := extractDTag(event)
-	coordinate := buildCoordinate(event)
-
-	if block_height == 0 || d_tag == "" || coordinate == "" {
-		return nil
+func (m *Marketplace) handleMatch(ctx context.Context, match *core.Match) error {
+	if match == nil || match.BlockHeight == 0 {
+		return nil // Invalid event
 	}
 
 	// Check if already processed
-	exists, err := m.storage.Exists(ctx, "match", event.ID)
+	exists, err := m.storage.Exists(ctx, "match", match.Event.ID)
 	if err != nil {
 		return err
 	}
@@ -262,7 +276,14 @@ func (m *Marketplace) handleMatch(ctx context.Context, event *nostr.Event, data
 		return nil
 	}
 
-	return m.storage.StoreMatch(ctx, event, data, block_height, d_tag, coordinate)
+	// Drop matches whose promotion or attention is unknown or targets another marketplace
+	if resolver, ok := m.storage.(validation.Resolver); ok {
+		if result := validation.ValidateWithResolverOptions(ctx, match.Event, resolver, m.config.ValidationOptions); !result.Valid {
+			return nil
+		}
+	}
+
+	return m.storage.StoreMatch(ctx, match.Event, match.Data, match.BlockHeight, match.DTag, match.Coordinate)
 }
 
 // handleBlock processes block events.
@@ -282,20 +303,18 @@ func (m *Marketplace) handleBlock(ctx context.Context, block_height int64, block
 }
 
 // tryMatchAttention attempts to match an attention offer with promotions.
-func (m *Marketplace) tryMatchAttention(ctx context.Context, attention_event *nostr.Event, attention_data *core.AttentionData, attention_coordinate string, block_height int64) error {
-	// Extract marketplace coordinate from attention event
-	marketplace_coordinate := extractMarketplaceCoordinate(attention_event)
-	if marketplace_coordinate == "" {
+func (m *Marketplace) tryMatchAttention(ctx context.Context, attention *core.Attention) error {
+	if attention.MarketplaceCoordinate == "" {
 		return nil
 	}
 
 	// Query matching promotions
 	promotions, err := m.storage.QueryPromotions(ctx, QueryPromotionsParams{
-		MarketplaceCoordinate: marketplace_coordinate,
-		MinBid:                attention_data.Ask,
-		MinDuration:           attention_data.MinDuration,
-		MaxDuration:           attention_data.MaxDuration,
-		BlockHeight:           block_height,
+		MarketplaceCoordinate: attention.MarketplaceCoordinate,
+		MinBid:                attention.Data.Ask,
+		MinDuration:           attention.Data.MinDuration,
+		MaxDuration:           attention.Data.MaxDuration,
+		BlockHeight:           attention.BlockHeight,
 	})
 	if err != nil {
 		return err
@@ -312,9 +331,9 @@ func (m *Marketplace) tryMatchAttention(ctx context.Context, attention_event *no
 			PromotionEvent:      p.Event,
 			PromotionData:       p.Data,
 			PromotionCoordinate: p.Coordinate,
-			AttentionEvent:      attention_event,
-			AttentionData:       attention_data,
-			AttentionCoordinate: attention_coordinate,
+			AttentionEvent:      attention.Event,
+			AttentionData:       attention.Data,
+			AttentionCoordinate: attention.Coordinate,
 		}
 	}
 
@@ -326,7 +345,7 @@ func (m *Marketplace) tryMatchAttention(ctx context.Context, attention_event *no
 
 	// Create and publish matches
 	for _, match := range matches {
-		if err := m.createAndPublishMatch(ctx, match, block_height); err != nil {
+		if err := m.createAndPublishMatch(ctx, match, attention.BlockHeight); err != nil {
 			// Log but continue
 			continue
 		}
@@ -346,10 +365,22 @@ func (m *Marketplace) createAndPublishMatch(ctx context.Context, candidate Match
 }
 
 // Start starts the marketplace.
+// Returns an error if PrivateKey is set but is not a valid hex or nsec key.
 func (m *Marketplace) Start(ctx context.Context) error {
+	if m.signerErr != nil {
+		return m.signerErr
+	}
 	return m.framework.Connect(ctx)
 }
 
+// SignEvent signs an event with the marketplace's signer.
+func (m *Marketplace) SignEvent(ctx context.Context, event *nostr.Event) error {
+	if m.signerErr != nil {
+		return m.signerErr
+	}
+	return m.framework.SignEvent(ctx, event)
+}
+
 // Stop stops the marketplace.
 func (m *Marketplace) Stop() {
 	m.framework.Disconnect()
@@ -366,45 +397,3 @@ func (m *Marketplace) BlockHeight() int64 {
 	defer m.mu.RUnlock()
 	return m.currentBlockHeight
 }
-
-// Helper functions
-
-func extractBlockHeight(event *nostr.Event) int64 {
-	for _, tag := range event.Tags {
-		if len(tag) >= 2 && tag[0] == "t" {
-			var height int64
-			fmt.Sscanf(tag[1], "%d", &height)
-			return height
-		}
-	}
-	return 0
-}
-
-func extractDTag(event *nostr.Event) string {
-	for _, tag := range event.Tags {
-		if len(tag) >= 2 && tag[0] == "d" {
-			return tag[1]
-		}
-	}
-	return ""
-}
-
-func buildCoordinate(event *nostr.Event) string {
-	d_tag := extractDTag(event)
-	if d_tag == "" {
-		return ""
-	}
-	return fmt.Sprintf("%d:%s:%s", event.Kind, event.PubKey, d_tag)
-}
-
-func extractMarketplaceCoordinate(event *nostr.Event) string {
-	for _, tag := range event.Tags {
-		if len(tag) >= 2 && tag[0] == "a" {
-			// Check if it's a marketplace coordinate (38188:...)
-			if len(tag[1]) > 6 && tag[1][:6] == "38188:" {
-				return tag[1]
-			}
-		}
-	}
-	return ""
-}
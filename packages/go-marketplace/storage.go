@@ -8,7 +8,9 @@ import (
 )
 
 // Storage defines the interface for storage operations.
-// Implementers bring their own storage backend.
+// Implementers bring their own storage backend. Storage that also implements
+// validation.Resolver is used to check match events against the stored promotion
+// and attention before they are stored.
 type Storage interface {
 	// StoreBillboard stores a billboard event.
 	StoreBillboard(ctx context.Context, event *nostr.Event, data *core.BillboardData, block_height int64, d_tag, coordinate string) error
@@ -35,7 +37,7 @@ type Storage interface {
 // QueryPromotionsParams holds parameters for querying promotions.
 type QueryPromotionsParams struct {
 	MarketplaceCoordinate string
-	MinBid                int64
+	MinBid                core.Sats
 	MinDuration           int64
 	MaxDuration           int64
 	BlockHeight           int64
@@ -1,9 +1,9 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/joinnextblock/attn-protocol/go-core"
 	"github.com/nbd-wtf/go-nostr"
@@ -12,7 +12,7 @@ import (
 // AttentionParams holds parameters for creating an attention event.
 type AttentionParams struct {
 	// Ask is the minimum payment requested in satoshis.
-	Ask int64
+	Ask core.Sats
 
 	// MinDuration is the minimum ad duration in milliseconds.
 	MinDuration int64
@@ -35,6 +35,30 @@ type AttentionParams struct {
 	// MarketplaceCoordinate is the marketplace coordinate (38188:pubkey:id).
 	MarketplaceCoordinate string
 
+	// BlockedPromotionsCoordinate is the blocked promotions list coordinate (30000:pubkey:id).
+	BlockedPromotionsCoordinate string
+
+	// BlockedPromotersCoordinate is the blocked promoters list coordinate (30000:pubkey:id).
+	BlockedPromotersCoordinate string
+
+	// TrustedMarketplacesCoordinate is the trusted marketplaces list coordinate (30000:pubkey:id).
+	TrustedMarketplacesCoordinate string
+
+	// TrustedBillboardsCoordinate is the trusted billboards list coordinate (30000:pubkey:id).
+	TrustedBillboardsCoordinate string
+
+	// MarketplacePubkey is the marketplace's pubkey.
+	MarketplacePubkey string
+
+	// MarketplaceID is the marketplace ID.
+	MarketplaceID string
+
+	// Relays is the list of relay URLs.
+	Relays []string
+
+	// Kinds is the list of accepted content kinds.
+	Kinds []int
+
 	// BlockHeight is the Bitcoin block height.
 	BlockHeight int64
 
@@ -45,8 +69,30 @@ type AttentionParams struct {
 	AttentionPubkey string
 }
 
-// CreateAttention creates an ATTENTION event (kind 38488).
+// CreateAttention creates an ATTENTION event (kind 38488) signed with a hex or nsec private key.
 func CreateAttention(private_key string, params AttentionParams) (*nostr.Event, error) {
+	signer, err := newKeySigner(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return CreateAttentionWithSigner(context.Background(), signer, params)
+}
+
+// CreateAttentionWithSigner creates an ATTENTION event (kind 38488) signed by signer.
+func CreateAttentionWithSigner(ctx context.Context, signer core.Signer, params AttentionParams) (*nostr.Event, error) {
+	// Resolve NIP-19 encoded keys and pointers
+	p := &pointers{}
+	p.coordinate(&params.MarketplaceCoordinate)
+	p.coordinate(&params.BlockedPromotionsCoordinate)
+	p.coordinate(&params.BlockedPromotersCoordinate)
+	p.coordinate(&params.TrustedMarketplacesCoordinate)
+	p.coordinate(&params.TrustedBillboardsCoordinate)
+	p.pubkey(&params.MarketplacePubkey)
+	p.pubkey(&params.AttentionPubkey)
+	if p.err != nil {
+		return nil, p.err
+	}
+
 	// Build content
 	content := core.AttentionData{
 		Ask:                   params.Ask,
@@ -58,6 +104,8 @@ func CreateAttention(private_key string, params AttentionParams) (*nostr.Event,
 		TrustedBillboardsID:   params.TrustedBillboardsID,
 		RefAttentionPubkey:    params.AttentionPubkey,
 		RefAttentionID:        params.AttentionID,
+		RefMarketplacePubkey:  params.MarketplacePubkey,
+		RefMarketplaceID:      params.MarketplaceID,
 	}
 
 	content_json, err := json.Marshal(content)
@@ -69,11 +117,7 @@ func CreateAttention(private_key string, params AttentionParams) (*nostr.Event,
 	tags := nostr.Tags{}
 
 	// Add d-tag
-	d_tag := params.AttentionID
-	if d_tag == "" {
-		d_tag = fmt.Sprintf("org.attnprotocol:attention:%d", time.Now().UnixNano())
-	}
-	tags = append(tags, nostr.Tag{"d", d_tag})
+	tags = append(tags, nostr.Tag{"d", buildDTag(core.KindAttention, params.AttentionID)})
 
 	// Add block height tag
 	tags = append(tags, nostr.Tag{"t", fmt.Sprintf("%d", params.BlockHeight)})
@@ -83,25 +127,35 @@ func CreateAttention(private_key string, params AttentionParams) (*nostr.Event,
 		tags = append(tags, nostr.Tag{"a", params.MarketplaceCoordinate})
 	}
 
-	// Get public key
-	pk, err := nostr.GetPublicKey(private_key)
-	if err != nil {
-		return nil, err
+	// Add list coordinates
+	for _, coordinate := range []string{
+		params.BlockedPromotionsCoordinate,
+		params.BlockedPromotersCoordinate,
+		params.TrustedMarketplacesCoordinate,
+		params.TrustedBillboardsCoordinate,
+	} {
+		if coordinate != "" {
+			tags = append(tags, nostr.Tag{"a", coordinate})
+		}
 	}
 
-	// Create event
-	event := &nostr.Event{
-		PubKey:    pk,
-		CreatedAt: nostr.Timestamp(time.Now().Unix()),
-		Kind:      core.KindAttention,
-		Tags:      tags,
-		Content:   string(content_json),
+	// Add pubkey tags
+	if params.AttentionPubkey != "" {
+		tags = append(tags, nostr.Tag{"p", params.AttentionPubkey})
+	}
+	if params.MarketplacePubkey != "" {
+		tags = append(tags, nostr.Tag{"p", params.MarketplacePubkey})
 	}
 
-	// Sign event
-	if err := event.Sign(private_key); err != nil {
-		return nil, err
+	// Add relay list
+	for _, relay := range params.Relays {
+		tags = append(tags, nostr.Tag{"r", relay})
+	}
+
+	// Add kind list
+	for _, kind := range params.Kinds {
+		tags = append(tags, nostr.Tag{"k", fmt.Sprintf("%d", kind)})
 	}
 
-	return event, nil
+	return signEvent(ctx, signer, core.KindAttention, tags, string(content_json))
 }
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// BillboardParams holds parameters for creating a billboard event.
+type BillboardParams struct {
+	// Name is the billboard name.
+	Name string
+
+	// Description is the billboard description.
+	Description string
+
+	// ConfirmationFeeSats is the fee the billboard charges per confirmation.
+	ConfirmationFeeSats core.Sats
+
+	// BillboardID is the unique billboard ID for the d-tag.
+	BillboardID string
+
+	// BillboardPubkey is the billboard operator's pubkey.
+	BillboardPubkey string
+
+	// MarketplaceCoordinate is the marketplace coordinate (38188:pubkey:id).
+	MarketplaceCoordinate string
+
+	// MarketplacePubkey is the marketplace's pubkey.
+	MarketplacePubkey string
+
+	// MarketplaceID is the marketplace ID.
+	MarketplaceID string
+
+	// Relays is the list of relay URLs.
+	Relays []string
+
+	// Kind is the kind of content the billboard displays.
+	Kind int
+
+	// URL is the billboard URL.
+	URL string
+
+	// BlockHeight is the Bitcoin block height.
+	BlockHeight int64
+}
+
+// CreateBillboard creates a BILLBOARD event (kind 38288) signed with a hex or nsec private key.
+func CreateBillboard(private_key string, params BillboardParams) (*nostr.Event, error) {
+	signer, err := newKeySigner(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return CreateBillboardWithSigner(context.Background(), signer, params)
+}
+
+// CreateBillboardWithSigner creates a BILLBOARD event (kind 38288) signed by signer.
+func CreateBillboardWithSigner(ctx context.Context, signer core.Signer, params BillboardParams) (*nostr.Event, error) {
+	// Resolve NIP-19 encoded keys and pointers
+	p := &pointers{}
+	p.pubkey(&params.BillboardPubkey)
+	p.coordinate(&params.MarketplaceCoordinate)
+	p.pubkey(&params.MarketplacePubkey)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	// Build content
+	content := core.BillboardData{
+		Name:                 params.Name,
+		Description:          params.Description,
+		ConfirmationFeeSats:  params.ConfirmationFeeSats,
+		RefBillboardPubkey:   params.BillboardPubkey,
+		RefBillboardID:       params.BillboardID,
+		RefMarketplacePubkey: params.MarketplacePubkey,
+		RefMarketplaceID:     params.MarketplaceID,
+	}
+
+	content_json, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build tags
+	tags := nostr.Tags{}
+
+	// Add d-tag
+	tags = append(tags, nostr.Tag{"d", buildDTag(core.KindBillboard, params.BillboardID)})
+
+	// Add block height tag
+	tags = append(tags, nostr.Tag{"t", fmt.Sprintf("%d", params.BlockHeight)})
+
+	// Add marketplace coordinate
+	if params.MarketplaceCoordinate != "" {
+		tags = append(tags, nostr.Tag{"a", params.MarketplaceCoordinate})
+	}
+
+	// Add pubkey tags
+	if params.BillboardPubkey != "" {
+		tags = append(tags, nostr.Tag{"p", params.BillboardPubkey})
+	}
+	if params.MarketplacePubkey != "" {
+		tags = append(tags, nostr.Tag{"p", params.MarketplacePubkey})
+	}
+
+	// Add relay list
+	for _, relay := range params.Relays {
+		tags = append(tags, nostr.Tag{"r", relay})
+	}
+
+	// Add displayed content kind
+	if params.Kind != 0 {
+		tags = append(tags, nostr.Tag{"k", fmt.Sprintf("%d", params.Kind)})
+	}
+
+	// Add billboard URL
+	if params.URL != "" {
+		tags = append(tags, nostr.Tag{"u", params.URL})
+	}
+
+	return signEvent(ctx, signer, core.KindBillboard, tags, string(content_json))
+}
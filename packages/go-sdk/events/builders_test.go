@@ -0,0 +1,70 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/validation"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// TestBuildersEmitRequiredFields checks each builder can produce an event ATTN-01
+// validation accepts. Zero fees must still be serialized, since match_fee_sats and
+// confirmation_fee_sats are required, and ATTENTION events must reference the
+// provider's blocked lists and carry their p, r and k tags.
+func TestBuildersEmitRequiredFields(t *testing.T) {
+	pubkey, err := nostr.GetPublicKey(vectorSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coordinate := func(kind int, id string) string {
+		return core.NewCoordinate(kind, pubkey, core.NewDTag(kind, id)).String()
+	}
+	block_coordinate := core.NewCoordinate(core.KindCityBlock, pubkey, core.NewDTag(core.KindCityBlock, "870500:"+pubkey)).String()
+
+	marketplace, err := CreateMarketplace(vectorSecretKey, MarketplaceParams{
+		Name:              "Free market",
+		Description:       "No fees",
+		AdminPubkey:       pubkey,
+		MinDuration:       15000,
+		MaxDuration:       60000,
+		MarketplaceID:     "mp",
+		MarketplacePubkey: pubkey,
+		BlockHeight:       870500,
+		RefClockPubkey:    pubkey,
+		RefBlockID:        "org.cityprotocol:block:870500:" + pubkey,
+		BlockCoordinate:   block_coordinate,
+		KindList:          []int{34236},
+		RelayList:         []string{"wss://relay.example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attention, err := CreateAttention(vectorSecretKey, AttentionParams{
+		Ask:                         500,
+		MinDuration:                 15000,
+		MaxDuration:                 60000,
+		BlockedPromotionsID:         core.NIP51BlockedPromotions,
+		BlockedPromotersID:          core.NIP51BlockedPromoters,
+		MarketplaceCoordinate:       coordinate(core.KindMarketplace, "mp"),
+		BlockedPromotionsCoordinate: ListCoordinate(pubkey, core.NIP51BlockedPromotions),
+		BlockedPromotersCoordinate:  ListCoordinate(pubkey, core.NIP51BlockedPromoters),
+		MarketplacePubkey:           pubkey,
+		MarketplaceID:               "mp",
+		Relays:                      []string{"wss://relay.example.com"},
+		Kinds:                       []int{34236},
+		BlockHeight:                 870500,
+		AttentionID:                 "at",
+		AttentionPubkey:             pubkey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, event := range []*nostr.Event{marketplace, attention} {
+		if result := validation.ValidateEvent(event); !result.Valid {
+			t.Errorf("kind %d: expected a valid event, got %s (%s)", event.Kind, result.Message, result.Code)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// ConfirmationRefs holds the match references every confirmation kind carries.
+// Validation requires all coordinates, pubkeys and IDs; BILLBOARD_CONFIRMATION,
+// ATTENTION_CONFIRMATION and MARKETPLACE_CONFIRMATION also require the four party
+// event IDs.
+type ConfirmationRefs struct {
+	// MatchEventID is the ID of the MATCH event being confirmed.
+	MatchEventID string
+
+	// MatchID is the match ID from the MATCH d-tag.
+	MatchID string
+
+	// Coordinates of the matched events
+	MarketplaceCoordinate string
+	BillboardCoordinate   string
+	PromotionCoordinate   string
+	AttentionCoordinate   string
+	MatchCoordinate       string
+
+	// Reference pubkeys
+	MarketplacePubkey string
+	BillboardPubkey   string
+	PromotionPubkey   string
+	AttentionPubkey   string
+
+	// Reference IDs
+	MarketplaceID string
+	BillboardID   string
+	PromotionID   string
+	AttentionID   string
+
+	// Event IDs of the matched events
+	MarketplaceEventID string
+	BillboardEventID   string
+	PromotionEventID   string
+	AttentionEventID   string
+
+	// Relays is the list of relay URLs.
+	Relays []string
+}
+
+// ConfirmationRefsFromMatch fills the references a confirmation copies from a decoded
+// MATCH event. The party event IDs are not part of a match and must be set separately.
+func ConfirmationRefsFromMatch(match *core.Match) ConfirmationRefs {
+	return ConfirmationRefs{
+		MatchEventID:          match.Event.ID,
+		MatchID:               match.Data.RefMatchID,
+		MarketplaceCoordinate: match.MarketplaceCoordinate,
+		BillboardCoordinate:   match.BillboardCoordinate,
+		PromotionCoordinate:   match.PromotionCoordinate,
+		AttentionCoordinate:   match.AttentionCoordinate,
+		MatchCoordinate:       match.Coordinate,
+		MarketplacePubkey:     match.Data.RefMarketplacePubkey,
+		BillboardPubkey:       match.Data.RefBillboardPubkey,
+		PromotionPubkey:       match.Data.RefPromotionPubkey,
+		AttentionPubkey:       match.Data.RefAttentionPubkey,
+		MarketplaceID:         match.Data.RefMarketplaceID,
+		BillboardID:           match.Data.RefBillboardID,
+		PromotionID:           match.Data.RefPromotionID,
+		AttentionID:           match.Data.RefAttentionID,
+		Relays:                match.Relays,
+	}
+}
+
+// BillboardConfirmationParams holds parameters for creating a billboard confirmation event.
+type BillboardConfirmationParams struct {
+	ConfirmationRefs
+
+	// ConfirmationID is the unique confirmation ID for the d-tag.
+	ConfirmationID string
+
+	// BlockHeight is the Bitcoin block height.
+	BlockHeight int64
+}
+
+// CreateBillboardConfirmation creates a BILLBOARD_CONFIRMATION event (kind 38588) signed with a hex or nsec private key.
+func CreateBillboardConfirmation(private_key string, params BillboardConfirmationParams) (*nostr.Event, error) {
+	signer, err := newKeySigner(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return CreateBillboardConfirmationWithSigner(context.Background(), signer, params)
+}
+
+// CreateBillboardConfirmationWithSigner creates a BILLBOARD_CONFIRMATION event (kind 38588) signed by signer.
+func CreateBillboardConfirmationWithSigner(ctx context.Context, signer core.Signer, params BillboardConfirmationParams) (*nostr.Event, error) {
+	// Resolve NIP-19 encoded keys and pointers
+	p := &pointers{}
+	p.refs(&params.ConfirmationRefs)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	// Build content (only ref_* fields per ATTN-01)
+	refs := params.ConfirmationRefs
+	content := core.BillboardConfirmationData{
+		RefMatchEventID:      refs.MatchEventID,
+		RefMatchID:           refs.MatchID,
+		RefMarketplacePubkey: refs.MarketplacePubkey,
+		RefBillboardPubkey:   refs.BillboardPubkey,
+		RefPromotionPubkey:   refs.PromotionPubkey,
+		RefAttentionPubkey:   refs.AttentionPubkey,
+		RefMarketplaceID:     refs.MarketplaceID,
+		RefBillboardID:       refs.BillboardID,
+		RefPromotionID:       refs.PromotionID,
+		RefAttentionID:       refs.AttentionID,
+	}
+
+	tags := confirmationHeaderTags(core.KindBillboardConfirmation, params.ConfirmationID, params.BlockHeight, refs)
+	tags = appendConfirmationRefTags(tags, refs)
+
+	return signConfirmation(ctx, signer, core.KindBillboardConfirmation, tags, content)
+}
+
+// AttentionConfirmationParams holds parameters for creating an attention confirmation event.
+type AttentionConfirmationParams struct {
+	ConfirmationRefs
+
+	// ConfirmationID is the unique confirmation ID for the d-tag.
+	ConfirmationID string
+
+	// BlockHeight is the Bitcoin block height.
+	BlockHeight int64
+}
+
+// CreateAttentionConfirmation creates an ATTENTION_CONFIRMATION event (kind 38688) signed with a hex or nsec private key.
+func CreateAttentionConfirmation(private_key string, params AttentionConfirmationParams) (*nostr.Event, error) {
+	signer, err := newKeySigner(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return CreateAttentionConfirmationWithSigner(context.Background(), signer, params)
+}
+
+// CreateAttentionConfirmationWithSigner creates an ATTENTION_CONFIRMATION event (kind 38688) signed by signer.
+func CreateAttentionConfirmationWithSigner(ctx context.Context, signer core.Signer, params AttentionConfirmationParams) (*nostr.Event, error) {
+	// Resolve NIP-19 encoded keys and pointers
+	p := &pointers{}
+	p.refs(&params.ConfirmationRefs)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	// Build content (only ref_* fields per ATTN-01)
+	refs := params.ConfirmationRefs
+	content := core.AttentionConfirmationData{
+		RefMatchEventID:      refs.MatchEventID,
+		RefMatchID:           refs.MatchID,
+		RefMarketplacePubkey: refs.MarketplacePubkey,
+		RefBillboardPubkey:   refs.BillboardPubkey,
+		RefPromotionPubkey:   refs.PromotionPubkey,
+		RefAttentionPubkey:   refs.AttentionPubkey,
+		RefMarketplaceID:     refs.MarketplaceID,
+		RefBillboardID:       refs.BillboardID,
+		RefPromotionID:       refs.PromotionID,
+		RefAttentionID:       refs.AttentionID,
+	}
+
+	tags := confirmationHeaderTags(core.KindAttentionConfirmation, params.ConfirmationID, params.BlockHeight, refs)
+	tags = appendConfirmationRefTags(tags, refs)
+
+	return signConfirmation(ctx, signer, core.KindAttentionConfirmation, tags, content)
+}
+
+// MarketplaceConfirmationParams holds parameters for creating a marketplace confirmation event.
+type MarketplaceConfirmationParams struct {
+	ConfirmationRefs
+
+	// ConfirmationID is the unique confirmation ID for the d-tag.
+	ConfirmationID string
+
+	// BlockHeight is the Bitcoin block height.
+	BlockHeight int64
+
+	// BillboardConfirmationEventID is the ID of the BILLBOARD_CONFIRMATION event.
+	BillboardConfirmationEventID string
+
+	// AttentionConfirmationEventID is the ID of the ATTENTION_CONFIRMATION event.
+	AttentionConfirmationEventID string
+}
+
+// CreateMarketplaceConfirmation creates a MARKETPLACE_CONFIRMATION event (kind 38788) signed with a hex or nsec private key.
+func CreateMarketplaceConfirmation(private_key string, params MarketplaceConfirmationParams) (*nostr.Event, error) {
+	signer, err := newKeySigner(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return CreateMarketplaceConfirmationWithSigner(context.Background(), signer, params)
+}
+
+// CreateMarketplaceConfirmationWithSigner creates a MARKETPLACE_CONFIRMATION event (kind 38788) signed by signer.
+func CreateMarketplaceConfirmationWithSigner(ctx context.Context, signer core.Signer, params MarketplaceConfirmationParams) (*nostr.Event, error) {
+	// Resolve NIP-19 encoded keys and pointers
+	p := &pointers{}
+	p.refs(&params.ConfirmationRefs)
+	p.eventID(&params.BillboardConfirmationEventID)
+	p.eventID(&params.AttentionConfirmationEventID)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	// Build content (only ref_* fields per ATTN-01)
+	refs := params.ConfirmationRefs
+	content := core.MarketplaceConfirmationData{
+		RefMatchEventID:                 refs.MatchEventID,
+		RefMatchID:                      refs.MatchID,
+		RefBillboardConfirmationEventID: params.BillboardConfirmationEventID,
+		RefAttentionConfirmationEventID: params.AttentionConfirmationEventID,
+		RefMarketplacePubkey:            refs.MarketplacePubkey,
+		RefBillboardPubkey:              refs.BillboardPubkey,
+		RefPromotionPubkey:              refs.PromotionPubkey,
+		RefAttentionPubkey:              refs.AttentionPubkey,
+		RefMarketplaceID:                refs.MarketplaceID,
+		RefBillboardID:                  refs.BillboardID,
+		RefPromotionID:                  refs.PromotionID,
+		RefAttentionID:                  refs.AttentionID,
+	}
+
+	tags := confirmationHeaderTags(core.KindMarketplaceConfirmation, params.ConfirmationID, params.BlockHeight, refs)
+
+	// Add both party confirmations with their markers
+	tags = append(tags, nostr.Tag{"e", params.BillboardConfirmationEventID, "", "billboard_confirmation"})
+	tags = append(tags, nostr.Tag{"e", params.AttentionConfirmationEventID, "", "attention_confirmation"})
+
+	tags = appendConfirmationRefTags(tags, refs)
+
+	return signConfirmation(ctx, signer, core.KindMarketplaceConfirmation, tags, content)
+}
+
+// AttentionPaymentConfirmationParams holds parameters for creating an attention payment confirmation event.
+type AttentionPaymentConfirmationParams struct {
+	ConfirmationRefs
+
+	// ConfirmationID is the unique confirmation ID for the d-tag.
+	ConfirmationID string
+
+	// BlockHeight is the Bitcoin block height.
+	BlockHeight int64
+
+	// MarketplaceConfirmationEventID is the ID of the MARKETPLACE_CONFIRMATION event.
+	MarketplaceConfirmationEventID string
+
+	// SatsReceived is the amount the attention provider received.
+	SatsReceived core.Sats
+
+	// PaymentProof is an optional proof of payment, such as a Lightning invoice or preimage.
+	PaymentProof string
+}
+
+// CreateAttentionPaymentConfirmation creates an ATTENTION_PAYMENT_CONFIRMATION event (kind 38988) signed with a hex or nsec private key.
+func CreateAttentionPaymentConfirmation(private_key string, params AttentionPaymentConfirmationParams) (*nostr.Event, error) {
+	signer, err := newKeySigner(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return CreateAttentionPaymentConfirmationWithSigner(context.Background(), signer, params)
+}
+
+// CreateAttentionPaymentConfirmationWithSigner creates an ATTENTION_PAYMENT_CONFIRMATION event (kind 38988) signed by signer.
+func CreateAttentionPaymentConfirmationWithSigner(ctx context.Context, signer core.Signer, params AttentionPaymentConfirmationParams) (*nostr.Event, error) {
+	// Resolve NIP-19 encoded keys and pointers
+	p := &pointers{}
+	p.refs(&params.ConfirmationRefs)
+	p.eventID(&params.MarketplaceConfirmationEventID)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	// Build content (sats_received, payment_proof and ref_* fields per ATTN-01)
+	refs := params.ConfirmationRefs
+	content := core.AttentionPaymentConfirmationData{
+		SatsReceived:                      params.SatsReceived,
+		PaymentProof:                      params.PaymentProof,
+		RefMatchEventID:                   refs.MatchEventID,
+		RefMatchID:                        refs.MatchID,
+		RefMarketplaceConfirmationEventID: params.MarketplaceConfirmationEventID,
+		RefMarketplacePubkey:              refs.MarketplacePubkey,
+		RefBillboardPubkey:                refs.BillboardPubkey,
+		RefPromotionPubkey:                refs.PromotionPubkey,
+		RefAttentionPubkey:                refs.AttentionPubkey,
+		RefMarketplaceID:                  refs.MarketplaceID,
+		RefBillboardID:                    refs.BillboardID,
+		RefPromotionID:                    refs.PromotionID,
+		RefAttentionID:                    refs.AttentionID,
+	}
+
+	tags := confirmationHeaderTags(core.KindAttentionPaymentConfirmation, params.ConfirmationID, params.BlockHeight, refs)
+
+	// Add the marketplace confirmation being paid out
+	tags = append(tags, nostr.Tag{"e", params.MarketplaceConfirmationEventID, "", "marketplace_confirmation"})
+
+	tags = appendConfirmationRefTags(tags, refs)
+
+	return signConfirmation(ctx, signer, core.KindAttentionPaymentConfirmation, tags, content)
+}
+
+// confirmationHeaderTags builds the d, t and match 'e' tags shared by all confirmations.
+func confirmationHeaderTags(kind int, confirmation_id string, block_height int64, refs ConfirmationRefs) nostr.Tags {
+	return nostr.Tags{
+		{"d", buildDTag(kind, confirmation_id)},
+		{"t", fmt.Sprintf("%d", block_height)},
+		{"e", refs.MatchEventID, "", "match"},
+	}
+}
+
+// appendConfirmationRefTags adds the party event, coordinate, pubkey and relay tags.
+func appendConfirmationRefTags(tags nostr.Tags, refs ConfirmationRefs) nostr.Tags {
+	// Add party event references
+	for _, event_id := range []string{refs.MarketplaceEventID, refs.BillboardEventID, refs.PromotionEventID, refs.AttentionEventID} {
+		if event_id != "" {
+			tags = append(tags, nostr.Tag{"e", event_id})
+		}
+	}
+
+	// Add coordinate tags
+	for _, coordinate := range []string{refs.MarketplaceCoordinate, refs.BillboardCoordinate, refs.PromotionCoordinate, refs.AttentionCoordinate, refs.MatchCoordinate} {
+		if coordinate != "" {
+			tags = append(tags, nostr.Tag{"a", coordinate})
+		}
+	}
+
+	// Add pubkey tags
+	for _, pubkey := range []string{refs.MarketplacePubkey, refs.BillboardPubkey, refs.PromotionPubkey, refs.AttentionPubkey} {
+		if pubkey != "" {
+			tags = append(tags, nostr.Tag{"p", pubkey})
+		}
+	}
+
+	// Add relay list
+	for _, relay := range refs.Relays {
+		tags = append(tags, nostr.Tag{"r", relay})
+	}
+
+	return tags
+}
+
+// signConfirmation marshals the content and signs a confirmation event.
+func signConfirmation(ctx context.Context, signer core.Signer, kind int, tags nostr.Tags, content interface{}) (*nostr.Event, error) {
+	content_json, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return signEvent(ctx, signer, kind, tags, string(content_json))
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/validation"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// TestConfirmationChain builds every confirmation for a match from ConfirmationRefsFromMatch
+// and checks each one passes validation.
+func TestConfirmationChain(t *testing.T) {
+	pubkey, err := nostr.GetPublicKey(vectorSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coordinate := func(kind int, id string) string {
+		return core.NewCoordinate(kind, pubkey, core.NewDTag(kind, id)).String()
+	}
+
+	match_event, err := CreateMatch(vectorSecretKey, MatchParams{
+		MatchID:               "m1",
+		BlockHeight:           870500,
+		MarketplaceCoordinate: coordinate(core.KindMarketplace, "mp"),
+		BillboardCoordinate:   coordinate(core.KindBillboard, "bb"),
+		PromotionCoordinate:   coordinate(core.KindPromotion, "pr"),
+		AttentionCoordinate:   coordinate(core.KindAttention, "at"),
+		MarketplacePubkey:     pubkey,
+		BillboardPubkey:       pubkey,
+		PromotionPubkey:       pubkey,
+		AttentionPubkey:       pubkey,
+		Relays:                []string{"wss://relay.example.com"},
+		MarketplaceID:         "mp",
+		BillboardID:           "bb",
+		PromotionID:           "pr",
+		AttentionID:           "at",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	match, err := core.DecodeMatch(match_event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := ConfirmationRefsFromMatch(match)
+	refs.MarketplaceEventID = strings.Repeat("01", 32)
+	refs.BillboardEventID = strings.Repeat("02", 32)
+	refs.PromotionEventID = strings.Repeat("03", 32)
+	refs.AttentionEventID = strings.Repeat("04", 32)
+
+	billboard_confirmation, err := CreateBillboardConfirmation(vectorSecretKey, BillboardConfirmationParams{ConfirmationRefs: refs, ConfirmationID: "bc1", BlockHeight: 870501})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attention_confirmation, err := CreateAttentionConfirmation(vectorSecretKey, AttentionConfirmationParams{ConfirmationRefs: refs, ConfirmationID: "ac1", BlockHeight: 870501})
+	if err != nil {
+		t.Fatal(err)
+	}
+	marketplace_confirmation, err := CreateMarketplaceConfirmation(vectorSecretKey, MarketplaceConfirmationParams{
+		ConfirmationRefs:             refs,
+		ConfirmationID:               "mc1",
+		BlockHeight:                  870502,
+		BillboardConfirmationEventID: billboard_confirmation.ID,
+		AttentionConfirmationEventID: attention_confirmation.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment_confirmation, err := CreateAttentionPaymentConfirmation(vectorSecretKey, AttentionPaymentConfirmationParams{
+		ConfirmationRefs:               refs,
+		ConfirmationID:                 "pc1",
+		BlockHeight:                    870503,
+		MarketplaceConfirmationEventID: marketplace_confirmation.ID,
+		SatsReceived:                   3000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, event := range []*nostr.Event{billboard_confirmation, attention_confirmation, marketplace_confirmation, payment_confirmation} {
+		if result := validation.ValidateEvent(event); !result.Valid {
+			t.Errorf("kind %d is invalid: %s (%s)", event.Kind, result.Message, result.Code)
+		}
+	}
+}
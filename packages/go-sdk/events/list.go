@@ -0,0 +1,247 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// ListParams holds parameters for creating an ATTN Protocol NIP-51 list event.
+type ListParams struct {
+	// Type is the list type and d-tag, one of core.AllListTypes().
+	Type string
+
+	// Description is the list description.
+	Description string
+
+	// BlockHeight is the Bitcoin block height. Zero omits the 't' tag.
+	BlockHeight int64
+
+	// Entries are the public entries, published as tags.
+	Entries []core.ListEntry
+
+	// PrivateEntries are NIP-44 encrypted to the author and published as content,
+	// so only the author can read them.
+	PrivateEntries []core.ListEntry
+
+	// ExtraTags are public tags that are not entries of the list type, such as NIP-51
+	// 'title' and 'image' or entries another client added. ReadList keeps them so an
+	// edit re-publishes them unchanged. The 'd', 't' and 'description' tags are set
+	// from the fields above and cannot be extra tags.
+	ExtraTags nostr.Tags
+
+	// PrivateExtraTags are private tags that are not entries of the list type, kept
+	// and encrypted with PrivateEntries.
+	PrivateExtraTags nostr.Tags
+}
+
+// listManagedTags are the list tags CreateListWithSigner sets from ListParams fields.
+var listManagedTags = map[string]bool{"d": true, "t": true, "description": true}
+
+// ListCoordinate returns the coordinate of a pubkey's list (30000:pubkey:list_type),
+// the value ATTENTION events reference in their list 'a' tags.
+func ListCoordinate(pubkey string, list_type string) string {
+	return core.Coordinate{Kind: core.KindFollowSet, Pubkey: pubkey, DTag: list_type}.String()
+}
+
+// ResolveListEntry resolves an entry's NIP-19 value (naddr, nevent or npub) and
+// checks the list type holds it.
+func ResolveListEntry(list_type string, entry core.ListEntry) (core.ListEntry, error) {
+	p := &pointers{}
+	switch entry.Tag {
+	case "a":
+		p.coordinate(&entry.Value)
+	case "e":
+		p.eventID(&entry.Value)
+	case "p":
+		p.pubkey(&entry.Value)
+	}
+	if p.err != nil {
+		return core.ListEntry{}, p.err
+	}
+
+	resolved, ok, err := core.ParseListEntry(list_type, nostr.Tag{entry.Tag, entry.Value})
+	if err != nil {
+		return core.ListEntry{}, err
+	}
+	if !ok {
+		return core.ListEntry{}, fmt.Errorf("%s lists do not hold '%s' entries", list_type, entry.Tag)
+	}
+	return resolved, nil
+}
+
+// CreateList creates an ATTN Protocol NIP-51 list event (kind 30000) signed with a hex
+// or nsec private key.
+func CreateList(private_key string, params ListParams) (*nostr.Event, error) {
+	signer, err := newKeySigner(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return CreateListWithSigner(context.Background(), signer, params)
+}
+
+// CreateListWithSigner creates an ATTN Protocol NIP-51 list event (kind 30000) signed
+// by signer. Duplicate entries are dropped, including private entries that are also
+// public. Private entries need a signer that can encrypt (see core.Encrypter); they
+// replace the JSON content, so the description moves to a 'description' tag.
+func CreateListWithSigner(ctx context.Context, signer core.Signer, params ListParams) (*nostr.Event, error) {
+	if !core.IsListType(params.Type) {
+		return nil, fmt.Errorf("%w: %s", core.ErrUnknownListType, params.Type)
+	}
+
+	for _, tag := range params.ExtraTags {
+		if len(tag) == 0 || listManagedTags[tag[0]] {
+			return nil, fmt.Errorf("extra list tag %v is empty or set from ListParams fields", tag)
+		}
+	}
+	for _, tag := range params.PrivateExtraTags {
+		if len(tag) == 0 {
+			return nil, fmt.Errorf("private extra list tags must not be empty")
+		}
+	}
+
+	// Resolve and deduplicate entries
+	seen := make(map[core.ListEntry]bool)
+	entries, err := resolveListEntries(params.Type, params.Entries, seen)
+	if err != nil {
+		return nil, err
+	}
+	private_entries, err := resolveListEntries(params.Type, params.PrivateEntries, seen)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build tags
+	tags := nostr.Tags{}
+
+	// Add d-tag
+	tags = append(tags, nostr.Tag{"d", params.Type})
+
+	// Add block height tag
+	if params.BlockHeight > 0 {
+		tags = append(tags, nostr.Tag{"t", fmt.Sprintf("%d", params.BlockHeight)})
+	}
+
+	// Add public entries
+	for _, entry := range entries {
+		tags = append(tags, nostr.Tag{entry.Tag, entry.Value})
+	}
+	tags = append(tags, params.ExtraTags...)
+
+	if len(private_entries) == 0 && len(params.PrivateExtraTags) == 0 {
+		content_json, err := json.Marshal(map[string]string{"description": params.Description})
+		if err != nil {
+			return nil, err
+		}
+		return signEvent(ctx, signer, core.KindFollowSet, tags, string(content_json))
+	}
+
+	// Encrypt private entries to the author as a JSON tag array (NIP-51)
+	private_tags := make(nostr.Tags, 0, len(private_entries)+len(params.PrivateExtraTags))
+	for _, entry := range private_entries {
+		private_tags = append(private_tags, nostr.Tag{entry.Tag, entry.Value})
+	}
+	private_tags = append(private_tags, params.PrivateExtraTags...)
+	private_json, err := json.Marshal(private_tags)
+	if err != nil {
+		return nil, err
+	}
+	pubkey, err := signer.GetPublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	content, err := core.Encrypt(ctx, signer, pubkey, string(private_json))
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Description != "" {
+		tags = append(tags, nostr.Tag{"description", params.Description})
+	}
+
+	return signEvent(ctx, signer, core.KindFollowSet, tags, content)
+}
+
+// ReadList decodes a list event back into ListParams, decrypting private entries with
+// signer. Pass a nil signer to read public entries only; private entries of a list
+// signer did not author are skipped as well, since only the author can decrypt them.
+// Tags that are not well-formed entries of the list type are kept in ExtraTags and
+// PrivateExtraTags.
+func ReadList(ctx context.Context, signer core.Signer, event *nostr.Event) (ListParams, error) {
+	list, err := core.ParseList(event)
+	if err != nil {
+		return ListParams{}, err
+	}
+
+	params := ListParams{
+		Type:        list.Type,
+		Description: list.Description,
+		BlockHeight: list.BlockHeight,
+	}
+	for _, tag := range event.Tags {
+		if entry, ok, err := core.ParseListEntry(list.Type, tag); ok && err == nil {
+			params.Entries = append(params.Entries, entry)
+		} else if len(tag) > 0 && !listManagedTags[tag[0]] {
+			params.ExtraTags = append(params.ExtraTags, tag)
+		}
+	}
+
+	if signer == nil || !isEncryptedContent(event.Content) {
+		return params, nil
+	}
+	pubkey, err := signer.GetPublicKey(ctx)
+	if err != nil {
+		return ListParams{}, err
+	}
+	if pubkey != event.PubKey {
+		return params, nil
+	}
+
+	plaintext, err := core.Decrypt(ctx, signer, event.PubKey, event.Content)
+	if err != nil {
+		return ListParams{}, err
+	}
+	var private_tags nostr.Tags
+	if err := json.Unmarshal([]byte(plaintext), &private_tags); err != nil {
+		return ListParams{}, fmt.Errorf("invalid private list entries: %w", err)
+	}
+	for _, tag := range private_tags {
+		if entry, ok, err := core.ParseListEntry(list.Type, tag); ok && err == nil {
+			params.PrivateEntries = append(params.PrivateEntries, entry)
+		} else if len(tag) > 0 {
+			params.PrivateExtraTags = append(params.PrivateExtraTags, tag)
+		}
+	}
+
+	return params, nil
+}
+
+// resolveListEntries resolves entries, skipping those already in seen.
+func resolveListEntries(list_type string, entries []core.ListEntry, seen map[core.ListEntry]bool) ([]core.ListEntry, error) {
+	var resolved []core.ListEntry
+	for _, entry := range entries {
+		entry, err := ResolveListEntry(list_type, entry)
+		if err != nil {
+			return nil, err
+		}
+		if seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		resolved = append(resolved, entry)
+	}
+	return resolved, nil
+}
+
+// isEncryptedContent reports whether list content holds encrypted private entries
+// rather than being empty or a JSON object.
+func isEncryptedContent(content string) bool {
+	if content == "" {
+		return false
+	}
+	var object map[string]interface{}
+	return json.Unmarshal([]byte(content), &object) != nil
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/validation"
+	"github.com/joinnextblock/attn-protocol/go-sdk/nip19"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// TestListRoundTrip builds a blocked promoters list with public and private entries
+// and reads it back as its author and as someone else.
+func TestListRoundTrip(t *testing.T) {
+	blocked := strings.Repeat("ab", 32)
+	hidden := strings.Repeat("cd", 32)
+	npub, err := nip19.EncodePublicKey(blocked)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := CreateList(vectorSecretKey, ListParams{
+		Type:           core.NIP51BlockedPromoters,
+		Description:    "Spammers",
+		BlockHeight:    870500,
+		Entries:        []core.ListEntry{{Tag: "p", Value: npub}, {Tag: "p", Value: blocked}},
+		PrivateEntries: []core.ListEntry{{Tag: "p", Value: hidden}, {Tag: "p", Value: blocked}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := validation.ValidateListEvent(event); !result.Valid {
+		t.Fatalf("expected a valid list, got %s", result.Message)
+	}
+	if strings.Contains(event.Content, hidden) || event.Tags.FindWithValue("p", hidden) != nil {
+		t.Error("expected the private entry to stay out of the published event")
+	}
+	if coordinate := ListCoordinate(event.PubKey, core.NIP51BlockedPromoters); coordinate != "30000:"+event.PubKey+":"+core.NIP51BlockedPromoters {
+		t.Errorf("unexpected list coordinate %s", coordinate)
+	}
+
+	signer, _ := core.NewKeySigner(vectorSecretKey)
+	params, err := ReadList(context.Background(), signer, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Description != "Spammers" || params.BlockHeight != 870500 {
+		t.Errorf("expected the description and block height back, got %+v", params)
+	}
+	if len(params.Entries) != 1 || params.Entries[0].Value != blocked {
+		t.Errorf("expected the npub entry resolved and deduplicated, got %+v", params.Entries)
+	}
+	if len(params.PrivateEntries) != 1 || params.PrivateEntries[0].Value != hidden {
+		t.Errorf("expected only the private entry that is not public, got %+v", params.PrivateEntries)
+	}
+
+	other, _ := core.NewKeySigner(strings.Repeat("02", 32))
+	params, err = ReadList(context.Background(), other, event)
+	if err != nil || len(params.Entries) != 1 || len(params.PrivateEntries) != 0 {
+		t.Errorf("expected another signer to see public entries only, got %+v (%v)", params, err)
+	}
+}
+
+// TestListKeepsForeignTags checks tags that are not entries of the list type survive
+// a read and re-create, so editing a list does not drop them.
+func TestListKeepsForeignTags(t *testing.T) {
+	blocked := strings.Repeat("ab", 32)
+	event, err := CreateList(vectorSecretKey, ListParams{
+		Type:             core.NIP51BlockedPromoters,
+		Entries:          []core.ListEntry{{Tag: "p", Value: blocked}},
+		PrivateEntries:   []core.ListEntry{{Tag: "p", Value: strings.Repeat("cd", 32)}},
+		ExtraTags:        nostr.Tags{{"title", "Spammers"}, {"p", "not-a-pubkey"}},
+		PrivateExtraTags: nostr.Tags{{"note", "reported twice"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, _ := core.NewKeySigner(vectorSecretKey)
+	params, err := ReadList(context.Background(), signer, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(params.Entries) != 1 || len(params.ExtraTags) != 2 || params.ExtraTags[0][0] != "title" || params.ExtraTags[1][1] != "not-a-pubkey" {
+		t.Errorf("expected the title and malformed entry kept as extra tags, got %+v", params)
+	}
+	if len(params.PrivateExtraTags) != 1 || params.PrivateExtraTags[0][1] != "reported twice" {
+		t.Errorf("expected the private note kept, got %+v", params.PrivateExtraTags)
+	}
+
+	params.Entries = nil
+	edited, err := CreateListWithSigner(context.Background(), signer, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Tags.FindWithValue("title", "Spammers") == nil || edited.Tags.FindWithValue("p", "not-a-pubkey") == nil {
+		t.Errorf("expected the edit to re-publish the foreign tags, got %v", edited.Tags)
+	}
+	if reread, err := ReadList(context.Background(), signer, edited); err != nil || len(reread.PrivateExtraTags) != 1 {
+		t.Errorf("expected the private note to survive the edit, got %+v (%v)", reread.PrivateExtraTags, err)
+	}
+
+	_, err = CreateList(vectorSecretKey, ListParams{Type: core.NIP51BlockedPromoters, ExtraTags: nostr.Tags{{"d", "other"}}})
+	if err == nil {
+		t.Error("expected an error for an extra 'd' tag")
+	}
+}
+
+func TestListRejectsEntriesTheTypeDoesNotHold(t *testing.T) {
+	_, err := CreateList(vectorSecretKey, ListParams{
+		Type:    core.NIP51BlockedPromoters,
+		Entries: []core.ListEntry{{Tag: "e", Value: strings.Repeat("ab", 32)}},
+	})
+	if err == nil {
+		t.Error("expected an error for an 'e' entry on a blocked promoters list")
+	}
+
+	_, err = CreateList(vectorSecretKey, ListParams{Type: "org.attnprotocol:unknown"})
+	if !errors.Is(err, core.ErrUnknownListType) {
+		t.Errorf("expected ErrUnknownListType, got %v", err)
+	}
+}
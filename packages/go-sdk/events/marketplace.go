@@ -1,9 +1,9 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/joinnextblock/attn-protocol/go-core"
 	"github.com/nbd-wtf/go-nostr"
@@ -27,10 +27,10 @@ type MarketplaceParams struct {
 	MaxDuration int64
 
 	// MatchFeeSats is the fee per match in satoshis.
-	MatchFeeSats int64
+	MatchFeeSats core.Sats
 
 	// ConfirmationFeeSats is the fee per confirmation in satoshis.
-	ConfirmationFeeSats int64
+	ConfirmationFeeSats core.Sats
 
 	// MarketplaceID is the unique marketplace ID for the d-tag.
 	MarketplaceID string
@@ -66,8 +66,27 @@ type MarketplaceParams struct {
 	MatchCount     int64
 }
 
-// CreateMarketplace creates a MARKETPLACE event (kind 38188).
+// CreateMarketplace creates a MARKETPLACE event (kind 38188) signed with a hex or nsec private key.
 func CreateMarketplace(private_key string, params MarketplaceParams) (*nostr.Event, error) {
+	signer, err := newKeySigner(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return CreateMarketplaceWithSigner(context.Background(), signer, params)
+}
+
+// CreateMarketplaceWithSigner creates a MARKETPLACE event (kind 38188) signed by signer.
+func CreateMarketplaceWithSigner(ctx context.Context, signer core.Signer, params MarketplaceParams) (*nostr.Event, error) {
+	// Resolve NIP-19 encoded keys and pointers
+	p := &pointers{}
+	p.pubkey(&params.AdminPubkey)
+	p.pubkey(&params.MarketplacePubkey)
+	p.pubkey(&params.RefClockPubkey)
+	p.coordinate(&params.BlockCoordinate)
+	if p.err != nil {
+		return nil, p.err
+	}
+
 	// Build content
 	content := core.MarketplaceData{
 		Name:                 params.Name,
@@ -96,11 +115,7 @@ func CreateMarketplace(private_key string, params MarketplaceParams) (*nostr.Eve
 	tags := nostr.Tags{}
 
 	// Add d-tag
-	d_tag := params.MarketplaceID
-	if d_tag == "" {
-		d_tag = fmt.Sprintf("org.attnprotocol:marketplace:%d", time.Now().UnixNano())
-	}
-	tags = append(tags, nostr.Tag{"d", d_tag})
+	tags = append(tags, nostr.Tag{"d", buildDTag(core.KindMarketplace, params.MarketplaceID)})
 
 	// Add block height tag
 	tags = append(tags, nostr.Tag{"t", fmt.Sprintf("%d", params.BlockHeight)})
@@ -115,30 +130,23 @@ func CreateMarketplace(private_key string, params MarketplaceParams) (*nostr.Eve
 		tags = append(tags, nostr.Tag{"k", fmt.Sprintf("%d", kind)})
 	}
 
-	// Add relay list
-	for _, relay := range params.RelayList {
-		tags = append(tags, nostr.Tag{"r", relay})
+	// Add marketplace and clock pubkeys
+	if params.MarketplacePubkey != "" {
+		tags = append(tags, nostr.Tag{"p", params.MarketplacePubkey})
 	}
-
-	// Get public key
-	pk, err := nostr.GetPublicKey(private_key)
-	if err != nil {
-		return nil, err
+	if params.RefClockPubkey != "" {
+		tags = append(tags, nostr.Tag{"p", params.RefClockPubkey})
 	}
 
-	// Create event
-	event := &nostr.Event{
-		PubKey:    pk,
-		CreatedAt: nostr.Timestamp(time.Now().Unix()),
-		Kind:      core.KindMarketplace,
-		Tags:      tags,
-		Content:   string(content_json),
+	// Add relay list
+	for _, relay := range params.RelayList {
+		tags = append(tags, nostr.Tag{"r", relay})
 	}
 
-	// Sign event
-	if err := event.Sign(private_key); err != nil {
-		return nil, err
+	// Add website URL
+	if params.WebsiteURL != "" {
+		tags = append(tags, nostr.Tag{"u", params.WebsiteURL})
 	}
 
-	return event, nil
+	return signEvent(ctx, signer, core.KindMarketplace, tags, string(content_json))
 }
@@ -1,9 +1,9 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/joinnextblock/attn-protocol/go-core"
 	"github.com/nbd-wtf/go-nostr"
@@ -35,6 +35,12 @@ type MatchParams struct {
 	PromotionPubkey   string
 	AttentionPubkey   string
 
+	// Relays is the list of relay URLs.
+	Relays []string
+
+	// Kinds is the list of content kinds.
+	Kinds []int
+
 	// Reference IDs
 	MarketplaceID string
 	BillboardID   string
@@ -42,8 +48,31 @@ type MatchParams struct {
 	AttentionID   string
 }
 
-// CreateMatch creates a MATCH event (kind 38888).
+// CreateMatch creates a MATCH event (kind 38888) signed with a hex or nsec private key.
 func CreateMatch(private_key string, params MatchParams) (*nostr.Event, error) {
+	signer, err := newKeySigner(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return CreateMatchWithSigner(context.Background(), signer, params)
+}
+
+// CreateMatchWithSigner creates a MATCH event (kind 38888) signed by signer.
+func CreateMatchWithSigner(ctx context.Context, signer core.Signer, params MatchParams) (*nostr.Event, error) {
+	// Resolve NIP-19 encoded keys and pointers
+	p := &pointers{}
+	p.coordinate(&params.MarketplaceCoordinate)
+	p.coordinate(&params.BillboardCoordinate)
+	p.coordinate(&params.PromotionCoordinate)
+	p.coordinate(&params.AttentionCoordinate)
+	p.pubkey(&params.MarketplacePubkey)
+	p.pubkey(&params.BillboardPubkey)
+	p.pubkey(&params.PromotionPubkey)
+	p.pubkey(&params.AttentionPubkey)
+	if p.err != nil {
+		return nil, p.err
+	}
+
 	// Build content (only ref_* fields per ATTN-01)
 	content := core.MatchData{
 		RefMatchID:           params.MatchID,
@@ -66,11 +95,7 @@ func CreateMatch(private_key string, params MatchParams) (*nostr.Event, error) {
 	tags := nostr.Tags{}
 
 	// Add d-tag
-	d_tag := params.MatchID
-	if d_tag == "" {
-		d_tag = fmt.Sprintf("org.attnprotocol:match:%d", time.Now().UnixNano())
-	}
-	tags = append(tags, nostr.Tag{"d", d_tag})
+	tags = append(tags, nostr.Tag{"d", buildDTag(core.KindMatch, params.MatchID)})
 
 	// Add block height tag
 	tags = append(tags, nostr.Tag{"t", fmt.Sprintf("%d", params.BlockHeight)})
@@ -103,25 +128,15 @@ func CreateMatch(private_key string, params MatchParams) (*nostr.Event, error) {
 		tags = append(tags, nostr.Tag{"p", params.AttentionPubkey})
 	}
 
-	// Get public key
-	pk, err := nostr.GetPublicKey(private_key)
-	if err != nil {
-		return nil, err
+	// Add relay list
+	for _, relay := range params.Relays {
+		tags = append(tags, nostr.Tag{"r", relay})
 	}
 
-	// Create event
-	event := &nostr.Event{
-		PubKey:    pk,
-		CreatedAt: nostr.Timestamp(time.Now().Unix()),
-		Kind:      core.KindMatch,
-		Tags:      tags,
-		Content:   string(content_json),
-	}
-
-	// Sign event
-	if err := event.Sign(private_key); err != nil {
-		return nil, err
+	// Add kind list
+	for _, kind := range params.Kinds {
+		tags = append(tags, nostr.Tag{"k", fmt.Sprintf("%d", kind)})
 	}
 
-	return event, nil
+	return signEvent(ctx, signer, core.KindMatch, tags, string(content_json))
 }
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/nip19"
+)
+
+// pointers resolves NIP-19 encoded builder parameters in place: npub pubkeys to hex,
+// naddr pointers to <kind>:<pubkey>:<d_tag> coordinates and nevent pointers to hex
+// event ids. Values already in those forms are left as they are. Any other value,
+// such as an nsec given for a pubkey, fails instead of being published as is. The
+// first error is kept in err.
+type pointers struct {
+	err error
+}
+
+// pubkey resolves an npub.
+func (p *pointers) pubkey(value *string) {
+	p.resolve(value, nip19.DecodePublicKey)
+}
+
+// coordinate resolves an naddr.
+func (p *pointers) coordinate(value *string) {
+	p.resolve(value, nip19.DecodeCoordinate)
+}
+
+// eventID resolves an nevent.
+func (p *pointers) eventID(value *string) {
+	p.resolve(value, nip19.DecodeEventID)
+}
+
+// contentID resolves an nevent to its hex event id. Other values are kept, since
+// the promoted content's id need not be an event id, but other NIP-19 entities fail.
+func (p *pointers) contentID(value *string) {
+	if nip19.IsEntity(*value) {
+		p.eventID(value)
+	}
+}
+
+// resolve replaces value with its decoded and checked form.
+func (p *pointers) resolve(value *string, decode func(string) (string, error)) {
+	if p.err != nil || *value == "" {
+		return
+	}
+	decoded, err := decode(*value)
+	if err != nil {
+		p.err = err
+		return
+	}
+	*value = decoded
+}
+
+// refs resolves every pointer in confirmation refs.
+func (p *pointers) refs(refs *ConfirmationRefs) {
+	p.eventID(&refs.MatchEventID)
+	p.coordinate(&refs.MarketplaceCoordinate)
+	p.coordinate(&refs.BillboardCoordinate)
+	p.coordinate(&refs.PromotionCoordinate)
+	p.coordinate(&refs.AttentionCoordinate)
+	p.coordinate(&refs.MatchCoordinate)
+	p.pubkey(&refs.MarketplacePubkey)
+	p.pubkey(&refs.BillboardPubkey)
+	p.pubkey(&refs.PromotionPubkey)
+	p.pubkey(&refs.AttentionPubkey)
+	p.eventID(&refs.MarketplaceEventID)
+	p.eventID(&refs.BillboardEventID)
+	p.eventID(&refs.PromotionEventID)
+	p.eventID(&refs.AttentionEventID)
+}
+
+// newKeySigner creates an in-memory signer from a hex or nsec private key.
+func newKeySigner(private_key string) (*core.KeySigner, error) {
+	decoded, err := nip19.DecodePrivateKey(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return core.NewKeySigner(decoded)
+}
@@ -0,0 +1,93 @@
+package events
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/nip19"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// TestBuildersAcceptNIP19 checks nsec keys, npub pubkeys and naddr coordinates produce
+// the same tags as their hex and raw forms.
+func TestBuildersAcceptNIP19(t *testing.T) {
+	pubkey, err := nostr.GetPublicKey(vectorSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coordinate := func(kind int, id string) string {
+		return core.NewCoordinate(kind, pubkey, core.NewDTag(kind, id)).String()
+	}
+	params := MatchParams{
+		MatchID:               "m1",
+		BlockHeight:           870500,
+		MarketplaceCoordinate: coordinate(core.KindMarketplace, "mp"),
+		BillboardCoordinate:   coordinate(core.KindBillboard, "bb"),
+		PromotionCoordinate:   coordinate(core.KindPromotion, "pr"),
+		AttentionCoordinate:   coordinate(core.KindAttention, "at"),
+		MarketplacePubkey:     pubkey,
+		BillboardPubkey:       pubkey,
+		PromotionPubkey:       pubkey,
+		AttentionPubkey:       pubkey,
+	}
+	want, err := CreateMatch(vectorSecretKey, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nsec, _ := nip19.EncodePrivateKey(vectorSecretKey)
+	npub, _ := nip19.EncodePublicKey(pubkey)
+	encoded := params
+	encoded.MarketplaceCoordinate, _ = nip19.EncodeCoordinate(params.MarketplaceCoordinate, "wss://relay.example.com")
+	encoded.PromotionCoordinate, _ = nip19.EncodeCoordinate(params.PromotionCoordinate)
+	encoded.MarketplacePubkey = npub
+	encoded.AttentionPubkey = npub
+
+	got, err := CreateMatch(nsec, encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PubKey != want.PubKey || !reflect.DeepEqual(got.Tags, want.Tags) || got.Content != want.Content {
+		t.Errorf("expected NIP-19 parameters to resolve to the hex forms:\n got %v\nwant %v", got.Tags, want.Tags)
+	}
+
+	encoded.BillboardPubkey = "npub1invalid"
+	if _, err := CreateMatch(vectorSecretKey, encoded); !errors.Is(err, nip19.ErrInvalidEntity) {
+		t.Errorf("expected ErrInvalidEntity, got %v", err)
+	}
+}
+
+// TestBuildersRejectOtherEntities checks a private key or other entity given for a
+// pubkey, coordinate or event id fails instead of being published.
+func TestBuildersRejectOtherEntities(t *testing.T) {
+	pubkey, _ := nostr.GetPublicKey(vectorSecretKey)
+	nsec, _ := nip19.EncodePrivateKey(vectorSecretKey)
+	params := MatchParams{
+		MatchID:               "m1",
+		BlockHeight:           870500,
+		MarketplaceCoordinate: core.NewCoordinate(core.KindMarketplace, pubkey, core.NewDTag(core.KindMarketplace, "mp")).String(),
+		BillboardCoordinate:   core.NewCoordinate(core.KindBillboard, pubkey, core.NewDTag(core.KindBillboard, "bb")).String(),
+		PromotionCoordinate:   core.NewCoordinate(core.KindPromotion, pubkey, core.NewDTag(core.KindPromotion, "pr")).String(),
+		AttentionCoordinate:   core.NewCoordinate(core.KindAttention, pubkey, core.NewDTag(core.KindAttention, "at")).String(),
+		MarketplacePubkey:     pubkey,
+		BillboardPubkey:       pubkey,
+		PromotionPubkey:       pubkey,
+		AttentionPubkey:       nsec,
+	}
+	if _, err := CreateMatch(vectorSecretKey, params); !errors.Is(err, nip19.ErrUnexpectedPrefix) {
+		t.Errorf("expected an nsec pubkey to fail with ErrUnexpectedPrefix, got %v", err)
+	}
+
+	params.AttentionPubkey = pubkey
+	params.BillboardCoordinate = nsec
+	if _, err := CreateMatch(vectorSecretKey, params); !errors.Is(err, nip19.ErrUnexpectedPrefix) {
+		t.Errorf("expected an nsec coordinate to fail with ErrUnexpectedPrefix, got %v", err)
+	}
+
+	promotion := PromotionParams{EventID: nsec}
+	if _, err := CreatePromotion(vectorSecretKey, promotion); !errors.Is(err, nip19.ErrUnexpectedPrefix) {
+		t.Errorf("expected an nsec content id to fail with ErrUnexpectedPrefix, got %v", err)
+	}
+}
@@ -1,9 +1,9 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/joinnextblock/attn-protocol/go-core"
 	"github.com/nbd-wtf/go-nostr"
@@ -15,7 +15,7 @@ type PromotionParams struct {
 	Duration int64
 
 	// Bid is the bid amount in satoshis.
-	Bid int64
+	Bid core.Sats
 
 	// EventID is the ID of the content event being promoted.
 	EventID string
@@ -35,6 +35,30 @@ type PromotionParams struct {
 	// BillboardCoordinate is the billboard coordinate (38288:pubkey:id).
 	BillboardCoordinate string
 
+	// VideoCoordinate is the coordinate of the promoted video (34236:pubkey:id).
+	VideoCoordinate string
+
+	// MarketplacePubkey is the marketplace's pubkey.
+	MarketplacePubkey string
+
+	// MarketplaceID is the marketplace ID.
+	MarketplaceID string
+
+	// BillboardPubkey is the billboard's pubkey.
+	BillboardPubkey string
+
+	// BillboardID is the billboard ID.
+	BillboardID string
+
+	// Relays is the list of relay URLs.
+	Relays []string
+
+	// Kind is the kind of the promoted content.
+	Kind int
+
+	// URL is the promotion URL.
+	URL string
+
 	// BlockHeight is the Bitcoin block height.
 	BlockHeight int64
 
@@ -45,18 +69,44 @@ type PromotionParams struct {
 	PromotionPubkey string
 }
 
-// CreatePromotion creates a PROMOTION event (kind 38388).
+// CreatePromotion creates a PROMOTION event (kind 38388) signed with a hex or nsec private key.
 func CreatePromotion(private_key string, params PromotionParams) (*nostr.Event, error) {
+	signer, err := newKeySigner(private_key)
+	if err != nil {
+		return nil, err
+	}
+	return CreatePromotionWithSigner(context.Background(), signer, params)
+}
+
+// CreatePromotionWithSigner creates a PROMOTION event (kind 38388) signed by signer.
+func CreatePromotionWithSigner(ctx context.Context, signer core.Signer, params PromotionParams) (*nostr.Event, error) {
+	// Resolve NIP-19 encoded keys and pointers
+	p := &pointers{}
+	p.contentID(&params.EventID)
+	p.coordinate(&params.MarketplaceCoordinate)
+	p.coordinate(&params.BillboardCoordinate)
+	p.coordinate(&params.VideoCoordinate)
+	p.pubkey(&params.MarketplacePubkey)
+	p.pubkey(&params.BillboardPubkey)
+	p.pubkey(&params.PromotionPubkey)
+	if p.err != nil {
+		return nil, p.err
+	}
+
 	// Build content
 	content := core.PromotionData{
-		Duration:           params.Duration,
-		Bid:                params.Bid,
-		EventID:            params.EventID,
-		CallToAction:       params.CallToAction,
-		CallToActionURL:    params.CallToActionURL,
-		EscrowIDList:       params.EscrowIDList,
-		RefPromotionPubkey: params.PromotionPubkey,
-		RefPromotionID:     params.PromotionID,
+		Duration:             params.Duration,
+		Bid:                  params.Bid,
+		EventID:              params.EventID,
+		CallToAction:         params.CallToAction,
+		CallToActionURL:      params.CallToActionURL,
+		EscrowIDList:         params.EscrowIDList,
+		RefPromotionPubkey:   params.PromotionPubkey,
+		RefPromotionID:       params.PromotionID,
+		RefMarketplacePubkey: params.MarketplacePubkey,
+		RefMarketplaceID:     params.MarketplaceID,
+		RefBillboardPubkey:   params.BillboardPubkey,
+		RefBillboardID:       params.BillboardID,
 	}
 
 	content_json, err := json.Marshal(content)
@@ -68,11 +118,7 @@ func CreatePromotion(private_key string, params PromotionParams) (*nostr.Event,
 	tags := nostr.Tags{}
 
 	// Add d-tag
-	d_tag := params.PromotionID
-	if d_tag == "" {
-		d_tag = fmt.Sprintf("org.attnprotocol:promotion:%d", time.Now().UnixNano())
-	}
-	tags = append(tags, nostr.Tag{"d", d_tag})
+	tags = append(tags, nostr.Tag{"d", buildDTag(core.KindPromotion, params.PromotionID)})
 
 	// Add block height tag
 	tags = append(tags, nostr.Tag{"t", fmt.Sprintf("%d", params.BlockHeight)})
@@ -82,30 +128,41 @@ func CreatePromotion(private_key string, params PromotionParams) (*nostr.Event,
 		tags = append(tags, nostr.Tag{"a", params.MarketplaceCoordinate})
 	}
 
+	// Add video coordinate
+	if params.VideoCoordinate != "" {
+		tags = append(tags, nostr.Tag{"a", params.VideoCoordinate})
+	}
+
 	// Add billboard coordinate
 	if params.BillboardCoordinate != "" {
 		tags = append(tags, nostr.Tag{"a", params.BillboardCoordinate})
 	}
 
-	// Get public key
-	pk, err := nostr.GetPublicKey(private_key)
-	if err != nil {
-		return nil, err
+	// Add pubkey tags
+	if params.MarketplacePubkey != "" {
+		tags = append(tags, nostr.Tag{"p", params.MarketplacePubkey})
+	}
+	if params.BillboardPubkey != "" {
+		tags = append(tags, nostr.Tag{"p", params.BillboardPubkey})
+	}
+	if params.PromotionPubkey != "" {
+		tags = append(tags, nostr.Tag{"p", params.PromotionPubkey})
 	}
 
-	// Create event
-	event := &nostr.Event{
-		PubKey:    pk,
-		CreatedAt: nostr.Timestamp(time.Now().Unix()),
-		Kind:      core.KindPromotion,
-		Tags:      tags,
-		Content:   string(content_json),
+	// Add relay list
+	for _, relay := range params.Relays {
+		tags = append(tags, nostr.Tag{"r", relay})
 	}
 
-	// Sign event
-	if err := event.Sign(private_key); err != nil {
-		return nil, err
+	// Add promoted content kind
+	if params.Kind != 0 {
+		tags = append(tags, nostr.Tag{"k", fmt.Sprintf("%d", params.Kind)})
+	}
+
+	// Add promotion URL
+	if params.URL != "" {
+		tags = append(tags, nostr.Tag{"u", params.URL})
 	}
 
-	return event, nil
+	return signEvent(ctx, signer, core.KindPromotion, tags, string(content_json))
 }
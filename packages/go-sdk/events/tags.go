@@ -0,0 +1,43 @@
+package events
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// buildDTag returns the d tag for a protocol event.
+// A value that is already a valid d tag for the kind is used as-is, a bare
+// identifier is namespaced (org.attnprotocol:<event_type>:<id>), and an empty
+// value gets a timestamp-based identifier.
+func buildDTag(kind int, id string) string {
+	if id == "" {
+		return core.NewDTag(kind, strconv.FormatInt(time.Now().UnixNano(), 10)).String()
+	}
+
+	if parsed, err := core.ParseDTag(id); err == nil && parsed.Validate(kind) == nil {
+		return id
+	}
+
+	return core.NewDTag(kind, id).String()
+}
+
+// signEvent creates an event of the given kind, tags and content stamped with the
+// current time and signs it with signer.
+func signEvent(ctx context.Context, signer core.Signer, kind int, tags nostr.Tags, content string) (*nostr.Event, error) {
+	event := &nostr.Event{
+		CreatedAt: nostr.Timestamp(time.Now().Unix()),
+		Kind:      kind,
+		Tags:      tags,
+		Content:   content,
+	}
+
+	if err := signer.SignEvent(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
@@ -0,0 +1,337 @@
+package events
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/validation"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// vectorsDir holds the golden corpus shared by every implementation.
+const vectorsDir = "../../protocol/test-vectors"
+
+// vectorSecretKey is the published test key that signed the corpus.
+const vectorSecretKey = "0000000000000000000000000000000000000000000000000000000000000001"
+
+type vectorFile struct {
+	Kind    int `json:"kind"`
+	Vectors []struct {
+		Name  string       `json:"name"`
+		Valid bool         `json:"valid"`
+		Event *nostr.Event `json:"event"`
+	} `json:"vectors"`
+}
+
+// vectorBuilders rebuild a golden event from its decoded form with the SDK builders.
+var vectorBuilders = map[int]func(event *nostr.Event) (*nostr.Event, error){
+	core.KindMarketplace: func(event *nostr.Event) (*nostr.Event, error) {
+		marketplace, err := core.DecodeMarketplace(event)
+		if err != nil {
+			return nil, err
+		}
+		return CreateMarketplace(vectorSecretKey, MarketplaceParams{
+			Name:                marketplace.Data.Name,
+			Description:         marketplace.Data.Description,
+			AdminPubkey:         marketplace.Data.AdminPubkey,
+			MinDuration:         marketplace.Data.MinDuration,
+			MaxDuration:         marketplace.Data.MaxDuration,
+			MatchFeeSats:        marketplace.Data.MatchFeeSats,
+			ConfirmationFeeSats: marketplace.Data.ConfirmationFeeSats,
+			MarketplaceID:       marketplace.Data.RefMarketplaceID,
+			MarketplacePubkey:   marketplace.Data.RefMarketplacePubkey,
+			BlockHeight:         marketplace.BlockHeight,
+			RefClockPubkey:      marketplace.Data.RefClockPubkey,
+			RefBlockID:          marketplace.Data.RefBlockID,
+			BlockCoordinate:     marketplace.BlockCoordinate,
+			KindList:            marketplace.Kinds,
+			RelayList:           marketplace.Relays,
+			WebsiteURL:          tagValue(event, "u"),
+		})
+	},
+	core.KindBillboard: func(event *nostr.Event) (*nostr.Event, error) {
+		billboard, err := core.DecodeBillboard(event)
+		if err != nil {
+			return nil, err
+		}
+		params := BillboardParams{
+			Name:                  billboard.Data.Name,
+			Description:           billboard.Data.Description,
+			ConfirmationFeeSats:   billboard.Data.ConfirmationFeeSats,
+			BillboardID:           billboard.Data.RefBillboardID,
+			BillboardPubkey:       billboard.Data.RefBillboardPubkey,
+			MarketplaceCoordinate: billboard.MarketplaceCoordinate,
+			MarketplacePubkey:     billboard.Data.RefMarketplacePubkey,
+			MarketplaceID:         billboard.Data.RefMarketplaceID,
+			Relays:                billboard.Relays,
+			URL:                   billboard.URL,
+			BlockHeight:           billboard.BlockHeight,
+		}
+		if len(billboard.Kinds) > 0 {
+			params.Kind = billboard.Kinds[0]
+		}
+		return CreateBillboard(vectorSecretKey, params)
+	},
+	core.KindPromotion: func(event *nostr.Event) (*nostr.Event, error) {
+		promotion, err := core.DecodePromotion(event)
+		if err != nil {
+			return nil, err
+		}
+		params := PromotionParams{
+			Duration:              promotion.Data.Duration,
+			Bid:                   promotion.Data.Bid,
+			EventID:               promotion.Data.EventID,
+			CallToAction:          promotion.Data.CallToAction,
+			CallToActionURL:       promotion.Data.CallToActionURL,
+			EscrowIDList:          promotion.Data.EscrowIDList,
+			MarketplaceCoordinate: promotion.MarketplaceCoordinate,
+			BillboardCoordinate:   promotion.BillboardCoordinate,
+			VideoCoordinate:       promotion.VideoCoordinate,
+			MarketplacePubkey:     promotion.Data.RefMarketplacePubkey,
+			MarketplaceID:         promotion.Data.RefMarketplaceID,
+			BillboardPubkey:       promotion.Data.RefBillboardPubkey,
+			BillboardID:           promotion.Data.RefBillboardID,
+			Relays:                promotion.Relays,
+			URL:                   promotion.URL,
+			BlockHeight:           promotion.BlockHeight,
+			PromotionID:           promotion.Data.RefPromotionID,
+			PromotionPubkey:       promotion.Data.RefPromotionPubkey,
+		}
+		if len(promotion.Kinds) > 0 {
+			params.Kind = promotion.Kinds[0]
+		}
+		return CreatePromotion(vectorSecretKey, params)
+	},
+	core.KindAttention: func(event *nostr.Event) (*nostr.Event, error) {
+		attention, err := core.DecodeAttention(event)
+		if err != nil {
+			return nil, err
+		}
+		return CreateAttention(vectorSecretKey, AttentionParams{
+			Ask:                           attention.Data.Ask,
+			MinDuration:                   attention.Data.MinDuration,
+			MaxDuration:                   attention.Data.MaxDuration,
+			BlockedPromotionsID:           attention.Data.BlockedPromotionsID,
+			BlockedPromotersID:            attention.Data.BlockedPromotersID,
+			TrustedMarketplacesID:         attention.Data.TrustedMarketplacesID,
+			TrustedBillboardsID:           attention.Data.TrustedBillboardsID,
+			MarketplaceCoordinate:         attention.MarketplaceCoordinate,
+			BlockedPromotionsCoordinate:   attention.BlockedPromotionsCoordinate,
+			BlockedPromotersCoordinate:    attention.BlockedPromotersCoordinate,
+			TrustedMarketplacesCoordinate: attention.TrustedMarketplacesCoordinate,
+			TrustedBillboardsCoordinate:   attention.TrustedBillboardsCoordinate,
+			MarketplacePubkey:             attention.Data.RefMarketplacePubkey,
+			MarketplaceID:                 attention.Data.RefMarketplaceID,
+			Relays:                        attention.Relays,
+			Kinds:                         attention.Kinds,
+			BlockHeight:                   attention.BlockHeight,
+			AttentionID:                   attention.Data.RefAttentionID,
+			AttentionPubkey:               attention.Data.RefAttentionPubkey,
+		})
+	},
+	core.KindMatch: func(event *nostr.Event) (*nostr.Event, error) {
+		match, err := core.DecodeMatch(event)
+		if err != nil {
+			return nil, err
+		}
+		return CreateMatch(vectorSecretKey, MatchParams{
+			MatchID:               match.Data.RefMatchID,
+			BlockHeight:           match.BlockHeight,
+			MarketplaceCoordinate: match.MarketplaceCoordinate,
+			BillboardCoordinate:   match.BillboardCoordinate,
+			PromotionCoordinate:   match.PromotionCoordinate,
+			AttentionCoordinate:   match.AttentionCoordinate,
+			MarketplacePubkey:     match.Data.RefMarketplacePubkey,
+			BillboardPubkey:       match.Data.RefBillboardPubkey,
+			PromotionPubkey:       match.Data.RefPromotionPubkey,
+			AttentionPubkey:       match.Data.RefAttentionPubkey,
+			Relays:                match.Relays,
+			Kinds:                 match.Kinds,
+			MarketplaceID:         match.Data.RefMarketplaceID,
+			BillboardID:           match.Data.RefBillboardID,
+			PromotionID:           match.Data.RefPromotionID,
+			AttentionID:           match.Data.RefAttentionID,
+		})
+	},
+	core.KindBillboardConfirmation: func(event *nostr.Event) (*nostr.Event, error) {
+		confirmation, err := core.DecodeBillboardConfirmation(event)
+		if err != nil {
+			return nil, err
+		}
+		return CreateBillboardConfirmation(vectorSecretKey, BillboardConfirmationParams{
+			ConfirmationRefs: vectorConfirmationRefs(event, confirmation.Header(), confirmation.MatchRefs, confirmation.MatchCoordinate, confirmation.MatchEventID),
+			ConfirmationID:   confirmation.DTag,
+			BlockHeight:      confirmation.BlockHeight,
+		})
+	},
+	core.KindAttentionConfirmation: func(event *nostr.Event) (*nostr.Event, error) {
+		confirmation, err := core.DecodeAttentionConfirmation(event)
+		if err != nil {
+			return nil, err
+		}
+		return CreateAttentionConfirmation(vectorSecretKey, AttentionConfirmationParams{
+			ConfirmationRefs: vectorConfirmationRefs(event, confirmation.Header(), confirmation.MatchRefs, confirmation.MatchCoordinate, confirmation.MatchEventID),
+			ConfirmationID:   confirmation.DTag,
+			BlockHeight:      confirmation.BlockHeight,
+		})
+	},
+	core.KindMarketplaceConfirmation: func(event *nostr.Event) (*nostr.Event, error) {
+		confirmation, err := core.DecodeMarketplaceConfirmation(event)
+		if err != nil {
+			return nil, err
+		}
+		return CreateMarketplaceConfirmation(vectorSecretKey, MarketplaceConfirmationParams{
+			ConfirmationRefs:             vectorConfirmationRefs(event, confirmation.Header(), confirmation.MatchRefs, confirmation.MatchCoordinate, confirmation.MatchEventID),
+			ConfirmationID:               confirmation.DTag,
+			BlockHeight:                  confirmation.BlockHeight,
+			BillboardConfirmationEventID: confirmation.BillboardConfirmationEventID,
+			AttentionConfirmationEventID: confirmation.AttentionConfirmationEventID,
+		})
+	},
+	core.KindAttentionPaymentConfirmation: func(event *nostr.Event) (*nostr.Event, error) {
+		confirmation, err := core.DecodeAttentionPaymentConfirmation(event)
+		if err != nil {
+			return nil, err
+		}
+		return CreateAttentionPaymentConfirmation(vectorSecretKey, AttentionPaymentConfirmationParams{
+			ConfirmationRefs:               vectorConfirmationRefs(event, confirmation.Header(), confirmation.MatchRefs, confirmation.MatchCoordinate, confirmation.MatchEventID),
+			ConfirmationID:                 confirmation.DTag,
+			BlockHeight:                    confirmation.BlockHeight,
+			MarketplaceConfirmationEventID: confirmation.MarketplaceConfirmationEventID,
+			SatsReceived:                   confirmation.Data.SatsReceived,
+			PaymentProof:                   confirmation.Data.PaymentProof,
+		})
+	},
+}
+
+// vectorConfirmationRefs rebuilds the shared confirmation references from a golden event.
+// Content is compared separately, so ids and pubkeys are read back from the tags.
+func vectorConfirmationRefs(event *nostr.Event, header *core.EventHeader, refs core.MatchRefs, match_coordinate string, match_event_id string) ConfirmationRefs {
+	decoded, _ := core.Decode(event)
+	content := reflect.ValueOf(contentData(decoded)).Elem()
+	field := func(name string) string {
+		return content.FieldByName(name).String()
+	}
+
+	// Unmarked 'e' tags are the party events, in builder order
+	var party_event_ids []string
+	for _, tag := range event.Tags {
+		if len(tag) >= 2 && tag[0] == "e" && (len(tag) < 4 || tag[3] == "") {
+			party_event_ids = append(party_event_ids, tag[1])
+		}
+	}
+	party_event_ids = append(party_event_ids, make([]string, 4)...)
+
+	return ConfirmationRefs{
+		MatchEventID:          match_event_id,
+		MatchID:               field("RefMatchID"),
+		MarketplaceCoordinate: refs.MarketplaceCoordinate,
+		BillboardCoordinate:   refs.BillboardCoordinate,
+		PromotionCoordinate:   refs.PromotionCoordinate,
+		AttentionCoordinate:   refs.AttentionCoordinate,
+		MatchCoordinate:       match_coordinate,
+		MarketplacePubkey:     field("RefMarketplacePubkey"),
+		BillboardPubkey:       field("RefBillboardPubkey"),
+		PromotionPubkey:       field("RefPromotionPubkey"),
+		AttentionPubkey:       field("RefAttentionPubkey"),
+		MarketplaceID:         field("RefMarketplaceID"),
+		BillboardID:           field("RefBillboardID"),
+		PromotionID:           field("RefPromotionID"),
+		AttentionID:           field("RefAttentionID"),
+		MarketplaceEventID:    party_event_ids[0],
+		BillboardEventID:      party_event_ids[1],
+		PromotionEventID:      party_event_ids[2],
+		AttentionEventID:      party_event_ids[3],
+		Relays:                header.Relays,
+	}
+}
+
+// TestBuildersReproduceVectors rebuilds every valid golden event the SDK has a builder
+// for and checks the result validates and carries the same tags and content.
+func TestBuildersReproduceVectors(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(vectorsDir, "*.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no test vectors found in %s: %v", vectorsDir, err)
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var file vectorFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+
+		build, ok := vectorBuilders[file.Kind]
+		if !ok {
+			continue
+		}
+
+		for _, vector := range file.Vectors {
+			if !vector.Valid {
+				continue
+			}
+			t.Run(filepath.Base(path)+"/"+vector.Name, func(t *testing.T) {
+				built, err := build(vector.Event)
+				if err != nil {
+					t.Fatalf("builder failed: %v", err)
+				}
+
+				if result := validation.ValidateEvent(built); !result.Valid {
+					t.Errorf("built event is invalid: %s (%s)", result.Message, result.Code)
+				}
+				if want, got := sortedTags(vector.Event.Tags), sortedTags(built.Tags); !reflect.DeepEqual(want, got) {
+					t.Errorf("tags differ:\nwant %v\ngot  %v", want, got)
+				}
+				if !sameContent(t, vector.Event, built) {
+					t.Errorf("content differs:\nwant %s\ngot  %s", vector.Event.Content, built.Content)
+				}
+			})
+		}
+	}
+}
+
+// sortedTags returns the tags in a canonical order so they compare as a multiset.
+func sortedTags(tags nostr.Tags) []string {
+	sorted := make([]string, len(tags))
+	for i, tag := range tags {
+		sorted[i] = strings.Join(tag, "\x00")
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// sameContent compares content after decoding both events into the kind's content type.
+func sameContent(t *testing.T, want *nostr.Event, got *nostr.Event) bool {
+	want_decoded, err := core.Decode(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got_decoded, err := core.Decode(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reflect.DeepEqual(contentData(want_decoded), contentData(got_decoded))
+}
+
+// contentData returns the Data field of a decoded event.
+func contentData(decoded core.DecodedEvent) interface{} {
+	return reflect.ValueOf(decoded).Elem().FieldByName("Data").Interface()
+}
+
+func tagValue(event *nostr.Event, name string) string {
+	for _, tag := range event.Tags {
+		if len(tag) >= 2 && tag[0] == name {
+			return tag[1]
+		}
+	}
+	return ""
+}
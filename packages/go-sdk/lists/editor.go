@@ -0,0 +1,237 @@
+// Package lists maintains a signer's ATTN Protocol NIP-51 lists on relays.
+//
+// An Editor fetches the latest version of a list, changes its entries and publishes
+// a newly signed version that replaces it. Edits are read-modify-write, so two
+// editors changing the same list at the same time can lose one another's change.
+package lists
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/events"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relay"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// Relays queries and publishes events. *relay.Pool implements it.
+//
+// Query must fail, rather than return no events, when no relay answered: edits are
+// read-modify-write, so a list read as empty would be replaced by one holding only
+// the new entries.
+type Relays interface {
+	Query(ctx context.Context, filter nostr.Filter) ([]*nostr.Event, error)
+	Publish(ctx context.Context, event *nostr.Event) (*relay.PublishResults, error)
+}
+
+// List is the current version of one of the signer's lists, with its private
+// entries decrypted.
+type List struct {
+	events.ListParams
+
+	// Event is the published list event, nil if the list has not been published yet.
+	Event *nostr.Event
+}
+
+// Editor edits the lists of the identity behind a signer.
+type Editor struct {
+	signer core.Signer
+	relays Relays
+}
+
+// NewEditor returns an editor signing with signer and reading and publishing
+// through relays, usually a connected *relay.Pool.
+func NewEditor(signer core.Signer, relays Relays) *Editor {
+	return &Editor{signer: signer, relays: relays}
+}
+
+// Coordinate returns the coordinate of the signer's list (30000:pubkey:list_type),
+// for the list 'a' tags of ATTENTION events.
+func (e *Editor) Coordinate(ctx context.Context, list_type string) (string, error) {
+	if !core.IsListType(list_type) {
+		return "", fmt.Errorf("%w: %s", core.ErrUnknownListType, list_type)
+	}
+	pubkey, err := e.signer.GetPublicKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	return events.ListCoordinate(pubkey, list_type), nil
+}
+
+// Get returns the latest version of the signer's list. A list that has not been
+// published yet is returned empty, with a nil Event. A failed query is returned
+// as an error, never as an unpublished list.
+func (e *Editor) Get(ctx context.Context, list_type string) (*List, error) {
+	if !core.IsListType(list_type) {
+		return nil, fmt.Errorf("%w: %s", core.ErrUnknownListType, list_type)
+	}
+	pubkey, err := e.signer.GetPublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := e.relays.Query(ctx, nostr.Filter{
+		Kinds:   []int{core.KindFollowSet},
+		Authors: []string{pubkey},
+		Tags:    nostr.TagMap{"d": []string{list_type}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Versions with the same created_at are resolved by the lowest id, as NIP-01 specifies
+	var newest *nostr.Event
+	for _, event := range found {
+		if event.PubKey != pubkey || event.Tags.GetD() != list_type {
+			continue
+		}
+		if newest == nil || event.CreatedAt > newest.CreatedAt ||
+			(event.CreatedAt == newest.CreatedAt && event.ID < newest.ID) {
+			newest = event
+		}
+	}
+	if newest == nil {
+		return &List{ListParams: events.ListParams{Type: list_type}}, nil
+	}
+
+	params, err := events.ReadList(ctx, e.signer, newest)
+	if err != nil {
+		return nil, err
+	}
+	return &List{ListParams: params, Event: newest}, nil
+}
+
+// Add adds public entries to the signer's list and publishes it. Values may be NIP-19
+// encoded (naddr, nevent, npub). Entries already on the list, publicly or privately,
+// are left as they are; if nothing changes the list is returned without publishing.
+func (e *Editor) Add(ctx context.Context, list_type string, entries ...core.ListEntry) (*List, error) {
+	return e.add(ctx, list_type, entries, false)
+}
+
+// AddPrivate adds entries to the signer's list as NIP-44 encrypted private entries
+// and publishes it, as Add does. The signer must be able to encrypt.
+func (e *Editor) AddPrivate(ctx context.Context, list_type string, entries ...core.ListEntry) (*List, error) {
+	return e.add(ctx, list_type, entries, true)
+}
+
+// Remove removes entries from the signer's list, public or private, and publishes it.
+// If none of the entries is on the list it is returned without publishing.
+func (e *Editor) Remove(ctx context.Context, list_type string, entries ...core.ListEntry) (*List, error) {
+	resolved, err := resolve(list_type, entries)
+	if err != nil {
+		return nil, err
+	}
+	removed := make(map[core.ListEntry]bool, len(resolved))
+	for _, entry := range resolved {
+		removed[entry] = true
+	}
+
+	list, err := e.Get(ctx, list_type)
+	if err != nil {
+		return nil, err
+	}
+
+	var public_removed, private_removed bool
+	list.Entries, public_removed = without(list.Entries, removed)
+	list.PrivateEntries, private_removed = without(list.PrivateEntries, removed)
+	if !public_removed && !private_removed {
+		return list, nil
+	}
+	return e.publish(ctx, list)
+}
+
+// Save publishes params as the new version of the signer's list, replacing its
+// entries and description.
+func (e *Editor) Save(ctx context.Context, params events.ListParams) (*List, error) {
+	list, err := e.Get(ctx, params.Type)
+	if err != nil {
+		return nil, err
+	}
+	list.ListParams = params
+	return e.publish(ctx, list)
+}
+
+// add adds entries to the public or private entries of the list and publishes it.
+func (e *Editor) add(ctx context.Context, list_type string, entries []core.ListEntry, private bool) (*List, error) {
+	added, err := resolve(list_type, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := e.Get(ctx, list_type)
+	if err != nil {
+		return nil, err
+	}
+
+	present := make(map[core.ListEntry]bool)
+	for _, entry := range list.Entries {
+		present[entry] = true
+	}
+	for _, entry := range list.PrivateEntries {
+		present[entry] = true
+	}
+
+	var changed bool
+	for _, entry := range added {
+		if present[entry] {
+			continue
+		}
+		present[entry] = true
+		changed = true
+		if private {
+			list.PrivateEntries = append(list.PrivateEntries, entry)
+		} else {
+			list.Entries = append(list.Entries, entry)
+		}
+	}
+	if !changed {
+		return list, nil
+	}
+	return e.publish(ctx, list)
+}
+
+// publish signs the list as a new version and publishes it. The new version is
+// dated after the one it replaces so relays keep it even within the same second.
+func (e *Editor) publish(ctx context.Context, list *List) (*List, error) {
+	event, err := events.CreateListWithSigner(ctx, e.signer, list.ListParams)
+	if err != nil {
+		return nil, err
+	}
+	if list.Event != nil && event.CreatedAt <= list.Event.CreatedAt {
+		event.CreatedAt = list.Event.CreatedAt + 1
+		if err := e.signer.SignEvent(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := e.relays.Publish(ctx, event); err != nil {
+		return nil, err
+	}
+	list.Event = event
+	return list, nil
+}
+
+// resolve resolves entries for the list type, failing on the first invalid one.
+func resolve(list_type string, entries []core.ListEntry) ([]core.ListEntry, error) {
+	resolved := make([]core.ListEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry, err := events.ResolveListEntry(list_type, entry)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, entry)
+	}
+	return resolved, nil
+}
+
+// without returns entries minus the removed ones and whether any was removed.
+func without(entries []core.ListEntry, removed map[core.ListEntry]bool) ([]core.ListEntry, bool) {
+	var kept []core.ListEntry
+	for _, entry := range entries {
+		if !removed[entry] {
+			kept = append(kept, entry)
+		}
+	}
+	return kept, len(kept) != len(entries)
+}
@@ -0,0 +1,199 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/events"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relay"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relaytest"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// newEditor returns an editor for a fresh key on the server.
+func newEditor(t *testing.T, server *relaytest.Server, private_key string) *Editor {
+	t.Helper()
+	pool, err := relay.NewPool([]string{server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	if err := pool.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	signer, err := core.NewKeySigner(private_key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewEditor(signer, pool)
+}
+
+func TestEditorAddAndRemove(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+	private_key := strings.Repeat("01", 32)
+	editor := newEditor(t, server, private_key)
+
+	promotion := core.ListEntry{Tag: "a", Value: "38388:" + strings.Repeat("ab", 32) + ":org.attnprotocol:promotion:p1"}
+	public_id := core.ListEntry{Tag: "e", Value: strings.Repeat("cd", 32)}
+	private_id := core.ListEntry{Tag: "e", Value: strings.Repeat("ef", 32)}
+
+	list, err := editor.Get(ctx, core.NIP51BlockedPromotions)
+	if err != nil || list.Event != nil || len(list.Entries) != 0 {
+		t.Fatalf("expected an empty unpublished list, got %+v (%v)", list, err)
+	}
+
+	if _, err := editor.Add(ctx, core.NIP51BlockedPromotions, promotion, public_id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := editor.AddPrivate(ctx, core.NIP51BlockedPromotions, private_id, promotion); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh editor for the same key sees every entry
+	list, err = newEditor(t, server, private_key).Get(ctx, core.NIP51BlockedPromotions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Entries) != 2 || len(list.PrivateEntries) != 1 || list.PrivateEntries[0] != private_id {
+		t.Fatalf("expected two public entries and one private entry, got %+v", list.ListParams)
+	}
+	parsed, err := core.ParseBlockedPromotionsList(list.Event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Blocks("", public_id.Value) || parsed.Blocks("", private_id.Value) {
+		t.Error("expected only public entries in the published tags")
+	}
+
+	published := len(server.Events())
+	if _, err := editor.Add(ctx, core.NIP51BlockedPromotions, public_id); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.Events()) != published {
+		t.Error("expected adding an existing entry not to publish")
+	}
+
+	if _, err := editor.Remove(ctx, core.NIP51BlockedPromotions, public_id, private_id); err != nil {
+		t.Fatal(err)
+	}
+	list, err = editor.Get(ctx, core.NIP51BlockedPromotions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Entries) != 1 || list.Entries[0] != promotion || len(list.PrivateEntries) != 0 {
+		t.Errorf("expected only the promotion coordinate left, got %+v", list.ListParams)
+	}
+
+	coordinate, err := editor.Coordinate(ctx, core.NIP51BlockedPromotions)
+	if err != nil || coordinate != events.ListCoordinate(list.Event.PubKey, core.NIP51BlockedPromotions) {
+		t.Errorf("unexpected list coordinate %s (%v)", coordinate, err)
+	}
+}
+
+func TestEditorKeepsForeignTags(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+	private_key := strings.Repeat("01", 32)
+	signer, _ := core.NewKeySigner(private_key)
+
+	// Another NIP-51 client published the list with a title and an entry we do not parse
+	existing := &nostr.Event{
+		Kind:      core.KindFollowSet,
+		CreatedAt: nostr.Now() - 10,
+		Tags:      nostr.Tags{{"d", core.NIP51BlockedPromoters}, {"title", "Spammers"}, {"p", "not-a-pubkey"}},
+		Content:   `{"description":""}`,
+	}
+	if err := signer.SignEvent(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+	editor := newEditor(t, server, private_key)
+	if _, err := editor.relays.Publish(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := editor.Add(ctx, core.NIP51BlockedPromoters, core.ListEntry{Tag: "p", Value: strings.Repeat("ab", 32)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Event.Tags.FindWithValue("title", "Spammers") == nil || list.Event.Tags.FindWithValue("p", "not-a-pubkey") == nil {
+		t.Errorf("expected the edit to keep the foreign tags, got %v", list.Event.Tags)
+	}
+}
+
+func TestEditorRejectsInvalidEntries(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	editor := newEditor(t, server, strings.Repeat("01", 32))
+
+	if _, err := editor.Add(context.Background(), "org.attnprotocol:unknown"); !errors.Is(err, core.ErrUnknownListType) {
+		t.Errorf("expected ErrUnknownListType, got %v", err)
+	}
+	if _, err := editor.Add(context.Background(), core.NIP51BlockedPromoters, core.ListEntry{Tag: "p", Value: "not-a-pubkey"}); err == nil {
+		t.Error("expected an error for a malformed pubkey")
+	}
+	if len(server.Events()) != 0 {
+		t.Error("expected nothing to be published")
+	}
+}
+
+// failingRelays fails every query and records publishes.
+type failingRelays struct {
+	published []*nostr.Event
+}
+
+func (r *failingRelays) Query(ctx context.Context, filter nostr.Filter) ([]*nostr.Event, error) {
+	return nil, relay.ErrQueryFailed
+}
+
+func (r *failingRelays) Publish(ctx context.Context, event *nostr.Event) (*relay.PublishResults, error) {
+	r.published = append(r.published, event)
+	return &relay.PublishResults{EventID: event.ID, SuccessCount: 1}, nil
+}
+
+func TestEditorDoesNotEditUnreadList(t *testing.T) {
+	ctx := context.Background()
+	signer, _ := core.NewKeySigner(strings.Repeat("01", 32))
+	relays := &failingRelays{}
+	editor := NewEditor(signer, relays)
+
+	entry := core.ListEntry{Tag: "p", Value: strings.Repeat("ab", 32)}
+	if _, err := editor.Add(ctx, core.NIP51BlockedPromoters, entry); !errors.Is(err, relay.ErrQueryFailed) {
+		t.Errorf("expected Add to fail with ErrQueryFailed, got %v", err)
+	}
+	if _, err := editor.Remove(ctx, core.NIP51BlockedPromoters, entry); !errors.Is(err, relay.ErrQueryFailed) {
+		t.Errorf("expected Remove to fail with ErrQueryFailed, got %v", err)
+	}
+	if len(relays.published) != 0 {
+		t.Errorf("expected nothing to be published, got %d events", len(relays.published))
+	}
+}
+
+func TestEditorFailsWhenNoRelayAnswers(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+	private_key := strings.Repeat("01", 32)
+
+	existing := core.ListEntry{Tag: "p", Value: strings.Repeat("ab", 32)}
+	if _, err := newEditor(t, server, private_key).Add(ctx, core.NIP51BlockedPromoters, existing); err != nil {
+		t.Fatal(err)
+	}
+	published := len(server.Events())
+
+	// The relay still takes connections but drops every query
+	server.HangUpOnSubscribe()
+	editor := newEditor(t, server, private_key)
+	added := core.ListEntry{Tag: "p", Value: strings.Repeat("cd", 32)}
+	if _, err := editor.Add(ctx, core.NIP51BlockedPromoters, added); !errors.Is(err, relay.ErrQueryFailed) {
+		t.Errorf("expected ErrQueryFailed, got %v", err)
+	}
+	if len(server.Events()) != published {
+		t.Error("expected the existing list not to be replaced")
+	}
+}
@@ -0,0 +1,378 @@
+// Package nip19 encodes and decodes NIP-19 bech32 entities: nsec and npub keys,
+// naddr coordinates and nevent pointers.
+//
+// The Decode* helpers accept either form of a value, so configuration and
+// builder parameters can take hex keys and raw coordinates as well as their
+// shareable bech32 encodings. Any other NIP-19 entity is rejected, so an nsec
+// can never pass for a pubkey:
+//
+//	pubkey, err := nip19.DecodePublicKey("npub1...")   // or a 64-character hex pubkey
+//	coordinate, err := nip19.DecodeCoordinate("naddr1...") // or "38188:<pubkey>:<d>"
+//
+//	link, err := nip19.EncodeEventAddress(promotion_event, "wss://relay.example.com")
+//
+// The bech32 layer is the btcutil codec go-nostr's nip19 package uses, and the
+// encodings match go-nostr's. TLV entries are read here rather than through
+// go-nostr's decoder, which panics on a short naddr kind entry and accepts
+// truncated entries.
+package nip19
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// NIP-19 prefixes.
+const (
+	PrefixPrivateKey = "nsec"
+	PrefixPublicKey  = "npub"
+	PrefixAddress    = "naddr"
+	PrefixEvent      = "nevent"
+)
+
+// entityPrefixes are the prefixes of every NIP-19 bech32 entity, including the
+// note and nprofile entities this package does not decode.
+var entityPrefixes = []string{PrefixPrivateKey, PrefixPublicKey, PrefixAddress, PrefixEvent, "note", "nprofile"}
+
+// maxLength bounds encoded strings. NIP-19 lifts BIP-173's 90 character limit so
+// TLV entities can carry relay hints.
+const maxLength = 5000
+
+// TLV types used by naddr and nevent.
+const (
+	tlvSpecial = 0
+	tlvRelay   = 1
+	tlvAuthor  = 2
+	tlvKind    = 3
+)
+
+var (
+	// ErrInvalidEntity is returned when a NIP-19 string cannot be decoded, or a value
+	// is not in the hex or coordinate form the entity would decode to.
+	ErrInvalidEntity = errors.New("invalid NIP-19 entity")
+
+	// ErrUnexpectedPrefix is returned when a NIP-19 string has another prefix than expected.
+	ErrUnexpectedPrefix = errors.New("unexpected NIP-19 prefix")
+)
+
+// AddressPointer is the decoded form of an naddr: a coordinate plus relay hints.
+type AddressPointer struct {
+	Kind       int
+	Pubkey     string
+	Identifier string
+	Relays     []string
+}
+
+// Coordinate returns the pointer's <kind>:<pubkey>:<d_tag> coordinate.
+func (a AddressPointer) Coordinate() core.Coordinate {
+	return core.Coordinate{Kind: a.Kind, Pubkey: a.Pubkey, DTag: a.Identifier}
+}
+
+// EventPointer is the decoded form of an nevent: an event id plus optional hints.
+type EventPointer struct {
+	ID     string
+	Relays []string
+	Author string
+	Kind   int
+}
+
+// EncodePrivateKey encodes a hex private key as an nsec.
+func EncodePrivateKey(private_key string) (string, error) {
+	return encodeKey(PrefixPrivateKey, private_key)
+}
+
+// EncodePublicKey encodes a hex pubkey as an npub.
+func EncodePublicKey(pubkey string) (string, error) {
+	return encodeKey(PrefixPublicKey, pubkey)
+}
+
+// encodeKey encodes a 32-byte hex value under the prefix.
+func encodeKey(prefix string, value string) (string, error) {
+	if !nostr.IsValid32ByteHex(value) {
+		return "", fmt.Errorf("%w: %s value must be 64-character hex", ErrInvalidEntity, prefix)
+	}
+	data, _ := hex.DecodeString(value)
+	return encodeBech32(prefix, data)
+}
+
+// EncodeAddress encodes an address pointer as an naddr.
+func EncodeAddress(pointer AddressPointer) (string, error) {
+	if !nostr.IsValid32ByteHex(pointer.Pubkey) {
+		return "", fmt.Errorf("%w: naddr pubkey must be 64-character hex", ErrInvalidEntity)
+	}
+
+	var tlv []byte
+	tlv, err := appendTLV(tlv, tlvSpecial, []byte(pointer.Identifier))
+	if err != nil {
+		return "", err
+	}
+	for _, relay := range pointer.Relays {
+		if tlv, err = appendTLV(tlv, tlvRelay, []byte(relay)); err != nil {
+			return "", err
+		}
+	}
+	author, _ := hex.DecodeString(pointer.Pubkey)
+	tlv, _ = appendTLV(tlv, tlvAuthor, author)
+	tlv, _ = appendTLV(tlv, tlvKind, binary.BigEndian.AppendUint32(nil, uint32(pointer.Kind)))
+
+	return encodeBech32(PrefixAddress, tlv)
+}
+
+// EncodeEvent encodes an event pointer as an nevent. Author and Kind are optional.
+func EncodeEvent(pointer EventPointer) (string, error) {
+	if !nostr.IsValid32ByteHex(pointer.ID) {
+		return "", fmt.Errorf("%w: nevent id must be 64-character hex", ErrInvalidEntity)
+	}
+	if pointer.Author != "" && !nostr.IsValid32ByteHex(pointer.Author) {
+		return "", fmt.Errorf("%w: nevent author must be 64-character hex", ErrInvalidEntity)
+	}
+
+	id, _ := hex.DecodeString(pointer.ID)
+	tlv, _ := appendTLV(nil, tlvSpecial, id)
+	for _, relay := range pointer.Relays {
+		var err error
+		if tlv, err = appendTLV(tlv, tlvRelay, []byte(relay)); err != nil {
+			return "", err
+		}
+	}
+	if pointer.Author != "" {
+		author, _ := hex.DecodeString(pointer.Author)
+		tlv, _ = appendTLV(tlv, tlvAuthor, author)
+	}
+	if pointer.Kind != 0 {
+		tlv, _ = appendTLV(tlv, tlvKind, binary.BigEndian.AppendUint32(nil, uint32(pointer.Kind)))
+	}
+
+	return encodeBech32(PrefixEvent, tlv)
+}
+
+// EncodeCoordinate encodes a <kind>:<pubkey>:<d_tag> coordinate as an naddr with relay hints.
+func EncodeCoordinate(coordinate string, relays ...string) (string, error) {
+	parsed, err := core.ParseCoordinate(coordinate)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidEntity, err)
+	}
+	return EncodeAddress(AddressPointer{Kind: parsed.Kind, Pubkey: parsed.Pubkey, Identifier: parsed.DTag, Relays: relays})
+}
+
+// EncodeEventAddress encodes the naddr of an addressable event, such as a promotion
+// or marketplace, for sharing in logs and links.
+func EncodeEventAddress(event *nostr.Event, relays ...string) (string, error) {
+	if event == nil {
+		return "", core.ErrNilEvent
+	}
+	d_tag := event.Tags.GetD()
+	if d_tag == "" {
+		return "", core.ErrMissingDTag
+	}
+	return EncodeAddress(AddressPointer{Kind: event.Kind, Pubkey: event.PubKey, Identifier: d_tag, Relays: relays})
+}
+
+// appendTLV appends one type-length-value entry.
+func appendTLV(tlv []byte, typ byte, value []byte) ([]byte, error) {
+	if len(value) > 255 {
+		return nil, fmt.Errorf("%w: TLV value longer than 255 bytes", ErrInvalidEntity)
+	}
+	tlv = append(tlv, typ, byte(len(value)))
+	return append(tlv, value...), nil
+}
+
+// Decode decodes a NIP-19 string into its prefix and value: a hex string for nsec
+// and npub, an AddressPointer for naddr and an EventPointer for nevent.
+func Decode(value string) (string, interface{}, error) {
+	prefix, data, err := decodeBech32(value)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidEntity, err)
+	}
+
+	switch prefix {
+	case PrefixPrivateKey, PrefixPublicKey:
+		if len(data) != 32 {
+			return "", nil, fmt.Errorf("%w: %s must hold 32 bytes", ErrInvalidEntity, prefix)
+		}
+		return prefix, hex.EncodeToString(data), nil
+
+	case PrefixAddress:
+		var pointer AddressPointer
+		var has_identifier, has_author, has_kind bool
+		err := readTLV(data, func(typ byte, value []byte) error {
+			switch typ {
+			case tlvSpecial:
+				pointer.Identifier, has_identifier = string(value), true
+			case tlvRelay:
+				pointer.Relays = append(pointer.Relays, string(value))
+			case tlvAuthor:
+				if len(value) != 32 {
+					return fmt.Errorf("naddr author must hold 32 bytes")
+				}
+				pointer.Pubkey, has_author = hex.EncodeToString(value), true
+			case tlvKind:
+				if len(value) != 4 {
+					return fmt.Errorf("naddr kind must hold 4 bytes")
+				}
+				pointer.Kind, has_kind = int(binary.BigEndian.Uint32(value)), true
+			}
+			return nil
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %v", ErrInvalidEntity, err)
+		}
+		if !has_identifier || !has_author || !has_kind {
+			return "", nil, fmt.Errorf("%w: naddr needs an identifier, author and kind", ErrInvalidEntity)
+		}
+		return prefix, pointer, nil
+
+	case PrefixEvent:
+		var pointer EventPointer
+		err := readTLV(data, func(typ byte, value []byte) error {
+			switch typ {
+			case tlvSpecial:
+				if len(value) != 32 {
+					return fmt.Errorf("nevent id must hold 32 bytes")
+				}
+				pointer.ID = hex.EncodeToString(value)
+			case tlvRelay:
+				pointer.Relays = append(pointer.Relays, string(value))
+			case tlvAuthor:
+				if len(value) != 32 {
+					return fmt.Errorf("nevent author must hold 32 bytes")
+				}
+				pointer.Author = hex.EncodeToString(value)
+			case tlvKind:
+				if len(value) != 4 {
+					return fmt.Errorf("nevent kind must hold 4 bytes")
+				}
+				pointer.Kind = int(binary.BigEndian.Uint32(value))
+			}
+			return nil
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %v", ErrInvalidEntity, err)
+		}
+		if pointer.ID == "" {
+			return "", nil, fmt.Errorf("%w: nevent needs an event id", ErrInvalidEntity)
+		}
+		return prefix, pointer, nil
+	}
+
+	return "", nil, fmt.Errorf("%w: %s", ErrUnexpectedPrefix, prefix)
+}
+
+// readTLV walks type-length-value entries, ignoring unknown types.
+func readTLV(data []byte, visit func(typ byte, value []byte) error) error {
+	for len(data) > 0 {
+		if len(data) < 2 || len(data) < 2+int(data[1]) {
+			return fmt.Errorf("truncated TLV entry")
+		}
+		if err := visit(data[0], data[2:2+int(data[1])]); err != nil {
+			return err
+		}
+		data = data[2+int(data[1]):]
+	}
+	return nil
+}
+
+// DecodePrivateKey returns the hex private key for an nsec. A 64-character hex
+// key is returned unchanged; other NIP-19 entities fail with ErrUnexpectedPrefix.
+func DecodePrivateKey(value string) (string, error) {
+	return decodeAs(value, PrefixPrivateKey, nostr.IsValid32ByteHex, func(data interface{}) string { return data.(string) })
+}
+
+// DecodePublicKey returns the hex pubkey for an npub. A 64-character hex pubkey
+// is returned unchanged; other NIP-19 entities, an nsec among them, fail with
+// ErrUnexpectedPrefix.
+func DecodePublicKey(value string) (string, error) {
+	return decodeAs(value, PrefixPublicKey, nostr.IsValid32ByteHex, func(data interface{}) string { return data.(string) })
+}
+
+// DecodeCoordinate returns the <kind>:<pubkey>:<d_tag> coordinate for an naddr.
+// A raw coordinate is returned unchanged; other NIP-19 entities fail with
+// ErrUnexpectedPrefix.
+func DecodeCoordinate(value string) (string, error) {
+	return decodeAs(value, PrefixAddress, isCoordinate, func(data interface{}) string {
+		return data.(AddressPointer).Coordinate().String()
+	})
+}
+
+// DecodeEventID returns the hex event id for an nevent. A 64-character hex id is
+// returned unchanged; other NIP-19 entities fail with ErrUnexpectedPrefix.
+func DecodeEventID(value string) (string, error) {
+	return decodeAs(value, PrefixEvent, nostr.IsValid32ByteHex, func(data interface{}) string { return data.(EventPointer).ID })
+}
+
+// decodeAs decodes value if it is a NIP-19 entity with the prefix, converts the
+// result with convert and checks the final form with valid.
+func decodeAs(value string, prefix string, valid func(string) bool, convert func(data interface{}) string) (string, error) {
+	if got, ok := prefixOf(value); ok {
+		if got != prefix {
+			return "", fmt.Errorf("%w: expected %s, got %s", ErrUnexpectedPrefix, prefix, got)
+		}
+		_, data, err := Decode(value)
+		if err != nil {
+			return "", err
+		}
+		value = convert(data)
+	}
+	if !valid(value) {
+		return "", fmt.Errorf("%w: %q is neither an %s nor its decoded form", ErrInvalidEntity, value, prefix)
+	}
+	return value, nil
+}
+
+// IsEntity reports whether value starts like a NIP-19 bech32 entity (nsec1, npub1,
+// naddr1, nevent1, note1 or nprofile1), whether or not it decodes.
+func IsEntity(value string) bool {
+	_, ok := prefixOf(value)
+	return ok
+}
+
+// prefixOf returns the NIP-19 prefix value starts with, if any.
+func prefixOf(value string) (string, bool) {
+	lower := strings.ToLower(value)
+	for _, prefix := range entityPrefixes {
+		if strings.HasPrefix(lower, prefix+"1") {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// isCoordinate reports whether value is a <kind>:<pubkey>:<d_tag> coordinate with
+// a hex pubkey.
+func isCoordinate(value string) bool {
+	coordinate, err := core.ParseCoordinate(value)
+	return err == nil && nostr.IsValid32ByteHex(coordinate.Pubkey)
+}
+
+// encodeBech32 encodes bytes under the prefix.
+func encodeBech32(prefix string, data []byte) (string, error) {
+	values, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode(prefix, values)
+}
+
+// decodeBech32 decodes a bech32 string into its lowercase prefix and bytes,
+// verifying the checksum.
+func decodeBech32(value string) (string, []byte, error) {
+	if len(value) > maxLength {
+		return "", nil, fmt.Errorf("bech32 string too long")
+	}
+	prefix, values, err := bech32.DecodeNoLimit(value)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := bech32.ConvertBits(values, 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return prefix, data, nil
+}
@@ -0,0 +1,201 @@
+package nip19
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+	gonip19 "github.com/nbd-wtf/go-nostr/nip19"
+)
+
+const vectorKey = "3bf0c63fcb93463407af97a5e5ee64fa883d107ef9e558472c4eb9aaaefa459d"
+
+func TestEncodeKeys(t *testing.T) {
+	npub, err := EncodePublicKey(vectorKey)
+	if err != nil || npub != "npub180cvv07tjdrrgpa0j7j7tmnyl2yr6yr7l8j4s3evf6u64th6gkwsyjh6w6" {
+		t.Errorf("unexpected npub: %s %v", npub, err)
+	}
+	nsec, err := EncodePrivateKey(vectorKey)
+	if err != nil || nsec != "nsec180cvv07tjdrrgpa0j7j7tmnyl2yr6yr7l8j4s3evf6u64th6gkwsgyumg0" {
+		t.Errorf("unexpected nsec: %s %v", nsec, err)
+	}
+
+	if got, err := DecodePublicKey(npub); err != nil || got != vectorKey {
+		t.Errorf("DecodePublicKey(%s) = %s, %v", npub, got, err)
+	}
+	if got, err := DecodePrivateKey(strings.ToUpper(nsec)); err != nil || got != vectorKey {
+		t.Errorf("DecodePrivateKey(%s) = %s, %v", nsec, got, err)
+	}
+	if got, err := DecodePublicKey(vectorKey); err != nil || got != vectorKey {
+		t.Errorf("expected hex to pass through, got %s %v", got, err)
+	}
+
+	if _, err := EncodePublicKey("abc"); !errors.Is(err, ErrInvalidEntity) {
+		t.Errorf("expected ErrInvalidEntity, got %v", err)
+	}
+}
+
+func TestEncodeAddress(t *testing.T) {
+	naddr, err := EncodeAddress(AddressPointer{
+		Kind:       30023,
+		Pubkey:     vectorKey,
+		Identifier: "banana",
+		Relays:     []string{"wss://relay.nostr.example.mydomain.example.com", "wss://nostr.banana.com"},
+	})
+	want := "naddr1qqrxyctwv9hxzqfwwaehxw309aex2mrp0yhxummnw3ezuetcv9khqmr99ekhjer0d4skjm3wv4uxzmtsd3jjucm0d5q3vamnwvaz7tmwdaehgu3wvfskuctwvyhxxmmdqgsrhuxx8l9ex335q7he0f09aej04zpazpl0ne2cgukyawd24mayt8grqsqqqa28a3lkds"
+	if err != nil || naddr != want {
+		t.Fatalf("unexpected naddr: %s %v", naddr, err)
+	}
+
+	prefix, data, err := Decode(naddr)
+	if err != nil || prefix != PrefixAddress {
+		t.Fatalf("unexpected decode: %s %v", prefix, err)
+	}
+	pointer := data.(AddressPointer)
+	if pointer.Kind != 30023 || pointer.Pubkey != vectorKey || pointer.Identifier != "banana" || len(pointer.Relays) != 2 {
+		t.Errorf("unexpected pointer: %+v", pointer)
+	}
+
+	coordinate, err := DecodeCoordinate("naddr1qq98yetxv4ex2mnrv4esygrl54h466tz4v0re4pyuavvxqptsejl0vxcmnhfl60z3rth2xkpjspsgqqqw4rsf34vl5")
+	if err != nil || coordinate != "30023:7fa56f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751ac194:references" {
+		t.Errorf("unexpected coordinate: %s %v", coordinate, err)
+	}
+}
+
+func TestEncodeEventAddress(t *testing.T) {
+	marketplace := core.NewCoordinate(core.KindMarketplace, vectorKey, core.NewDTag(core.KindMarketplace, "m1")).String()
+	event := &nostr.Event{Kind: core.KindMarketplace, PubKey: vectorKey, Tags: nostr.Tags{{"d", "org.attnprotocol:marketplace:m1"}}}
+
+	naddr, err := EncodeEventAddress(event, "wss://relay.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from_coordinate, _ := EncodeCoordinate(marketplace, "wss://relay.example.com"); from_coordinate != naddr {
+		t.Errorf("expected EncodeCoordinate to match EncodeEventAddress, got %s and %s", from_coordinate, naddr)
+	}
+	if got, err := DecodeCoordinate(naddr); err != nil || got != marketplace {
+		t.Errorf("expected %s, got %s %v", marketplace, got, err)
+	}
+	if got, err := DecodeCoordinate(marketplace); err != nil || got != marketplace {
+		t.Errorf("expected raw coordinate to pass through, got %s %v", got, err)
+	}
+
+	if _, err := EncodeEventAddress(&nostr.Event{Kind: core.KindMarketplace, PubKey: vectorKey}); !errors.Is(err, core.ErrMissingDTag) {
+		t.Errorf("expected ErrMissingDTag, got %v", err)
+	}
+}
+
+func TestEncodeEvent(t *testing.T) {
+	nevent, err := EncodeEvent(EventPointer{
+		ID:     "45326f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751ac194",
+		Relays: []string{"wss://banana.com"},
+		Author: "7fa56f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751abb88",
+	})
+	want := "nevent1qqsy2vn0t45k92c78n2zfe6ccvqzhpn977cd3h8wnl579zxhw5dvr9qpzpmhxue69uhkyctwv9hxztnrdaksygrl54h466tz4v0re4pyuavvxqptsejl0vxcmnhfl60z3rth2x4m3q04ndyp"
+	if err != nil || nevent != want {
+		t.Fatalf("unexpected nevent: %s %v", nevent, err)
+	}
+
+	if id, err := DecodeEventID(nevent); err != nil || id != "45326f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751ac194" {
+		t.Errorf("unexpected event id: %s %v", id, err)
+	}
+
+	with_kind, _ := EncodeEvent(EventPointer{ID: strings.Repeat("01", 32), Kind: core.KindMatch})
+	_, data, err := Decode(with_kind)
+	if err != nil || data.(EventPointer).Kind != core.KindMatch {
+		t.Errorf("expected kind to round trip, got %+v %v", data, err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"npub180cvv07tjdrrgpa0j7j7tmnyl2yr6yr7l8j4s3evf6u64th6gkwsyjh6w7",
+		"npub180cvv07tjdrrgpa0j7j7tmnyl2yr6yr7l8j4s3evf6u64th6gkwsyjh6wB",
+		"naddr1qqrxyctwv9hxzc2gqvt",
+		"nevent1qqqqzqxg9k",
+	} {
+		if _, _, err := Decode(value); err == nil {
+			t.Errorf("Decode(%q) should fail", value)
+		}
+	}
+
+	note, _ := encodeBech32("note", make([]byte, 32))
+	if _, _, err := Decode(note); !errors.Is(err, ErrUnexpectedPrefix) {
+		t.Errorf("expected ErrUnexpectedPrefix, got %v", err)
+	}
+	if _, err := DecodeCoordinate("naddr1qqrxyctwv9hxzc2gqvt"); !errors.Is(err, ErrInvalidEntity) {
+		t.Errorf("expected ErrInvalidEntity, got %v", err)
+	}
+}
+
+func TestDecodeRejectsOtherEntities(t *testing.T) {
+	nsec, _ := EncodePrivateKey(vectorKey)
+	npub, _ := EncodePublicKey(vectorKey)
+	naddr, _ := EncodeAddress(AddressPointer{Kind: core.KindMarketplace, Pubkey: vectorKey, Identifier: "m1"})
+	nevent, _ := EncodeEvent(EventPointer{ID: vectorKey})
+	note, _ := encodeBech32("note", make([]byte, 32))
+
+	decoders := map[string]func(string) (string, error){
+		PrefixPrivateKey: DecodePrivateKey,
+		PrefixPublicKey:  DecodePublicKey,
+		PrefixAddress:    DecodeCoordinate,
+		PrefixEvent:      DecodeEventID,
+	}
+	for prefix, decode := range decoders {
+		for _, value := range []string{nsec, npub, naddr, nevent, note, strings.ToUpper(nsec)} {
+			if strings.HasPrefix(strings.ToLower(value), prefix+"1") {
+				continue
+			}
+			if got, err := decode(value); !errors.Is(err, ErrUnexpectedPrefix) {
+				t.Errorf("decoding %s as %s: expected ErrUnexpectedPrefix, got %q %v", value[:8], prefix, got, err)
+			}
+		}
+		if got, err := decode("not-a-key"); !errors.Is(err, ErrInvalidEntity) {
+			t.Errorf("decoding a bare string as %s: expected ErrInvalidEntity, got %q %v", prefix, got, err)
+		}
+	}
+
+	if _, err := DecodeCoordinate("38188:npub1x:m1"); !errors.Is(err, ErrInvalidEntity) {
+		t.Errorf("expected a coordinate without a hex pubkey to fail, got %v", err)
+	}
+}
+
+// TestMatchesGoNostr cross-checks the encodings against go-nostr's nip19 package.
+func TestMatchesGoNostr(t *testing.T) {
+	relays := []string{"wss://relay.example.com", "wss://nostr.banana.com"}
+
+	npub, _ := EncodePublicKey(vectorKey)
+	if want, _ := gonip19.EncodePublicKey(vectorKey); npub != want {
+		t.Errorf("npub: got %s, go-nostr %s", npub, want)
+	}
+	nsec, _ := EncodePrivateKey(vectorKey)
+	if want, _ := gonip19.EncodePrivateKey(vectorKey); nsec != want {
+		t.Errorf("nsec: got %s, go-nostr %s", nsec, want)
+	}
+
+	naddr, _ := EncodeAddress(AddressPointer{Kind: core.KindPromotion, Pubkey: vectorKey, Identifier: "org.attnprotocol:promotion:p1", Relays: relays})
+	if want, _ := gonip19.EncodeEntity(vectorKey, core.KindPromotion, "org.attnprotocol:promotion:p1", relays); naddr != want {
+		t.Errorf("naddr: got %s, go-nostr %s", naddr, want)
+	}
+
+	nevent, _ := EncodeEvent(EventPointer{ID: strings.Repeat("01", 32), Relays: relays, Author: vectorKey})
+	if want, _ := gonip19.EncodeEvent(strings.Repeat("01", 32), relays, vectorKey); nevent != want {
+		t.Errorf("nevent: got %s, go-nostr %s", nevent, want)
+	}
+
+	// go-nostr's encoder writes no nevent kind, so check its decoder reads ours
+	with_kind, _ := EncodeEvent(EventPointer{ID: strings.Repeat("01", 32), Author: vectorKey, Kind: core.KindMatch})
+	if _, data, err := gonip19.Decode(with_kind); err != nil || data.(nostr.EventPointer).Kind != core.KindMatch {
+		t.Errorf("expected go-nostr to decode the nevent kind, got %+v %v", data, err)
+	}
+	_, data, err := gonip19.Decode(naddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pointer := data.(nostr.EntityPointer); pointer.Kind != core.KindPromotion || pointer.PublicKey != vectorKey || len(pointer.Relays) != 2 {
+		t.Errorf("unexpected go-nostr decode of naddr: %+v", pointer)
+	}
+}
@@ -0,0 +1,74 @@
+package nip46
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// BunkerURI is a parsed bunker:// connection string handed out by a remote signer:
+//
+//	bunker://<remote-signer-pubkey>?relay=wss://relay.example.com&secret=<secret>
+type BunkerURI struct {
+	// RemoteSignerPubkey is the hex pubkey the remote signer talks with.
+	// It may differ from the user pubkey the signer signs events with.
+	RemoteSignerPubkey string
+
+	// Relays are the relays the remote signer listens on.
+	Relays []string
+
+	// Secret is the optional single-use connection secret.
+	Secret string
+}
+
+// ParseBunkerURI parses a bunker:// URI.
+// Returns ErrInvalidBunkerURI if the scheme, pubkey or relays are missing or malformed.
+func ParseBunkerURI(uri string) (BunkerURI, error) {
+	parsed, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return BunkerURI{}, fmt.Errorf("%w: %v", ErrInvalidBunkerURI, err)
+	}
+	if parsed.Scheme != "bunker" {
+		return BunkerURI{}, fmt.Errorf("%w: scheme must be bunker://", ErrInvalidBunkerURI)
+	}
+
+	pubkey := parsed.Host
+	if pubkey == "" {
+		pubkey = strings.TrimPrefix(parsed.Opaque, "//")
+	}
+	if !nostr.IsValid32ByteHex(pubkey) {
+		return BunkerURI{}, fmt.Errorf("%w: remote signer pubkey must be 64-character lowercase hex", ErrInvalidBunkerURI)
+	}
+
+	query := parsed.Query()
+	bunker := BunkerURI{
+		RemoteSignerPubkey: pubkey,
+		Secret:             query.Get("secret"),
+	}
+	for _, relay := range query["relay"] {
+		relay_url, err := url.Parse(relay)
+		if err != nil || (relay_url.Scheme != "wss" && relay_url.Scheme != "ws") || relay_url.Host == "" {
+			return BunkerURI{}, fmt.Errorf("%w: bad relay URL %q", ErrInvalidBunkerURI, relay)
+		}
+		bunker.Relays = append(bunker.Relays, relay)
+	}
+	if len(bunker.Relays) == 0 {
+		return BunkerURI{}, fmt.Errorf("%w: at least one relay is required", ErrInvalidBunkerURI)
+	}
+
+	return bunker, nil
+}
+
+// String formats the URI as a bunker:// connection string.
+func (b BunkerURI) String() string {
+	query := url.Values{}
+	for _, relay := range b.Relays {
+		query.Add("relay", relay)
+	}
+	if b.Secret != "" {
+		query.Set("secret", b.Secret)
+	}
+	return (&url.URL{Scheme: "bunker", Host: b.RemoteSignerPubkey, RawQuery: query.Encode()}).String()
+}
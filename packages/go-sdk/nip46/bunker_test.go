@@ -0,0 +1,40 @@
+package nip46
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseBunkerURI(t *testing.T) {
+	pubkey := strings.Repeat("ab", 32)
+
+	bunker, err := ParseBunkerURI("bunker://" + pubkey + "?relay=wss%3A%2F%2Frelay.example.com&relay=wss://relay2.example.com&secret=s3cret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bunker.RemoteSignerPubkey != pubkey || bunker.Secret != "s3cret" {
+		t.Errorf("unexpected bunker: %+v", bunker)
+	}
+	if len(bunker.Relays) != 2 || bunker.Relays[0] != "wss://relay.example.com" {
+		t.Errorf("unexpected relays: %v", bunker.Relays)
+	}
+
+	round_trip, err := ParseBunkerURI(bunker.String())
+	if err != nil || round_trip.String() != bunker.String() {
+		t.Errorf("expected String to round trip, got %v %v", round_trip, err)
+	}
+
+	for _, uri := range []string{
+		"",
+		"nostrconnect://" + pubkey + "?relay=wss://relay.example.com",
+		"bunker://" + strings.ToUpper(pubkey) + "?relay=wss://relay.example.com",
+		"bunker://abc?relay=wss://relay.example.com",
+		"bunker://" + pubkey,
+		"bunker://" + pubkey + "?relay=https://relay.example.com",
+	} {
+		if _, err := ParseBunkerURI(uri); !errors.Is(err, ErrInvalidBunkerURI) {
+			t.Errorf("ParseBunkerURI(%q) = %v, want ErrInvalidBunkerURI", uri, err)
+		}
+	}
+}
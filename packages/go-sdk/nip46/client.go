@@ -0,0 +1,290 @@
+package nip46
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relay"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// DefaultTimeout bounds each request when ClientOptions.Timeout is zero.
+const DefaultTimeout = 30 * time.Second
+
+// ClientOptions configures a Client.
+type ClientOptions struct {
+	// SecretKey is the client's hex-encoded key, used to sign and encrypt requests.
+	// A fresh key is generated when empty. Reuse a key to resume an authorized session.
+	SecretKey string
+
+	// Perms are the permissions requested on connect, e.g. "sign_event:38888,nip44_encrypt".
+	Perms string
+
+	// Timeout bounds each request, including connect. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// OnAuthURL is called with the URL when the remote signer asks the user to
+	// authorize a request there. The request then keeps waiting, within Timeout, for
+	// the result the remote signer sends once the user has authorized it. Without
+	// OnAuthURL such requests fail with ErrAuthRequired.
+	OnAuthURL func(auth_url string)
+}
+
+// request is the decrypted content of a client request event.
+type request struct {
+	ID     string   `json:"id"`
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+}
+
+// response is the decrypted content of a remote signer response event.
+type response struct {
+	ID     string `json:"id"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Client is a NIP-46 "Nostr Connect" client. It sends requests to a remote signer
+// as NIP-44 encrypted kind 24133 events over the bunker's relays and implements
+// Transport, so it can back a RemoteSigner. Bunker relays that drop are reconnected
+// and resubscribed with the relay.Pool backoff.
+type Client struct {
+	bunker       BunkerURI
+	clientKey    *core.KeySigner
+	clientPubkey string
+	timeout      time.Duration
+	onAuthURL    func(auth_url string)
+
+	pool *relay.Pool
+	sub  *relay.Subscription
+
+	mu      sync.Mutex
+	pending map[string]chan response
+	closed  bool
+}
+
+// Connect connects to the remote signer described by a bunker:// URI and performs
+// the connect handshake. Close the client when done.
+func Connect(ctx context.Context, bunker_uri string, options ClientOptions) (*Client, error) {
+	bunker, err := ParseBunkerURI(bunker_uri)
+	if err != nil {
+		return nil, err
+	}
+	return ConnectBunker(ctx, bunker, options)
+}
+
+// ConnectBunker connects to a parsed bunker URI and performs the connect handshake.
+// Returns ErrConnectRejected if the remote signer does not acknowledge the connection.
+func ConnectBunker(ctx context.Context, bunker BunkerURI, options ClientOptions) (*Client, error) {
+	secret_key := options.SecretKey
+	if secret_key == "" {
+		secret_key = nostr.GeneratePrivateKey()
+	}
+	client_key, err := core.NewKeySigner(secret_key)
+	if err != nil {
+		return nil, err
+	}
+	client_pubkey, _ := client_key.GetPublicKey(ctx)
+
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	pool, err := relay.NewPool(bunker.Relays)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoRelaysConnected, err)
+	}
+	if err := pool.Connect(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("%w: %v", ErrNoRelaysConnected, err)
+	}
+
+	c := &Client{
+		bunker:       bunker,
+		clientKey:    client_key,
+		clientPubkey: client_pubkey,
+		timeout:      timeout,
+		onAuthURL:    options.OnAuthURL,
+		pool:         pool,
+		pending:      make(map[string]chan response),
+	}
+
+	// Listen for responses addressed to this client on every bunker relay. The pool
+	// resubscribes relays that drop, and responses they replay are delivered once.
+	c.sub = pool.Subscribe(context.Background(), nostr.Filters{{
+		Kinds:   []int{nostr.KindNostrConnect},
+		Authors: []string{bunker.RemoteSignerPubkey},
+		Tags:    nostr.TagMap{"p": []string{client_pubkey}},
+		Since:   ptrTimestamp(nostr.Now()),
+	}})
+	go c.listen()
+
+	result, err := c.Request(ctx, MethodConnect, []string{bunker.RemoteSignerPubkey, bunker.Secret, options.Perms})
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	if result != "ack" && (bunker.Secret == "" || result != bunker.Secret) {
+		c.Close()
+		return nil, fmt.Errorf("%w: unexpected result %q", ErrConnectRejected, result)
+	}
+
+	return c, nil
+}
+
+// Signer returns a RemoteSigner that signs through this client.
+func (c *Client) Signer() *RemoteSigner {
+	return NewRemoteSigner(c)
+}
+
+// ClientPubkey returns the pubkey the client signs its requests with.
+func (c *Client) ClientPubkey() string {
+	return c.clientPubkey
+}
+
+// Request sends a request to the remote signer and waits for its result.
+// Returns ErrRequestTimeout if no response arrives within the client timeout,
+// ErrAuthRequired if the user must authorize the request at an auth URL and no
+// ClientOptions.OnAuthURL is set, and ErrRemoteSigner if the remote signer answers
+// with an error.
+func (c *Client) Request(ctx context.Context, method string, params []string) (string, error) {
+	id, err := randomID()
+	if err != nil {
+		return "", err
+	}
+	if params == nil {
+		params = []string{}
+	}
+
+	payload, err := json.Marshal(request{ID: id, Method: method, Params: params})
+	if err != nil {
+		return "", err
+	}
+	content, err := c.clientKey.Encrypt(ctx, c.bunker.RemoteSignerPubkey, string(payload))
+	if err != nil {
+		return "", err
+	}
+	event := &nostr.Event{
+		CreatedAt: nostr.Now(),
+		Kind:      nostr.KindNostrConnect,
+		Tags:      nostr.Tags{{"p", c.bunker.RemoteSignerPubkey}},
+		Content:   content,
+	}
+	if err := c.clientKey.SignEvent(ctx, event); err != nil {
+		return "", err
+	}
+
+	// Register before publishing so a fast response is not missed. The buffer holds
+	// an auth_url response and the result that follows it.
+	responses := make(chan response, 2)
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return "", ErrClientClosed
+	}
+	c.pending[id] = responses
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	if _, err := c.pool.Publish(ctx, event); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("%w: %s", ErrRequestTimeout, method)
+		}
+		return "", fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+
+	for {
+		select {
+		case resp := <-responses:
+			if resp.Result == "auth_url" {
+				if c.onAuthURL == nil {
+					return "", fmt.Errorf("%w: %s", ErrAuthRequired, resp.Error)
+				}
+				c.onAuthURL(resp.Error)
+				continue
+			}
+			if resp.Error != "" {
+				return "", fmt.Errorf("%w: %s", ErrRemoteSigner, resp.Error)
+			}
+			return resp.Result, nil
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return "", fmt.Errorf("%w: %s", ErrRequestTimeout, method)
+			}
+			return "", ctx.Err()
+		}
+	}
+}
+
+// Close closes the relay connections. Pending requests fail when their context ends.
+func (c *Client) Close() {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.closed = true
+	c.mu.Unlock()
+
+	c.sub.Close()
+	c.pool.Close()
+}
+
+// listen delivers responses to waiting requests until the subscription ends. The
+// pool subscription already drops copies of a response arriving from several
+// relays. A request stays pending until Request returns, so the result that follows
+// an auth_url response still reaches it.
+func (c *Client) listen() {
+	for received := range c.sub.Events {
+		event := received.Event
+		plaintext, err := c.clientKey.Decrypt(context.Background(), event.PubKey, event.Content)
+		if err != nil {
+			continue
+		}
+		var resp response
+		if err := json.Unmarshal([]byte(plaintext), &resp); err != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		responses, ok := c.pending[resp.ID]
+		c.mu.Unlock()
+		if !ok {
+			continue
+		}
+		// Never block the subscription on a request that stopped reading
+		select {
+		case responses <- resp:
+		default:
+		}
+	}
+}
+
+// randomID returns a random request id.
+func randomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// ptrTimestamp returns a pointer to the timestamp, for filter fields.
+func ptrTimestamp(timestamp nostr.Timestamp) *nostr.Timestamp {
+	return &timestamp
+}
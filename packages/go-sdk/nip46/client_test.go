@@ -0,0 +1,299 @@
+package nip46
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relaytest"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// testBunker is an in-process remote signer stand-in listening on a relay.
+type testBunker struct {
+	key    *core.KeySigner
+	user   *core.KeySigner
+	secret string
+	ignore map[string]bool
+	relay  *nostr.Relay
+
+	mu      sync.Mutex
+	authURL string
+}
+
+// startBunker runs a remote signer on the relay until the test ends.
+// Requests for the ignored methods are never answered.
+func startBunker(t *testing.T, relay_url string, secret string, ignore ...string) (*testBunker, BunkerURI) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	key, _ := core.NewKeySigner(strings.Repeat("0a", 32))
+	user, _ := core.NewKeySigner(strings.Repeat("0b", 32))
+	b := &testBunker{key: key, user: user, secret: secret, ignore: make(map[string]bool)}
+	for _, method := range ignore {
+		b.ignore[method] = true
+	}
+
+	relay, err := nostr.RelayConnect(ctx, relay_url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { relay.Close() })
+	b.relay = relay
+
+	pubkey, _ := key.GetPublicKey(ctx)
+	sub, err := relay.Subscribe(ctx, nostr.Filters{{
+		Kinds: []int{nostr.KindNostrConnect},
+		Tags:  nostr.TagMap{"p": []string{pubkey}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for event := range sub.Events {
+			b.handle(ctx, event)
+		}
+	}()
+
+	return b, BunkerURI{RemoteSignerPubkey: pubkey, Relays: []string{relay_url}, Secret: secret}
+}
+
+// requireAuth makes the bunker answer sign_event requests with an auth_url response
+// before the result, as if the user authorized each one at the URL.
+func (b *testBunker) requireAuth(auth_url string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.authURL = auth_url
+}
+
+// handle answers one request event.
+func (b *testBunker) handle(ctx context.Context, event *nostr.Event) {
+	plaintext, err := b.key.Decrypt(ctx, event.PubKey, event.Content)
+	if err != nil {
+		return
+	}
+	var req request
+	if err := json.Unmarshal([]byte(plaintext), &req); err != nil || b.ignore[req.Method] {
+		return
+	}
+
+	resp := response{ID: req.ID}
+	switch req.Method {
+	case MethodConnect:
+		if b.secret != "" && (len(req.Params) < 2 || req.Params[1] != b.secret) {
+			resp.Error = "invalid secret"
+		} else {
+			resp.Result = "ack"
+		}
+	case MethodGetPublicKey:
+		resp.Result, _ = b.user.GetPublicKey(ctx)
+	case MethodSignEvent:
+		b.mu.Lock()
+		auth_url := b.authURL
+		b.mu.Unlock()
+		if auth_url != "" {
+			b.reply(ctx, event, response{ID: req.ID, Result: "auth_url", Error: auth_url})
+		}
+		var template nostr.Event
+		if err := json.Unmarshal([]byte(req.Params[0]), &template); err != nil {
+			resp.Error = err.Error()
+			break
+		}
+		b.user.SignEvent(ctx, &template)
+		resp.Result = template.String()
+	case MethodNIP44Encrypt:
+		resp.Result, _ = b.user.Encrypt(ctx, req.Params[0], req.Params[1])
+	case MethodNIP44Decrypt:
+		resp.Result, _ = b.user.Decrypt(ctx, req.Params[0], req.Params[1])
+	default:
+		resp.Error = "unsupported method"
+	}
+	b.reply(ctx, event, resp)
+}
+
+// reply publishes a response to a request event.
+func (b *testBunker) reply(ctx context.Context, event *nostr.Event, resp response) {
+	payload, _ := json.Marshal(resp)
+	content, _ := b.key.Encrypt(ctx, event.PubKey, string(payload))
+	reply := nostr.Event{
+		CreatedAt: nostr.Now(),
+		Kind:      nostr.KindNostrConnect,
+		Tags:      nostr.Tags{{"p", event.PubKey}},
+		Content:   content,
+	}
+	b.key.SignEvent(ctx, &reply)
+	b.relay.Publish(ctx, reply)
+}
+
+func TestClientSignsThroughBunker(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	bunker, uri := startBunker(t, server.URL, "s3cret")
+
+	ctx := context.Background()
+	client, err := Connect(ctx, uri.String(), ClientOptions{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer client.Close()
+
+	signer := client.Signer()
+	pubkey, err := signer.GetPublicKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user_pubkey, _ := bunker.user.GetPublicKey(ctx); pubkey != user_pubkey {
+		t.Errorf("expected user pubkey %s, got %s", user_pubkey, pubkey)
+	}
+
+	event := &nostr.Event{Kind: core.KindMatch, CreatedAt: nostr.Now(), Content: "{}", Tags: nostr.Tags{{"t", "870500"}}}
+	if err := signer.SignEvent(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := event.CheckSignature(); !ok || event.PubKey != pubkey {
+		t.Errorf("expected event signed by the user key, got pubkey %s", event.PubKey)
+	}
+
+	ciphertext, err := core.Encrypt(ctx, signer, client.ClientPubkey(), "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := core.Decrypt(ctx, signer, client.ClientPubkey(), ciphertext); err != nil {
+		t.Errorf("expected decrypt round trip, got %v", err)
+	}
+
+	if _, err := client.Request(ctx, "unknown_method", nil); !errors.Is(err, ErrRemoteSigner) {
+		t.Errorf("expected ErrRemoteSigner, got %v", err)
+	}
+}
+
+func TestClientRejectsWrongSecret(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	_, uri := startBunker(t, server.URL, "s3cret")
+
+	uri.Secret = "wrong"
+	if _, err := ConnectBunker(context.Background(), uri, ClientOptions{Timeout: 5 * time.Second}); !errors.Is(err, ErrRemoteSigner) {
+		t.Errorf("expected ErrRemoteSigner, got %v", err)
+	}
+}
+
+func TestClientRequestTimeout(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	_, uri := startBunker(t, server.URL, "", MethodSignEvent)
+
+	ctx := context.Background()
+	client, err := ConnectBunker(ctx, uri, ClientOptions{Timeout: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer client.Close()
+
+	err = client.Signer().SignEvent(ctx, &nostr.Event{Kind: core.KindMatch, CreatedAt: nostr.Now()})
+	if !errors.Is(err, ErrRequestTimeout) {
+		t.Errorf("expected ErrRequestTimeout, got %v", err)
+	}
+
+	client.Close()
+	if _, err := client.Request(ctx, MethodPing, nil); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+}
+
+func TestConnectWithoutRelays(t *testing.T) {
+	server := relaytest.NewServer()
+	url := server.URL
+	server.Close()
+
+	uri := BunkerURI{RemoteSignerPubkey: strings.Repeat("ab", 32), Relays: []string{url}}
+	if _, err := ConnectBunker(context.Background(), uri, ClientOptions{Timeout: time.Second}); !errors.Is(err, ErrNoRelaysConnected) {
+		t.Errorf("expected ErrNoRelaysConnected, got %v", err)
+	}
+}
+
+func TestClientWaitsForAuthorizedResult(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	bunker, uri := startBunker(t, server.URL, "")
+	bunker.requireAuth("https://signer.example.com/authorize")
+
+	ctx := context.Background()
+	var auth_urls []string
+	var mu sync.Mutex
+	client, err := ConnectBunker(ctx, uri, ClientOptions{
+		Timeout: 5 * time.Second,
+		OnAuthURL: func(auth_url string) {
+			mu.Lock()
+			defer mu.Unlock()
+			auth_urls = append(auth_urls, auth_url)
+		},
+	})
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer client.Close()
+
+	event := &nostr.Event{Kind: core.KindMatch, CreatedAt: nostr.Now(), Content: "{}"}
+	if err := client.Signer().SignEvent(ctx, event); err != nil {
+		t.Fatalf("expected the result sent after authorization, got %v", err)
+	}
+	if ok, _ := event.CheckSignature(); !ok {
+		t.Error("expected a signed event")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(auth_urls) != 1 || auth_urls[0] != "https://signer.example.com/authorize" {
+		t.Errorf("expected the auth URL once, got %v", auth_urls)
+	}
+}
+
+func TestClientAuthRequiredWithoutHandler(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	bunker, uri := startBunker(t, server.URL, "")
+	bunker.requireAuth("https://signer.example.com/authorize")
+
+	ctx := context.Background()
+	client, err := ConnectBunker(ctx, uri, ClientOptions{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer client.Close()
+
+	err = client.Signer().SignEvent(ctx, &nostr.Event{Kind: core.KindMatch, CreatedAt: nostr.Now()})
+	if !errors.Is(err, ErrAuthRequired) || !strings.Contains(err.Error(), "https://signer.example.com/authorize") {
+		t.Errorf("expected ErrAuthRequired with the auth URL, got %v", err)
+	}
+}
+
+func TestClientReconnectsAfterRelayDrop(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	_, uri := startBunker(t, server.URL, "")
+
+	ctx := context.Background()
+	client, err := ConnectBunker(ctx, uri, ClientOptions{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer client.Close()
+
+	// A relay restart drops both sides; the bunker comes back on a new connection
+	server.DropConnections()
+	bunker, _ := startBunker(t, server.URL, "")
+
+	pubkey, err := client.Signer().GetPublicKey(ctx)
+	if err != nil {
+		t.Fatalf("expected the client to reconnect, got %v", err)
+	}
+	if user_pubkey, _ := bunker.user.GetPublicKey(ctx); pubkey != user_pubkey {
+		t.Errorf("expected user pubkey %s, got %s", user_pubkey, pubkey)
+	}
+}
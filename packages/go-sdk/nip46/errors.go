@@ -0,0 +1,33 @@
+package nip46
+
+import "errors"
+
+var (
+	// ErrInvalidBunkerURI is returned when a bunker:// URI cannot be parsed.
+	ErrInvalidBunkerURI = errors.New("invalid bunker URI")
+
+	// ErrNoRelaysConnected is returned when none of the bunker's relays could be reached.
+	ErrNoRelaysConnected = errors.New("no bunker relays connected")
+
+	// ErrPublishFailed is returned when a request could not be published to any relay.
+	ErrPublishFailed = errors.New("failed to publish request to any relay")
+
+	// ErrRequestTimeout is returned when the remote signer does not answer in time.
+	ErrRequestTimeout = errors.New("remote signer request timed out")
+
+	// ErrConnectRejected is returned when the connect handshake is not acknowledged.
+	ErrConnectRejected = errors.New("remote signer rejected connect")
+
+	// ErrRemoteSigner is returned when the remote signer answers a request with an error.
+	ErrRemoteSigner = errors.New("remote signer error")
+
+	// ErrAuthRequired is returned when the remote signer asks the user to authorize the
+	// request at an auth URL first.
+	ErrAuthRequired = errors.New("remote signer requires authorization")
+
+	// ErrClientClosed is returned when a request is made on a closed client.
+	ErrClientClosed = errors.New("nip46 client is closed")
+
+	// ErrInvalidResponse is returned when the remote signer's result cannot be used.
+	ErrInvalidResponse = errors.New("invalid remote signer response")
+)
@@ -0,0 +1,148 @@
+// Package nip46 provides a NIP-46 remote signer for ATTN Protocol services.
+//
+// A RemoteSigner implements core.Signer and core.Encrypter by forwarding each
+// request to a remote signer (a "bunker"), so the service signing events never
+// holds the private key.
+//
+// Example usage:
+//
+//	signer := nip46.NewRemoteSigner(transport)
+//
+//	event, err := events.CreateMatchWithSigner(ctx, signer, params)
+package nip46
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// NIP-46 request methods.
+const (
+	MethodConnect      = "connect"
+	MethodPing         = "ping"
+	MethodGetPublicKey = "get_public_key"
+	MethodSignEvent    = "sign_event"
+	MethodNIP44Encrypt = "nip44_encrypt"
+	MethodNIP44Decrypt = "nip44_decrypt"
+)
+
+// Transport delivers NIP-46 requests to a remote signer and returns the result string.
+// A response carrying an error must be returned as a non-nil error.
+type Transport interface {
+	Request(ctx context.Context, method string, params []string) (string, error)
+}
+
+// RemoteSigner is a core.Signer that forwards signing and NIP-44 requests to a
+// remote signer over a Transport.
+type RemoteSigner struct {
+	transport Transport
+
+	mu        sync.Mutex
+	publicKey string
+}
+
+// NewRemoteSigner creates a remote signer that sends requests over the transport.
+func NewRemoteSigner(transport Transport) *RemoteSigner {
+	return &RemoteSigner{transport: transport}
+}
+
+// GetPublicKey returns the remote signer's user pubkey, asking for it on first use.
+func (s *RemoteSigner) GetPublicKey(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.publicKey != "" {
+		return s.publicKey, nil
+	}
+
+	pubkey, err := s.transport.Request(ctx, MethodGetPublicKey, nil)
+	if err != nil {
+		return "", err
+	}
+	if !nostr.IsValid32ByteHex(pubkey) {
+		return "", fmt.Errorf("%w: bad public key %q", ErrInvalidResponse, pubkey)
+	}
+
+	s.publicKey = pubkey
+	return pubkey, nil
+}
+
+// unsignedEvent is the event template sent with sign_event requests.
+type unsignedEvent struct {
+	Kind      int             `json:"kind"`
+	Content   string          `json:"content"`
+	Tags      nostr.Tags      `json:"tags"`
+	CreatedAt nostr.Timestamp `json:"created_at"`
+}
+
+// SignEvent asks the remote signer to sign the event and sets its pubkey, id and
+// signature. The signed event must carry the signer's pubkey and a valid signature
+// over the event as sent.
+func (s *RemoteSigner) SignEvent(ctx context.Context, event *nostr.Event) error {
+	if event == nil {
+		return core.ErrNilEvent
+	}
+
+	pubkey, err := s.GetPublicKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	if event.Tags == nil {
+		event.Tags = nostr.Tags{}
+	}
+	template, err := json.Marshal(unsignedEvent{
+		Kind:      event.Kind,
+		Content:   event.Content,
+		Tags:      event.Tags,
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	result, err := s.transport.Request(ctx, MethodSignEvent, []string{string(template)})
+	if err != nil {
+		return err
+	}
+
+	var signed nostr.Event
+	if err := json.Unmarshal([]byte(result), &signed); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if signed.PubKey != pubkey {
+		return fmt.Errorf("%w: signed by %s, expected %s", ErrInvalidResponse, signed.PubKey, pubkey)
+	}
+
+	// Only take the signature fields, so the signer cannot alter the event
+	candidate := *event
+	candidate.PubKey = signed.PubKey
+	candidate.ID = signed.ID
+	candidate.Sig = signed.Sig
+	if candidate.GetID() != signed.ID {
+		return fmt.Errorf("%w: signed event does not match the request", ErrInvalidResponse)
+	}
+	if ok, err := candidate.CheckSignature(); !ok || err != nil {
+		return fmt.Errorf("%w: bad signature", ErrInvalidResponse)
+	}
+
+	event.PubKey = candidate.PubKey
+	event.ID = candidate.ID
+	event.Sig = candidate.Sig
+	return nil
+}
+
+// Encrypt asks the remote signer to NIP-44 encrypt plaintext for the recipient pubkey.
+func (s *RemoteSigner) Encrypt(ctx context.Context, recipient_pubkey string, plaintext string) (string, error) {
+	return s.transport.Request(ctx, MethodNIP44Encrypt, []string{recipient_pubkey, plaintext})
+}
+
+// Decrypt asks the remote signer to decrypt a NIP-44 payload from the sender pubkey.
+func (s *RemoteSigner) Decrypt(ctx context.Context, sender_pubkey string, ciphertext string) (string, error) {
+	return s.transport.Request(ctx, MethodNIP44Decrypt, []string{sender_pubkey, ciphertext})
+}
@@ -0,0 +1,102 @@
+package nip46
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// keyTransport answers requests locally with an in-memory signer.
+type keyTransport struct {
+	signer   *core.KeySigner
+	tamper   bool
+	requests []string
+}
+
+func (k *keyTransport) Request(ctx context.Context, method string, params []string) (string, error) {
+	k.requests = append(k.requests, method)
+	switch method {
+	case MethodGetPublicKey:
+		return k.signer.GetPublicKey(ctx)
+	case MethodSignEvent:
+		var event nostr.Event
+		if err := json.Unmarshal([]byte(params[0]), &event); err != nil {
+			return "", err
+		}
+		if k.tamper {
+			event.Content = "tampered"
+		}
+		if err := k.signer.SignEvent(ctx, &event); err != nil {
+			return "", err
+		}
+		return event.String(), nil
+	case MethodNIP44Encrypt:
+		return k.signer.Encrypt(ctx, params[0], params[1])
+	case MethodNIP44Decrypt:
+		return k.signer.Decrypt(ctx, params[0], params[1])
+	}
+	return "", errors.New("unsupported method")
+}
+
+func newKeyTransport(t *testing.T) *keyTransport {
+	signer, err := core.NewKeySigner(strings.Repeat("01", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &keyTransport{signer: signer}
+}
+
+func TestRemoteSignerSignEvent(t *testing.T) {
+	ctx := context.Background()
+	transport := newKeyTransport(t)
+	signer := NewRemoteSigner(transport)
+
+	event := &nostr.Event{Kind: core.KindMatch, Content: "{}", CreatedAt: nostr.Now()}
+	if err := signer.SignEvent(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := event.CheckSignature(); !ok || err != nil {
+		t.Errorf("expected valid signature, got %v %v", ok, err)
+	}
+
+	if err := signer.SignEvent(ctx, &nostr.Event{Kind: core.KindMatch}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(strings.Join(transport.requests, ","), MethodGetPublicKey); got != 1 {
+		t.Errorf("expected the public key to be requested once, got %d", got)
+	}
+
+	transport.tamper = true
+	if err := signer.SignEvent(ctx, &nostr.Event{Kind: core.KindMatch, Content: "{}"}); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse for a tampered event, got %v", err)
+	}
+}
+
+func TestRemoteSignerEncrypt(t *testing.T) {
+	ctx := context.Background()
+	signer := NewRemoteSigner(newKeyTransport(t))
+
+	peer, err := core.NewKeySigner(strings.Repeat("02", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer_pubkey, _ := peer.GetPublicKey(ctx)
+	pubkey, err := signer.GetPublicKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := core.Encrypt(ctx, signer, peer_pubkey, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := core.Decrypt(ctx, peer, pubkey, ciphertext)
+	if err != nil || plaintext != "hello" {
+		t.Errorf("expected round trip, got %q %v", plaintext, err)
+	}
+}
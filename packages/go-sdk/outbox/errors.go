@@ -0,0 +1,14 @@
+package outbox
+
+import "errors"
+
+var (
+	// ErrUnsignedEvent is returned when an event without an id and signature is enqueued.
+	ErrUnsignedEvent = errors.New("event is not signed")
+
+	// ErrInvalidEventID is returned when a stored entry's event id is not 32 hex bytes.
+	ErrInvalidEventID = errors.New("invalid event id")
+
+	// ErrEntryNotFound is returned when no entry exists for an event id.
+	ErrEntryNotFound = errors.New("outbox entry not found")
+)
@@ -0,0 +1,468 @@
+// Package outbox delivers signed events to relays durably.
+//
+// An Outbox persists each event to a Store before publishing it, then retries every
+// relay with backoff until that relay accepts the event or refuses it permanently.
+// Events still pending when the process exits are picked up again on restart, so
+// confirmations and payment confirmations are not lost to a relay outage.
+package outbox
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relay"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// Outbox defaults applied to zero Options fields.
+const (
+	DefaultInitialBackoff = 5 * time.Second
+	DefaultMaxBackoff     = 10 * time.Minute
+)
+
+// Status is an event's delivery state on one relay.
+type Status string
+
+// Delivery states.
+const (
+	// StatusPending means the relay has not answered yet or failed transiently.
+	StatusPending Status = "pending"
+
+	// StatusAccepted means the relay accepted the event or already had it.
+	StatusAccepted Status = "accepted"
+
+	// StatusRejected means the relay refused the event permanently, e.g. as invalid.
+	StatusRejected Status = "rejected"
+)
+
+// Delivery is an event's delivery state on one relay.
+type Delivery struct {
+	Status Status `json:"status"`
+
+	// Attempts counts the delivery rounds made to this relay.
+	Attempts int `json:"attempts"`
+
+	// Error is the last failure, kept for inspection.
+	Error string `json:"error,omitempty"`
+
+	// NextAttempt is when a pending relay is retried.
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// Entry is an event in the outbox and its delivery state per relay URL.
+type Entry struct {
+	Event     *nostr.Event         `json:"event"`
+	CreatedAt time.Time            `json:"created_at"`
+	Relays    map[string]*Delivery `json:"relays"`
+}
+
+// Stats counts the entries in the outbox.
+type Stats struct {
+	// Pending is the number of events still waiting on at least one relay.
+	Pending int
+
+	// Failed is the number of events every relay refused permanently.
+	Failed int
+}
+
+// Options configures how an Outbox retries relays.
+type Options struct {
+	// InitialBackoff is the wait before retrying a relay after its first failure.
+	// It doubles after each failed round, up to MaxBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between retries.
+	MaxBackoff time.Duration
+}
+
+// withDefaults fills zero fields with the package defaults.
+func (o Options) withDefaults() Options {
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = DefaultInitialBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultMaxBackoff
+	}
+	return o
+}
+
+// backoff returns the wait after the given number of failed rounds.
+func (o Options) backoff(failures int) time.Duration {
+	wait := o.InitialBackoff
+	for i := 1; i < failures && wait < o.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > o.MaxBackoff {
+		wait = o.MaxBackoff
+	}
+	return wait
+}
+
+// Outbox persists signed events and delivers them to every relay in a pool.
+// Fully delivered events are removed from the store; events no relay accepted
+// stay in it as failed until retried or removed.
+type Outbox struct {
+	store   Store
+	pool    *relay.Pool
+	options Options
+	wake    chan struct{}
+
+	mu      sync.Mutex
+	entries map[string]*Entry
+}
+
+// New returns an outbox delivering through pool, resuming the entries in store.
+// Pending deliveries to relays no longer in the pool are marked rejected.
+func New(store Store, pool *relay.Pool, options Options) (*Outbox, error) {
+	stored, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	in_pool := make(map[string]bool)
+	for _, url := range pool.URLs() {
+		in_pool[url] = true
+	}
+
+	entries := make(map[string]*Entry, len(stored))
+	for _, entry := range stored {
+		var changed bool
+		for url, delivery := range entry.Relays {
+			if delivery.Status == StatusPending && !in_pool[url] {
+				delivery.Status = StatusRejected
+				delivery.Error = "relay is no longer in the pool"
+				changed = true
+			}
+		}
+		if changed {
+			if err := store.Save(entry); err != nil {
+				return nil, err
+			}
+		}
+		entries[entry.Event.ID] = entry
+	}
+
+	return &Outbox{
+		store:   store,
+		pool:    pool,
+		options: options.withDefaults(),
+		wake:    make(chan struct{}, 1),
+		entries: entries,
+	}, nil
+}
+
+// Enqueue stores a signed event for delivery to every relay in the pool and wakes Run.
+// Once Enqueue returns the event survives a restart. Enqueueing an event that is
+// already in the outbox does nothing.
+func (o *Outbox) Enqueue(event *nostr.Event) error {
+	if event == nil {
+		return core.ErrNilEvent
+	}
+	if ok, _ := event.CheckSignature(); !ok || event.GetID() != event.ID {
+		return ErrUnsignedEvent
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.entries[event.ID]; ok {
+		return nil
+	}
+
+	entry := &Entry{
+		Event:     event,
+		CreatedAt: time.Now(),
+		Relays:    make(map[string]*Delivery),
+	}
+	for _, url := range o.pool.URLs() {
+		entry.Relays[url] = &Delivery{Status: StatusPending}
+	}
+	if err := o.store.Save(entry); err != nil {
+		return err
+	}
+	o.entries[event.ID] = entry
+
+	o.notify()
+	return nil
+}
+
+// Run delivers pending events until ctx is done, retrying each relay when its
+// backoff expires. It returns ctx's error, or a store error.
+func (o *Outbox) Run(ctx context.Context) error {
+	for {
+		if err := o.deliver(ctx, false); err != nil {
+			return err
+		}
+
+		wait := o.options.MaxBackoff
+		if next, ok := o.nextAttempt(); ok {
+			wait = time.Until(next)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-o.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// Flush makes one delivery round to every pending relay now, ignoring the outbox's
+// backoff. Relays the pool is still backing off from are left for a later round.
+func (o *Outbox) Flush(ctx context.Context) error {
+	return o.deliver(ctx, true)
+}
+
+// Stats returns the number of pending and failed events.
+func (o *Outbox) Stats() Stats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var stats Stats
+	for _, entry := range o.entries {
+		if entry.pending() {
+			stats.Pending++
+		} else if entry.failed() {
+			stats.Failed++
+		}
+	}
+	return stats
+}
+
+// Failed returns copies of the events every relay refused permanently.
+func (o *Outbox) Failed() []*Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var failed []*Entry
+	for _, entry := range o.entries {
+		if entry.failed() {
+			failed = append(failed, entry.clone())
+		}
+	}
+	return failed
+}
+
+// Retry marks an event's rejected relays pending again, e.g. after fixing the
+// cause of an "auth-required:" or "restricted:" refusal.
+func (o *Outbox) Retry(event_id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry, ok := o.entries[event_id]
+	if !ok {
+		return ErrEntryNotFound
+	}
+	for _, delivery := range entry.Relays {
+		if delivery.Status == StatusRejected {
+			delivery.Status = StatusPending
+			delivery.NextAttempt = time.Time{}
+		}
+	}
+	if err := o.store.Save(entry); err != nil {
+		return err
+	}
+
+	o.notify()
+	return nil
+}
+
+// Remove deletes an event from the outbox without delivering it further.
+func (o *Outbox) Remove(event_id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.entries[event_id]; !ok {
+		return ErrEntryNotFound
+	}
+	if err := o.store.Delete(event_id); err != nil {
+		return err
+	}
+	delete(o.entries, event_id)
+	return nil
+}
+
+// deliver publishes every due event to its pending relays and records the outcome.
+// Each relay gets its own queue, oldest event first, and the queues run concurrently
+// so a relay that is down does not hold up delivery to the others. With force,
+// pending relays are tried regardless of the outbox's backoff.
+func (o *Outbox) deliver(ctx context.Context, force bool) error {
+	now := time.Now()
+	o.mu.Lock()
+	queues := make(map[string][]*Entry)
+	for _, entry := range o.entries {
+		for url, delivery := range entry.Relays {
+			if delivery.Status == StatusPending && (force || !delivery.NextAttempt.After(now)) {
+				queues[url] = append(queues[url], entry)
+			}
+		}
+	}
+	o.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 0, len(queues))
+	var errs_mu sync.Mutex
+	for url, entries := range queues {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		})
+		wg.Add(1)
+		go func(url string, entries []*Entry) {
+			defer wg.Done()
+			if err := o.deliverTo(ctx, url, entries); err != nil {
+				errs_mu.Lock()
+				errs = append(errs, err)
+				errs_mu.Unlock()
+			}
+		}(url, entries)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// deliverTo publishes queued entries to one relay in order. Once the pool is
+// backing off from the relay, the rest of the queue is postponed until the pool
+// will dial it again instead of each entry waiting out the backoff in turn.
+func (o *Outbox) deliverTo(ctx context.Context, url string, entries []*Entry) error {
+	for i, entry := range entries {
+		if until := o.pool.BackoffUntil(url); until.After(time.Now()) {
+			o.postpone(url, entries[i:], until)
+			return nil
+		}
+
+		results, _ := o.pool.PublishToRelays(ctx, entry.Event, []string{url})
+
+		// Publishes cut short by ctx say nothing about the relay
+		if results == nil || ctx.Err() != nil {
+			return nil
+		}
+		if err := o.record(entry, results); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postpone moves the relay's pending deliveries of the entries to the given time.
+// It is a scheduling hint that does not count as an attempt, so it is not persisted.
+func (o *Outbox) postpone(url string, entries []*Entry, until time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, entry := range entries {
+		if delivery := entry.Relays[url]; delivery.Status == StatusPending && delivery.NextAttempt.Before(until) {
+			delivery.NextAttempt = until
+		}
+	}
+}
+
+// record applies one round's publish results to an entry and persists it,
+// removing the entry once every relay has answered and one accepted it.
+func (o *Outbox) record(entry *Entry, results *relay.PublishResults) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	// The entry may have been removed while publishing
+	if o.entries[entry.Event.ID] != entry {
+		return nil
+	}
+
+	for _, result := range results.Results {
+		delivery := entry.Relays[result.RelayURL]
+		delivery.Attempts++
+		switch {
+		case result.Success:
+			delivery.Status = StatusAccepted
+			delivery.Error = ""
+		case relay.IsTransient(result.Error):
+			delivery.Error = result.Error.Error()
+			delivery.NextAttempt = time.Now().Add(o.options.backoff(delivery.Attempts))
+		default:
+			delivery.Status = StatusRejected
+			delivery.Error = result.Error.Error()
+		}
+	}
+
+	if !entry.pending() && !entry.failed() {
+		if err := o.store.Delete(entry.Event.ID); err != nil {
+			return err
+		}
+		delete(o.entries, entry.Event.ID)
+		return nil
+	}
+	return o.store.Save(entry)
+}
+
+// nextAttempt returns when the earliest pending relay is due. Returns false if no
+// relay is pending.
+func (o *Outbox) nextAttempt() (time.Time, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var next time.Time
+	var ok bool
+	for _, entry := range o.entries {
+		for _, delivery := range entry.Relays {
+			if delivery.Status != StatusPending {
+				continue
+			}
+			if !ok || delivery.NextAttempt.Before(next) {
+				next = delivery.NextAttempt
+				ok = true
+			}
+		}
+	}
+	return next, ok
+}
+
+// notify wakes Run without blocking.
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pending reports whether any relay still has to answer.
+func (e *Entry) pending() bool {
+	for _, delivery := range e.Relays {
+		if delivery.Status == StatusPending {
+			return true
+		}
+	}
+	return false
+}
+
+// failed reports whether every relay refused the event permanently.
+func (e *Entry) failed() bool {
+	for _, delivery := range e.Relays {
+		if delivery.Status != StatusRejected {
+			return false
+		}
+	}
+	return true
+}
+
+// clone returns a copy of the entry that is safe to hand out.
+func (e *Entry) clone() *Entry {
+	clone := &Entry{
+		Event:     e.Event,
+		CreatedAt: e.CreatedAt,
+		Relays:    make(map[string]*Delivery, len(e.Relays)),
+	}
+	for url, delivery := range e.Relays {
+		copied := *delivery
+		clone.Relays[url] = &copied
+	}
+	return clone
+}
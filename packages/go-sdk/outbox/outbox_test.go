@@ -0,0 +1,225 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relay"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relaytest"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// signedEvent returns a fresh signed confirmation event.
+func signedEvent(t *testing.T, content string) *nostr.Event {
+	t.Helper()
+	signer, err := core.NewKeySigner(strings.Repeat("01", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &nostr.Event{Kind: core.KindAttentionPaymentConfirmation, CreatedAt: nostr.Now(), Content: content}
+	if err := signer.SignEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+// newOutbox returns an outbox storing in dir and delivering to the URLs.
+func newOutbox(t *testing.T, dir string, urls ...string) *Outbox {
+	t.Helper()
+	pool, err := relay.NewPoolWithOptions(urls, relay.PoolOptions{MaxAttempts: 1, InitialBackoff: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outbox, err := New(store, pool, Options{InitialBackoff: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return outbox
+}
+
+func TestOutboxSurvivesRestart(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	server.SetReject(func(event *nostr.Event) string { return "error: database offline" })
+
+	dir := t.TempDir()
+	first := newOutbox(t, dir, server.URL)
+	event := signedEvent(t, "payment")
+	if err := first.Enqueue(event); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if stats := first.Stats(); stats.Pending != 1 || stats.Failed != 0 {
+		t.Fatalf("expected one pending event while the relay errors, got %+v", stats)
+	}
+
+	// A new process picks the event up from the store
+	server.SetReject(nil)
+	second := newOutbox(t, dir, server.URL)
+	if stats := second.Stats(); stats.Pending != 1 {
+		t.Fatalf("expected the pending event to be loaded, got %+v", stats)
+	}
+	if err := second.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if stats := second.Stats(); stats.Pending != 0 || stats.Failed != 0 {
+		t.Errorf("expected the event to be delivered, got %+v", stats)
+	}
+	if events := server.Events(); len(events) != 1 || events[0].ID != event.ID {
+		t.Errorf("expected the relay to store the event, got %d events", len(events))
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expected delivered entries to be removed, found %d files", len(files))
+	}
+}
+
+func TestOutboxKeepsPermanentRejections(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	server.SetReject(func(event *nostr.Event) string { return "restricted: not on the allow list" })
+
+	outbox := newOutbox(t, t.TempDir(), server.URL)
+	event := signedEvent(t, "restricted")
+	if err := outbox.Enqueue(event); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	failed := outbox.Failed()
+	if len(failed) != 1 || outbox.Stats().Failed != 1 {
+		t.Fatalf("expected one failed event, got %+v", outbox.Stats())
+	}
+	delivery := failed[0].Relays[server.URL]
+	if delivery.Status != StatusRejected || !strings.Contains(delivery.Error, "not on the allow list") {
+		t.Errorf("expected a rejected delivery with the relay's reason, got %+v", delivery)
+	}
+
+	server.SetReject(nil)
+	if err := outbox.Retry(event.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if stats := outbox.Stats(); stats.Pending != 0 || stats.Failed != 0 {
+		t.Errorf("expected the retried event to be delivered, got %+v", stats)
+	}
+	if err := outbox.Retry(event.ID); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("expected ErrEntryNotFound for a delivered event, got %v", err)
+	}
+}
+
+func TestOutboxRunDeliversPerRelay(t *testing.T) {
+	up := relaytest.NewServer()
+	defer up.Close()
+	flaky := relaytest.NewServer()
+	defer flaky.Close()
+	flaky.SetReject(func(event *nostr.Event) string { return "rate-limited: slow down" })
+
+	outbox := newOutbox(t, t.TempDir(), up.URL, flaky.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go outbox.Run(ctx)
+
+	if err := outbox.Enqueue(signedEvent(t, "run")); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return len(up.Events()) == 1 })
+	if stats := outbox.Stats(); stats.Pending != 1 {
+		t.Errorf("expected the event to wait on the rate-limited relay, got %+v", stats)
+	}
+
+	flaky.SetReject(nil)
+	waitFor(t, func() bool { return outbox.Stats().Pending == 0 })
+	if len(flaky.Events()) != 1 {
+		t.Errorf("expected the rate-limited relay to get the event on retry, got %d", len(flaky.Events()))
+	}
+}
+
+func TestOutboxDeadRelayDoesNotHoldUpOthers(t *testing.T) {
+	up := relaytest.NewServer()
+	defer up.Close()
+	down := relaytest.NewServer()
+	down_url := down.URL
+	down.Close()
+
+	pool, err := relay.NewPoolWithOptions([]string{up.URL, down_url}, relay.PoolOptions{MaxAttempts: 3, InitialBackoff: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	outbox, err := New(store, pool, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := outbox.Enqueue(signedEvent(t, fmt.Sprintf("payment %d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- outbox.Flush(context.Background()) }()
+	waitFor(t, func() bool { return len(up.Events()) == 3 })
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("expected the live relay to get every event without waiting on the dead one, took %v", elapsed)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the oldest event waited out the dead relay's retries; the rest were postponed
+	var attempts int
+	for _, entry := range outbox.entries {
+		delivery := entry.Relays[down_url]
+		attempts += delivery.Attempts
+		if delivery.Status != StatusPending || !delivery.NextAttempt.After(time.Now()) {
+			t.Errorf("expected the dead relay's delivery to be postponed, got %+v", delivery)
+		}
+	}
+	if attempts != 1 {
+		t.Errorf("expected one delivery round to the dead relay, got %d", attempts)
+	}
+}
+
+func TestOutboxRejectsUnsignedEvents(t *testing.T) {
+	outbox := newOutbox(t, t.TempDir(), "ws://127.0.0.1:1")
+	event := signedEvent(t, "tampered")
+	event.Content = "changed"
+	if err := outbox.Enqueue(event); !errors.Is(err, ErrUnsignedEvent) {
+		t.Errorf("expected ErrUnsignedEvent, got %v", err)
+	}
+}
+
+// waitFor polls until the condition holds.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
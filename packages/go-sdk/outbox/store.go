@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Store persists outbox entries so undelivered events survive restarts.
+// Implementations must make Save durable before returning; a SQLite or other
+// database-backed store can be plugged in through this interface.
+type Store interface {
+	// Save creates or replaces the entry for its event id.
+	Save(entry *Entry) error
+
+	// Load returns every stored entry.
+	Load() ([]*Entry, error)
+
+	// Delete removes the entry for an event id. Deleting a missing entry is not an error.
+	Delete(event_id string) error
+}
+
+// FileStore stores each entry as a JSON file named after its event id.
+// Files are written to a temporary name, synced and renamed into place, so a crash
+// leaves either the old or the new entry, never a partial one. The directory is
+// synced after each rename and removal, so the change itself survives a crash.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a store in dir, creating the directory if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Save writes the entry atomically.
+func (s *FileStore) Save(entry *Entry) error {
+	path, err := s.path(entry.Event.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	return s.syncDir()
+}
+
+// Load reads every entry in the directory.
+func (s *FileStore) Load() ([]*Entry, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		entry := &Entry{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return nil, fmt.Errorf("outbox entry %s: %w", file.Name(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Delete removes the entry's file.
+func (s *FileStore) Delete(event_id string) error {
+	path, err := s.path(event_id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return s.syncDir()
+}
+
+// syncDir flushes the directory entries, making renames and removals durable.
+// Windows does not support syncing a directory, so it is skipped there.
+func (s *FileStore) syncDir() error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	dir, err := os.Open(s.dir)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+// path returns the entry file for an event id, refusing ids that are not hex.
+func (s *FileStore) path(event_id string) (string, error) {
+	if decoded, err := hex.DecodeString(event_id); err != nil || len(decoded) != 32 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidEventID, event_id)
+	}
+	return filepath.Join(s.dir, event_id+".json"), nil
+}
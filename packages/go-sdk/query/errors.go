@@ -0,0 +1,8 @@
+package query
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when no relay has the requested event.
+	ErrNotFound = errors.New("event not found")
+)
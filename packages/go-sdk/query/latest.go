@@ -0,0 +1,34 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// latest keeps the newest version of each addressable event, newest first.
+// Versions with the same created_at are resolved by the lowest id, as NIP-01 specifies.
+func latest(events []*nostr.Event) []*nostr.Event {
+	newest := make(map[string]*nostr.Event)
+	for _, event := range events {
+		key := fmt.Sprintf("%d:%s:%s", event.Kind, event.PubKey, event.Tags.GetD())
+		current, ok := newest[key]
+		if !ok || event.CreatedAt > current.CreatedAt ||
+			(event.CreatedAt == current.CreatedAt && event.ID < current.ID) {
+			newest[key] = event
+		}
+	}
+
+	result := make([]*nostr.Event, 0, len(newest))
+	for _, event := range newest {
+		result = append(result, event)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreatedAt != result[j].CreatedAt {
+			return result[i].CreatedAt > result[j].CreatedAt
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
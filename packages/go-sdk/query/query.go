@@ -0,0 +1,248 @@
+// Package query looks up ATTN Protocol entities on relays.
+//
+// Each helper builds the #a, #p, #t or #e filter for its entity, keeps only the
+// latest version of every addressable event and decodes the result into go-core
+// types. Events that fail to decode are skipped.
+package query
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/lifecycle"
+	"github.com/joinnextblock/attn-protocol/go-sdk/nip19"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// Querier runs one-shot queries. *relay.Pool implements it.
+type Querier interface {
+	Query(ctx context.Context, filter nostr.Filter) ([]*nostr.Event, error)
+}
+
+// Client answers ATTN Protocol queries through a Querier.
+type Client struct {
+	querier Querier
+}
+
+// New returns a client querying through querier, usually a connected *relay.Pool.
+func New(querier Querier) *Client {
+	return &Client{querier: querier}
+}
+
+// MatchChain is a MATCH and the confirmations that follow it. Confirmations the
+// match has not reached yet are nil.
+type MatchChain struct {
+	Match                        *core.Match
+	BillboardConfirmation        *core.BillboardConfirmation
+	AttentionConfirmation        *core.AttentionConfirmation
+	MarketplaceConfirmation      *core.MarketplaceConfirmation
+	AttentionPaymentConfirmation *core.AttentionPaymentConfirmation
+
+	// State is the match's position in the confirmation chain.
+	State lifecycle.State
+}
+
+// GetMarketplace returns the latest version of the MARKETPLACE event at a coordinate
+// (38188:pubkey:d or naddr). Returns ErrNotFound if no relay has it.
+func (c *Client) GetMarketplace(ctx context.Context, coordinate string) (*core.Marketplace, error) {
+	parsed, err := parseCoordinate(coordinate, core.KindMarketplace)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := c.querier.Query(ctx, nostr.Filter{
+		Kinds:   []int{core.KindMarketplace},
+		Authors: []string{parsed.Pubkey},
+		Tags:    nostr.TagMap{"d": []string{parsed.DTag}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range latest(events) {
+		if marketplace, err := core.DecodeMarketplace(event); err == nil {
+			return marketplace, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, parsed)
+}
+
+// ListBillboards returns the latest version of every BILLBOARD registered with a
+// marketplace, newest first.
+func (c *Client) ListBillboards(ctx context.Context, marketplace_coordinate string) ([]*core.Billboard, error) {
+	parsed, err := parseCoordinate(marketplace_coordinate, core.KindMarketplace)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := c.querier.Query(ctx, nostr.Filter{
+		Kinds: []int{core.KindBillboard},
+		Tags:  nostr.TagMap{"a": []string{parsed.String()}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var billboards []*core.Billboard
+	for _, event := range latest(events) {
+		if billboard, err := core.DecodeBillboard(event); err == nil {
+			billboards = append(billboards, billboard)
+		}
+	}
+	return billboards, nil
+}
+
+// ListActivePromotions returns the PROMOTION events submitted to a marketplace for
+// a block height, newest first.
+func (c *Client) ListActivePromotions(ctx context.Context, marketplace_coordinate string, block_height int64) ([]*core.Promotion, error) {
+	parsed, err := parseCoordinate(marketplace_coordinate, core.KindMarketplace)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := c.querier.Query(ctx, nostr.Filter{
+		Kinds: []int{core.KindPromotion},
+		Tags: nostr.TagMap{
+			"a": []string{parsed.String()},
+			"t": []string{strconv.FormatInt(block_height, 10)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var promotions []*core.Promotion
+	for _, event := range latest(events) {
+		if promotion, err := core.DecodePromotion(event); err == nil {
+			promotions = append(promotions, promotion)
+		}
+	}
+	return promotions, nil
+}
+
+// ListMyMatches returns the latest version of every MATCH naming a pubkey (hex or
+// npub) as a party, newest first.
+func (c *Client) ListMyMatches(ctx context.Context, pubkey string) ([]*core.Match, error) {
+	pubkey, err := nip19.DecodePublicKey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := c.querier.Query(ctx, nostr.Filter{
+		Kinds: []int{core.KindMatch},
+		Tags:  nostr.TagMap{"p": []string{pubkey}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*core.Match
+	for _, event := range latest(events) {
+		if match, err := core.DecodeMatch(event); err == nil {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+// GetMatchChain returns a MATCH (by event id, hex or nevent) and the confirmations
+// referencing it. Confirmations are checked with the lifecycle package, so events
+// published by the wrong party or out of order are left out.
+// Returns ErrNotFound if no relay has the match.
+func (c *Client) GetMatchChain(ctx context.Context, match_event_id string) (*MatchChain, error) {
+	match_event_id, err := nip19.DecodeEventID(match_event_id)
+	if err != nil {
+		return nil, err
+	}
+
+	match_events, err := c.querier.Query(ctx, nostr.Filter{
+		IDs:   []string{match_event_id},
+		Kinds: []int{core.KindMatch},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(match_events) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, match_event_id)
+	}
+	match, err := core.DecodeMatch(match_events[0])
+	if err != nil {
+		return nil, err
+	}
+
+	progress := lifecycle.New()
+	if _, err := progress.Apply(match.Event); err != nil {
+		return nil, err
+	}
+	chain := &MatchChain{Match: match, State: progress.State()}
+
+	events, err := c.querier.Query(ctx, nostr.Filter{
+		Kinds: []int{
+			core.KindBillboardConfirmation,
+			core.KindAttentionConfirmation,
+			core.KindMarketplaceConfirmation,
+			core.KindAttentionPaymentConfirmation,
+		},
+		Tags: nostr.TagMap{"e": []string{match_event_id}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	by_kind := make(map[int][]*nostr.Event)
+	for _, event := range latest(events) {
+		by_kind[event.Kind] = append(by_kind[event.Kind], event)
+	}
+
+	// Apply in chain order, taking the newest candidate the lifecycle accepts
+	for _, event := range by_kind[core.KindBillboardConfirmation] {
+		if confirmation, err := core.DecodeBillboardConfirmation(event); err == nil && apply(progress, event) {
+			chain.BillboardConfirmation = confirmation
+			break
+		}
+	}
+	for _, event := range by_kind[core.KindAttentionConfirmation] {
+		if confirmation, err := core.DecodeAttentionConfirmation(event); err == nil && apply(progress, event) {
+			chain.AttentionConfirmation = confirmation
+			break
+		}
+	}
+	for _, event := range by_kind[core.KindMarketplaceConfirmation] {
+		if confirmation, err := core.DecodeMarketplaceConfirmation(event); err == nil && apply(progress, event) {
+			chain.MarketplaceConfirmation = confirmation
+			break
+		}
+	}
+	for _, event := range by_kind[core.KindAttentionPaymentConfirmation] {
+		if confirmation, err := core.DecodeAttentionPaymentConfirmation(event); err == nil && apply(progress, event) {
+			chain.AttentionPaymentConfirmation = confirmation
+			break
+		}
+	}
+
+	chain.State = progress.State()
+	return chain, nil
+}
+
+// apply reports whether the lifecycle accepts the event.
+func apply(progress *lifecycle.Match, event *nostr.Event) bool {
+	_, err := progress.Apply(event)
+	return err == nil
+}
+
+// parseCoordinate resolves a hex or naddr coordinate and checks its kind.
+func parseCoordinate(coordinate string, kind int) (core.Coordinate, error) {
+	coordinate, err := nip19.DecodeCoordinate(coordinate)
+	if err != nil {
+		return core.Coordinate{}, err
+	}
+	parsed, err := core.ParseCoordinate(coordinate)
+	if err != nil {
+		return core.Coordinate{}, err
+	}
+	if parsed.Kind != kind {
+		return core.Coordinate{}, fmt.Errorf("%w: expected %d, got %d", core.ErrKindMismatch, kind, parsed.Kind)
+	}
+	return parsed, nil
+}
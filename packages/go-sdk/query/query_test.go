@@ -0,0 +1,234 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-core/lifecycle"
+	"github.com/joinnextblock/attn-protocol/go-sdk/events"
+	"github.com/joinnextblock/attn-protocol/go-sdk/nip19"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relay"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relaytest"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// Party keys used across the tests.
+var (
+	marketplaceKey = strings.Repeat("01", 32)
+	billboardKey   = strings.Repeat("02", 32)
+	promotionKey   = strings.Repeat("03", 32)
+	attentionKey   = strings.Repeat("04", 32)
+)
+
+// fixture is a relay with a connected pool and query client.
+type fixture struct {
+	t      *testing.T
+	pool   *relay.Pool
+	client *Client
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	server := relaytest.NewServer()
+	t.Cleanup(server.Close)
+
+	pool, err := relay.NewPool([]string{server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	if err := pool.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return &fixture{t: t, pool: pool, client: New(pool)}
+}
+
+// publish stores the event, first backdating it by age seconds.
+func (f *fixture) publish(private_key string, event *nostr.Event, err error, age nostr.Timestamp) *nostr.Event {
+	f.t.Helper()
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	if age > 0 {
+		signer, _ := core.NewKeySigner(private_key)
+		event.CreatedAt -= age
+		if err := signer.SignEvent(context.Background(), event); err != nil {
+			f.t.Fatal(err)
+		}
+	}
+	if _, err := f.pool.Publish(context.Background(), event); err != nil {
+		f.t.Fatal(err)
+	}
+	return event
+}
+
+// pubkey returns the public key for a private key.
+func pubkey(private_key string) string {
+	pubkey, _ := nostr.GetPublicKey(private_key)
+	return pubkey
+}
+
+// coordinate returns the coordinate of a protocol event.
+func coordinate(kind int, private_key, id string) string {
+	return core.NewCoordinate(kind, pubkey(private_key), core.NewDTag(kind, id)).String()
+}
+
+func TestGetMarketplaceAndBillboardsPickLatest(t *testing.T) {
+	f := newFixture(t)
+	marketplace_coordinate := coordinate(core.KindMarketplace, marketplaceKey, "city")
+
+	event, err := events.CreateMarketplace(marketplaceKey, events.MarketplaceParams{Name: "old", MarketplaceID: "city", BlockHeight: 880000})
+	f.publish(marketplaceKey, event, err, 60)
+	event, err = events.CreateMarketplace(marketplaceKey, events.MarketplaceParams{Name: "new", MarketplaceID: "city", BlockHeight: 880001})
+	f.publish(marketplaceKey, event, err, 0)
+
+	billboards := []struct {
+		id   string
+		name string
+		age  nostr.Timestamp
+	}{
+		{"lobby", "Lobby (old)", 30},
+		{"lobby", "Lobby", 0},
+		{"street", "Street", 0},
+	}
+	for _, billboard := range billboards {
+		event, err = events.CreateBillboard(billboardKey, events.BillboardParams{
+			Name:                  billboard.name,
+			BillboardID:           billboard.id,
+			MarketplaceCoordinate: marketplace_coordinate,
+			BlockHeight:           880001,
+		})
+		f.publish(billboardKey, event, err, billboard.age)
+	}
+
+	naddr, err := nip19.EncodeCoordinate(marketplace_coordinate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	marketplace, err := f.client.GetMarketplace(context.Background(), naddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if marketplace.Data.Name != "new" || marketplace.BlockHeight != 880001 {
+		t.Errorf("expected the latest marketplace version, got %q at %d", marketplace.Data.Name, marketplace.BlockHeight)
+	}
+
+	listed, err := f.client.ListBillboards(context.Background(), marketplace_coordinate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 {
+		t.Fatalf("expected one entry per billboard, got %d", len(listed))
+	}
+	for _, billboard := range listed {
+		if billboard.DTag == core.NewDTag(core.KindBillboard, "lobby").String() && billboard.Data.Name != "Lobby" {
+			t.Errorf("expected the latest lobby version, got %q", billboard.Data.Name)
+		}
+	}
+
+	_, err = f.client.GetMarketplace(context.Background(), coordinate(core.KindMarketplace, marketplaceKey, "missing"))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := f.client.ListBillboards(context.Background(), coordinate(core.KindBillboard, billboardKey, "lobby")); !errors.Is(err, core.ErrKindMismatch) {
+		t.Errorf("expected ErrKindMismatch for a billboard coordinate, got %v", err)
+	}
+}
+
+func TestListActivePromotions(t *testing.T) {
+	f := newFixture(t)
+	marketplace_coordinate := coordinate(core.KindMarketplace, marketplaceKey, "city")
+
+	for i, block_height := range []int64{880000, 880000, 880001} {
+		event, err := events.CreatePromotion(promotionKey, events.PromotionParams{
+			Duration:              30000,
+			Bid:                   5000,
+			MarketplaceCoordinate: marketplace_coordinate,
+			BlockHeight:           block_height,
+			PromotionID:           []string{"a", "b", "c"}[i],
+		})
+		f.publish(promotionKey, event, err, 0)
+	}
+
+	promotions, err := f.client.ListActivePromotions(context.Background(), marketplace_coordinate, 880000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(promotions) != 2 {
+		t.Fatalf("expected two promotions at 880000, got %d", len(promotions))
+	}
+	for _, promotion := range promotions {
+		if promotion.BlockHeight != 880000 || promotion.MarketplaceCoordinate != marketplace_coordinate {
+			t.Errorf("unexpected promotion %s at %d", promotion.Coordinate, promotion.BlockHeight)
+		}
+	}
+}
+
+func TestGetMatchChainAndListMyMatches(t *testing.T) {
+	f := newFixture(t)
+
+	match_event, err := events.CreateMatch(marketplaceKey, events.MatchParams{
+		MatchID:               "m1",
+		BlockHeight:           880000,
+		MarketplaceCoordinate: coordinate(core.KindMarketplace, marketplaceKey, "city"),
+		BillboardCoordinate:   coordinate(core.KindBillboard, billboardKey, "lobby"),
+		PromotionCoordinate:   coordinate(core.KindPromotion, promotionKey, "p1"),
+		AttentionCoordinate:   coordinate(core.KindAttention, attentionKey, "a1"),
+		MarketplacePubkey:     pubkey(marketplaceKey),
+		BillboardPubkey:       pubkey(billboardKey),
+		PromotionPubkey:       pubkey(promotionKey),
+		AttentionPubkey:       pubkey(attentionKey),
+		MarketplaceID:         "city",
+		BillboardID:           "lobby",
+		PromotionID:           "p1",
+		AttentionID:           "a1",
+	})
+	f.publish(marketplaceKey, match_event, err, 0)
+	match, err := core.DecodeMatch(match_event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := events.ConfirmationRefsFromMatch(match)
+
+	event, err := events.CreateBillboardConfirmation(billboardKey, events.BillboardConfirmationParams{ConfirmationRefs: refs, ConfirmationID: "bc1", BlockHeight: 880001})
+	f.publish(billboardKey, event, err, 0)
+
+	// The billboard cannot confirm on the attention owner's behalf
+	event, err = events.CreateAttentionConfirmation(billboardKey, events.AttentionConfirmationParams{ConfirmationRefs: refs, ConfirmationID: "forged", BlockHeight: 880001})
+	f.publish(billboardKey, event, err, 0)
+	attention_confirmation, err := events.CreateAttentionConfirmation(attentionKey, events.AttentionConfirmationParams{ConfirmationRefs: refs, ConfirmationID: "ac1", BlockHeight: 880001})
+	f.publish(attentionKey, attention_confirmation, err, 10)
+
+	nevent, err := nip19.EncodeEvent(nip19.EventPointer{ID: match_event.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := f.client.GetMatchChain(context.Background(), nevent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chain.State != lifecycle.StatePartiesConfirmed {
+		t.Errorf("expected parties_confirmed, got %s", chain.State)
+	}
+	if chain.BillboardConfirmation == nil || chain.AttentionConfirmation == nil || chain.AttentionConfirmation.Event.ID != attention_confirmation.ID {
+		t.Errorf("expected both party confirmations without the forged one, got %+v", chain)
+	}
+	if chain.MarketplaceConfirmation != nil || chain.AttentionPaymentConfirmation != nil {
+		t.Error("expected no marketplace or payment confirmation yet")
+	}
+
+	npub, err := nip19.EncodePublicKey(pubkey(attentionKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches, err := f.client.ListMyMatches(context.Background(), npub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Event.ID != match_event.ID {
+		t.Errorf("expected the attention owner's match, got %d matches", len(matches))
+	}
+}
@@ -0,0 +1,70 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// ErrAuthFailed is returned when the relay refuses the NIP-42 AUTH event.
+var ErrAuthFailed = errors.New("relay authentication failed")
+
+// Authenticate answers a connected relay's NIP-42 challenge with an AUTH event signed
+// by signer, for clients that manage their own go-nostr connections. Returns an error
+// wrapping ErrAuthFailed if signing fails or the relay refuses the event.
+func Authenticate(ctx context.Context, relay *nostr.Relay, signer core.Signer) error {
+	return authenticate(ctx, relay, relay.URL, signer)
+}
+
+// authenticate answers the relay's NIP-42 challenge with an AUTH event (kind 22242)
+// signed by signer. The relay sends its challenge on connect, so it is known by the
+// time the relay asks for authentication.
+func authenticate(ctx context.Context, relay *nostr.Relay, relay_url string, signer core.Signer) error {
+	var sign_err error
+	err := relay.Auth(ctx, func(event *nostr.Event) error {
+		sign_err = signer.SignEvent(ctx, event)
+		return sign_err
+	})
+	if sign_err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrAuthFailed, relay_url, sign_err)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAuthFailed, classifyPublishError(relay_url, err))
+	}
+	return nil
+}
+
+// publishEvent publishes an event on a connected relay. When the relay answers
+// "auth-required:" and a signer is given, it authenticates and publishes once more.
+func publishEvent(ctx context.Context, relay *nostr.Relay, relay_url string, event *nostr.Event, signer core.Signer) error {
+	err := publishOnRelay(ctx, relay, relay_url, event)
+	if signer == nil || !errors.Is(err, ErrAuthRequired) {
+		return err
+	}
+
+	if err := authenticate(ctx, relay, relay_url, signer); err != nil {
+		return err
+	}
+	return publishOnRelay(ctx, relay, relay_url, event)
+}
+
+// publishOnRelay makes a single publish and classifies its outcome.
+func publishOnRelay(ctx context.Context, relay *nostr.Relay, relay_url string, event *nostr.Event) error {
+	err := relay.Publish(ctx, *event)
+
+	// go-nostr reports no error when the connection drops before an OK arrives
+	if err == nil && !relay.IsConnected() {
+		err = errors.New("connection closed before OK")
+	}
+
+	return classifyPublishError(relay_url, err)
+}
+
+// IsAuthRequired reports whether a CLOSED reason asks for NIP-42 authentication.
+func IsAuthRequired(reason string) bool {
+	return strings.HasPrefix(reason, PrefixAuthRequired+":")
+}
@@ -0,0 +1,84 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relaytest"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// failingSigner is a signer that cannot sign, like a remote signer that is offline.
+type failingSigner struct{}
+
+func (failingSigner) GetPublicKey(ctx context.Context) (string, error) {
+	return "", errors.New("signer offline")
+}
+
+func (failingSigner) SignEvent(ctx context.Context, event *nostr.Event) error {
+	return errors.New("signer offline")
+}
+
+func TestPublishToRelayAuthenticates(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	server.RequireAuth()
+
+	event := signedEvent(t, "auth")
+	if _, err := PublishToRelay(context.Background(), event, server.URL); !errors.Is(err, ErrAuthRequired) {
+		t.Fatalf("expected ErrAuthRequired without a signer, got %v", err)
+	}
+
+	signer, _ := core.NewKeySigner(strings.Repeat("02", 32))
+	result, err := PublishToRelayWithSigner(context.Background(), signer, event, server.URL)
+	if err != nil || !result.Success {
+		t.Fatalf("expected publish to succeed after auth, got %v", err)
+	}
+	if len(server.Events()) != 1 {
+		t.Errorf("expected the relay to store the event, got %d", len(server.Events()))
+	}
+
+	_, err = PublishToRelayWithSigner(context.Background(), failingSigner{}, signedEvent(t, "offline"), server.URL)
+	if !errors.Is(err, ErrAuthFailed) || IsTransient(err) {
+		t.Errorf("expected a permanent ErrAuthFailed, got %v", err)
+	}
+}
+
+func TestPoolAuthenticatesPublishAndSubscribe(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	server.RequireAuth()
+
+	signer, _ := core.NewKeySigner(strings.Repeat("02", 32))
+	options := testOptions
+	options.Signer = signer
+	pool, err := NewPoolWithOptions([]string{server.URL}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	if err := pool.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := pool.Subscribe(context.Background(), nostr.Filters{{Kinds: []int{core.KindPromotion}}})
+	defer sub.Close()
+	select {
+	case <-sub.EOSE:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the subscription to authenticate and reach EOSE")
+	}
+
+	event := signedEvent(t, "pool auth")
+	results, err := pool.Publish(context.Background(), event)
+	if err != nil || results.Results[0].Attempts != 1 {
+		t.Fatalf("expected publish to authenticate within one attempt, got %v", err)
+	}
+	if got := nextEvent(t, sub); got.Event.ID != event.ID {
+		t.Errorf("expected the published event, got %s", got.Event.ID)
+	}
+}
@@ -0,0 +1,127 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NIP-01 OK and CLOSED message prefixes.
+const (
+	PrefixDuplicate    = "duplicate"
+	PrefixPoW          = "pow"
+	PrefixBlocked      = "blocked"
+	PrefixRateLimited  = "rate-limited"
+	PrefixInvalid      = "invalid"
+	PrefixRestricted   = "restricted"
+	PrefixAuthRequired = "auth-required"
+	PrefixError        = "error"
+)
+
+var (
+	// ErrDuplicate is returned when the relay already has the event. Publishing treats it as success.
+	ErrDuplicate = errors.New("relay already has the event")
+
+	// ErrPoW is returned when the relay requires more proof of work.
+	ErrPoW = errors.New("relay requires proof of work")
+
+	// ErrBlocked is returned when the relay has blocked the author.
+	ErrBlocked = errors.New("relay blocked the event")
+
+	// ErrRateLimited is returned when the relay is rate limiting the client.
+	ErrRateLimited = errors.New("relay rate limited the event")
+
+	// ErrInvalid is returned when the relay considers the event invalid.
+	ErrInvalid = errors.New("relay rejected the event as invalid")
+
+	// ErrRestricted is returned when the author is not allowed to write to the relay.
+	ErrRestricted = errors.New("relay restricted the event")
+
+	// ErrAuthRequired is returned when the relay requires NIP-42 authentication first.
+	ErrAuthRequired = errors.New("relay requires authentication")
+
+	// ErrRelayError is returned when the relay failed to handle the event.
+	ErrRelayError = errors.New("relay error")
+
+	// ErrRejected is returned when the relay refuses the event without a known prefix.
+	ErrRejected = errors.New("relay rejected the event")
+)
+
+// okPrefixErrors maps machine-readable OK prefixes to their errors.
+var okPrefixErrors = map[string]error{
+	PrefixDuplicate:    ErrDuplicate,
+	PrefixPoW:          ErrPoW,
+	PrefixBlocked:      ErrBlocked,
+	PrefixRateLimited:  ErrRateLimited,
+	PrefixInvalid:      ErrInvalid,
+	PrefixRestricted:   ErrRestricted,
+	PrefixAuthRequired: ErrAuthRequired,
+	PrefixError:        ErrRelayError,
+}
+
+// RejectedError is a relay's refusal of an event, parsed from an OK false message.
+// It unwraps to the error for its prefix, e.g. ErrRateLimited.
+type RejectedError struct {
+	RelayURL string
+	Prefix   string
+	Message  string
+	Err      error
+}
+
+// Error formats the relay, prefix and message.
+func (e *RejectedError) Error() string {
+	if e.RelayURL == "" {
+		return fmt.Sprintf("%s: %s", e.Err.Error(), e.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.RelayURL, e.Err.Error(), e.Message)
+}
+
+// Unwrap returns the error for the prefix.
+func (e *RejectedError) Unwrap() error {
+	return e.Err
+}
+
+// ParseOKReason parses the reason of an OK false message into a *RejectedError.
+// Reasons without a known NIP-01 prefix unwrap to ErrRejected.
+func ParseOKReason(reason string) *RejectedError {
+	rejected := &RejectedError{Message: strings.TrimSpace(reason), Err: ErrRejected}
+	if prefix, message, ok := strings.Cut(reason, ":"); ok {
+		if err, known := okPrefixErrors[strings.TrimSpace(prefix)]; known {
+			rejected.Prefix = strings.TrimSpace(prefix)
+			rejected.Message = strings.TrimSpace(message)
+			rejected.Err = err
+		}
+	}
+	return rejected
+}
+
+// IsTransient returns true if publishing may succeed when retried: rate limits,
+// relay-side errors, timeouts and lost connections.
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, ErrRelayError) ||
+		errors.Is(err, ErrConnectionFailed) ||
+		errors.Is(err, ErrConnectionLost) ||
+		errors.Is(err, ErrPublishTimeout)
+}
+
+// classifyPublishError turns an error from a go-nostr publish into a typed error.
+// go-nostr reports OK false messages as "msg: <reason>".
+func classifyPublishError(relay_url string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if reason, ok := strings.CutPrefix(err.Error(), "msg: "); ok {
+		rejected := ParseOKReason(reason)
+		rejected.RelayURL = relay_url
+		return rejected
+	}
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s: %v", ErrPublishTimeout, relay_url, err)
+	}
+	return fmt.Errorf("%w: %s: %v", ErrConnectionLost, relay_url, err)
+}
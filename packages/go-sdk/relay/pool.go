@@ -0,0 +1,523 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// Pool defaults applied to zero PoolOptions fields.
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff     = 30 * time.Second
+	DefaultPublishTimeout = 7 * time.Second
+	DefaultQueryTimeout   = 7 * time.Second
+)
+
+// PoolOptions configures how a Pool retries publishes and reconnects to relays.
+type PoolOptions struct {
+	// MaxAttempts is the number of publish attempts per relay, including the first.
+	// Only transient failures (see IsTransient) are retried.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry or reconnect. It doubles
+	// after each consecutive failure, up to MaxBackoff. A connection that drops or
+	// refuses a subscription before serving anything counts as a failure, so a relay
+	// that accepts connections but nothing else is not redialed hot.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between retries and reconnects.
+	MaxBackoff time.Duration
+
+	// PublishTimeout bounds the wait for each relay's OK.
+	PublishTimeout time.Duration
+
+	// Signer answers NIP-42 AUTH challenges from relays that require authentication
+	// before accepting events or subscriptions. Without one, such relays refuse with
+	// ErrAuthRequired.
+	Signer core.Signer
+}
+
+// withDefaults fills zero fields with the package defaults.
+func (o PoolOptions) withDefaults() PoolOptions {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultMaxAttempts
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = DefaultInitialBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultMaxBackoff
+	}
+	if o.PublishTimeout <= 0 {
+		o.PublishTimeout = DefaultPublishTimeout
+	}
+	return o
+}
+
+// backoff returns the wait after the given number of consecutive failures.
+func (o PoolOptions) backoff(failures int) time.Duration {
+	wait := o.InitialBackoff
+	for i := 1; i < failures && wait < o.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > o.MaxBackoff {
+		wait = o.MaxBackoff
+	}
+	return wait
+}
+
+// Pool manages connections to multiple Nostr relays.
+// Relays that drop are reconnected on demand, with exponential backoff between
+// failed attempts, and publishes are retried on transient failures.
+type Pool struct {
+	options PoolOptions
+	relays  []*poolRelay
+}
+
+// poolRelay is one relay's connection and reconnect state. mu is never held while
+// waiting out the backoff or dialing, so one down relay does not block the others.
+type poolRelay struct {
+	url string
+
+	mu          sync.Mutex
+	relay       *nostr.Relay
+	failures    int
+	nextAttempt time.Time
+	dialing     chan struct{} // closed when the dial in progress ends, nil if none
+	generation  int           // bumped by close, so a dial it overtook is discarded
+}
+
+// NewPool creates a new relay pool with the given URLs and default options.
+func NewPool(urls []string) (*Pool, error) {
+	return NewPoolWithOptions(urls, PoolOptions{})
+}
+
+// NewPoolWithOptions creates a new relay pool with the given URLs and options.
+func NewPoolWithOptions(urls []string, options PoolOptions) (*Pool, error) {
+	if len(urls) == 0 {
+		return nil, ErrNoRelays
+	}
+
+	relays := make([]*poolRelay, len(urls))
+	for i, url := range urls {
+		relays[i] = &poolRelay{url: url}
+	}
+
+	return &Pool{
+		options: options.withDefaults(),
+		relays:  relays,
+	}, nil
+}
+
+// Connect connects to all relays in the pool.
+// Relays that fail now are retried when next used.
+func (p *Pool) Connect(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, pr := range p.relays {
+		wg.Add(1)
+		go func(pr *poolRelay) {
+			defer wg.Done()
+			pr.connect(ctx, p.options)
+		}(pr)
+	}
+	wg.Wait()
+
+	if p.ConnectedCount() == 0 {
+		return ErrConnectionFailed
+	}
+
+	return nil
+}
+
+// Close closes all relay connections.
+func (p *Pool) Close() {
+	for _, pr := range p.relays {
+		pr.close()
+	}
+}
+
+// Publish publishes an event to every relay in the pool concurrently and reports
+// each relay's outcome. Transient failures are retried with backoff, reconnecting
+// dropped relays; a relay that already has the event counts as a success. Relays
+// that answer "auth-required:" are authenticated with the pool's Signer and the
+// event is published again.
+// Returns an error joining ErrPublishFailed with each relay's error if no relay
+// accepted the event.
+func (p *Pool) Publish(ctx context.Context, event *nostr.Event) (*PublishResults, error) {
+	return p.publish(ctx, event, p.relays)
+}
+
+// PublishToRelays publishes an event to the listed pool relays only, as Publish does.
+// Returns ErrUnknownRelay if a URL is not part of the pool.
+func (p *Pool) PublishToRelays(ctx context.Context, event *nostr.Event, relay_urls []string) (*PublishResults, error) {
+	relays := make([]*poolRelay, 0, len(relay_urls))
+	for _, url := range relay_urls {
+		pr := p.relay(url)
+		if pr == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRelay, url)
+		}
+		relays = append(relays, pr)
+	}
+	if len(relays) == 0 {
+		return nil, ErrNoRelays
+	}
+	return p.publish(ctx, event, relays)
+}
+
+// publish publishes to the given relays concurrently and collects their results.
+func (p *Pool) publish(ctx context.Context, event *nostr.Event, relays []*poolRelay) (*PublishResults, error) {
+	results := &PublishResults{
+		EventID: event.ID,
+		Results: make([]PublishResult, len(relays)),
+	}
+
+	var wg sync.WaitGroup
+	for i, pr := range relays {
+		wg.Add(1)
+		go func(i int, pr *poolRelay) {
+			defer wg.Done()
+			results.Results[i] = p.publishTo(ctx, pr, event)
+		}(i, pr)
+	}
+	wg.Wait()
+
+	for _, result := range results.Results {
+		if result.Success {
+			results.SuccessCount++
+		} else {
+			results.FailureCount++
+		}
+	}
+
+	if results.SuccessCount == 0 {
+		return results, results.failure()
+	}
+
+	return results, nil
+}
+
+// publishTo publishes to one relay, retrying transient failures.
+func (p *Pool) publishTo(ctx context.Context, pr *poolRelay, event *nostr.Event) PublishResult {
+	result := PublishResult{RelayURL: pr.url}
+
+	for {
+		result.Attempts++
+		err := p.publishOnce(ctx, pr, event)
+		if err == nil || errors.Is(err, ErrDuplicate) {
+			result.Success = true
+			result.Error = nil
+			return result
+		}
+		result.Error = err
+
+		if !IsTransient(err) || result.Attempts >= p.options.MaxAttempts {
+			return result
+		}
+		if !sleep(ctx, p.options.backoff(result.Attempts)) {
+			return result
+		}
+	}
+}
+
+// publishOnce makes a single publish attempt, connecting first if needed.
+func (p *Pool) publishOnce(ctx context.Context, pr *poolRelay, event *nostr.Event) error {
+	relay, err := pr.connect(ctx, p.options)
+	if err != nil {
+		return err
+	}
+
+	publish_ctx, cancel := context.WithTimeout(ctx, p.options.PublishTimeout)
+	defer cancel()
+
+	err = publishEvent(publish_ctx, relay, pr.url, event, p.options.Signer)
+	if err == nil || errors.Is(err, ErrDuplicate) {
+		pr.served()
+	} else if !relay.IsConnected() {
+		pr.drop(relay)
+	}
+
+	return err
+}
+
+// Query queries events from every relay in the pool concurrently, reconnecting
+// dropped relays with the pool's backoff, and merges the results. A relay answers
+// once it has sent all its stored matches (EOSE); relays that fail or time out
+// first are skipped. Returns an error joining ErrQueryFailed with each relay's
+// error if no relay answered, so that is never mistaken for no matching events.
+// Without a deadline on ctx, the query gets DefaultQueryTimeout.
+func (p *Pool) Query(ctx context.Context, filter nostr.Filter) ([]*nostr.Event, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultQueryTimeout)
+		defer cancel()
+	}
+
+	relay_events := make([][]*nostr.Event, len(p.relays))
+	relay_errs := make([]error, len(p.relays))
+	var wg sync.WaitGroup
+	for i, pr := range p.relays {
+		wg.Add(1)
+		go func(i int, pr *poolRelay) {
+			defer wg.Done()
+			relay_events[i], relay_errs[i] = p.queryFrom(ctx, pr, filter)
+		}(i, pr)
+	}
+	wg.Wait()
+
+	var events []*nostr.Event
+	seen := make(map[string]bool)
+	errs := []error{ErrQueryFailed}
+	var answered bool
+	for i := range p.relays {
+		if relay_errs[i] != nil {
+			errs = append(errs, relay_errs[i])
+			continue
+		}
+		answered = true
+		for _, event := range relay_events[i] {
+			if !seen[event.ID] {
+				seen[event.ID] = true
+				events = append(events, event)
+			}
+		}
+	}
+
+	if !answered {
+		return nil, errors.Join(errs...)
+	}
+	return events, nil
+}
+
+// queryFrom queries one relay, connecting first if needed.
+func (p *Pool) queryFrom(ctx context.Context, pr *poolRelay, filter nostr.Filter) ([]*nostr.Event, error) {
+	relay, err := pr.connect(ctx, p.options)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := queryRelay(ctx, relay, filter)
+	if err != nil {
+		if !relay.IsConnected() {
+			pr.drop(relay)
+		}
+		return nil, err
+	}
+	pr.served()
+	return events, nil
+}
+
+// queryRelay collects one relay's stored matches, failing unless the relay ends
+// them with EOSE.
+func queryRelay(ctx context.Context, relay *nostr.Relay, filter nostr.Filter) ([]*nostr.Event, error) {
+	sub, err := relay.Subscribe(ctx, nostr.Filters{filter})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrConnectionLost, relay.URL, err)
+	}
+	defer sub.Unsub()
+
+	var events []*nostr.Event
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrConnectionLost, relay.URL)
+			}
+			events = append(events, event)
+		case <-sub.EndOfStoredEvents:
+			return events, nil
+		case reason := <-sub.ClosedReason:
+			return nil, fmt.Errorf("%s refused the query: %s", relay.URL, reason)
+		case <-relay.Context().Done():
+			return nil, fmt.Errorf("%w: %s", ErrConnectionLost, relay.URL)
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%s: %w", relay.URL, ctx.Err())
+		}
+	}
+}
+
+// URLs returns the URLs of the pool's relays.
+func (p *Pool) URLs() []string {
+	urls := make([]string, len(p.relays))
+	for i, pr := range p.relays {
+		urls[i] = pr.url
+	}
+	return urls
+}
+
+// ConnectedCount returns the number of connected relays.
+func (p *Pool) ConnectedCount() int {
+	return len(p.connected())
+}
+
+// relay returns the pool relay with the URL, or nil.
+func (p *Pool) relay(url string) *poolRelay {
+	for _, pr := range p.relays {
+		if pr.url == url {
+			return pr
+		}
+	}
+	return nil
+}
+
+// connected returns the relays with a live connection.
+func (p *Pool) connected() []*nostr.Relay {
+	relays := make([]*nostr.Relay, 0, len(p.relays))
+	for _, pr := range p.relays {
+		pr.mu.Lock()
+		if pr.relay != nil && pr.relay.IsConnected() {
+			relays = append(relays, pr.relay)
+		}
+		pr.mu.Unlock()
+	}
+	return relays
+}
+
+// BackoffUntil returns when the pool will next dial the relay after failed dials, or
+// the zero time if it may dial now or the URL is not part of the pool. Callers with
+// many events for one relay can use it to skip a relay that is down.
+func (p *Pool) BackoffUntil(url string) time.Time {
+	pr := p.relay(url)
+	if pr == nil {
+		return time.Time{}
+	}
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if pr.relay != nil && pr.relay.IsConnected() {
+		return time.Time{}
+	}
+	return pr.nextAttempt
+}
+
+// connect returns the relay's live connection, dialing it if needed. After a failed
+// dial, further dials wait out the backoff so a down relay is not hammered. Only one
+// caller dials at a time; the others wait for its outcome.
+func (pr *poolRelay) connect(ctx context.Context, options PoolOptions) (*nostr.Relay, error) {
+	pr.mu.Lock()
+	for pr.relay == nil || !pr.relay.IsConnected() {
+		if pr.relay != nil {
+			pr.relay.Close()
+			pr.relay = nil
+		}
+		if pr.dialing == nil {
+			return pr.dial(ctx, options)
+		}
+
+		dialing := pr.dialing
+		pr.mu.Unlock()
+		select {
+		case <-dialing:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %s: %v", ErrConnectionFailed, pr.url, ctx.Err())
+		}
+		pr.mu.Lock()
+	}
+	relay := pr.relay
+	pr.mu.Unlock()
+	return relay, nil
+}
+
+// dial waits out the backoff and dials the relay, then installs the connection.
+// It is called with mu held and releases it while waiting and dialing.
+func (pr *poolRelay) dial(ctx context.Context, options PoolOptions) (*nostr.Relay, error) {
+	dialing := make(chan struct{})
+	pr.dialing = dialing
+	generation := pr.generation
+	wait := time.Until(pr.nextAttempt)
+	pr.mu.Unlock()
+
+	var relay *nostr.Relay
+	var err error
+	waited := wait <= 0 || sleep(ctx, wait)
+	if waited {
+		relay, err = nostr.RelayConnect(ctx, pr.url)
+	}
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.dialing = nil
+	close(dialing)
+
+	if !waited {
+		// Giving up on the backoff is not a failed dial
+		return nil, fmt.Errorf("%w: %s: %v", ErrConnectionFailed, pr.url, ctx.Err())
+	}
+	if err != nil {
+		pr.failures++
+		pr.nextAttempt = time.Now().Add(options.backoff(pr.failures))
+		return nil, fmt.Errorf("%w: %s: %v", ErrConnectionFailed, pr.url, err)
+	}
+	if generation != pr.generation {
+		// The pool was closed while dialing
+		relay.Close()
+		return nil, fmt.Errorf("%w: %s: pool closed", ErrConnectionFailed, pr.url)
+	}
+
+	// failures is kept until the connection serves something (see served)
+	pr.relay = relay
+	pr.nextAttempt = time.Time{}
+	return relay, nil
+}
+
+// served resets the backoff once the relay has answered over its connection.
+func (pr *poolRelay) served() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.failures = 0
+}
+
+// fail forgets a connection that failed before serving anything and backs off
+// before the next dial, as after a failed dial.
+func (pr *poolRelay) fail(relay *nostr.Relay, options PoolOptions) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if pr.relay == relay {
+		pr.relay.Close()
+		pr.relay = nil
+	}
+	pr.failures++
+	pr.nextAttempt = time.Now().Add(options.backoff(pr.failures))
+}
+
+// drop forgets a dead connection so the next use reconnects.
+func (pr *poolRelay) drop(relay *nostr.Relay) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if pr.relay == relay {
+		pr.relay.Close()
+		pr.relay = nil
+	}
+}
+
+// close closes the relay's connection and discards a dial in progress.
+func (pr *poolRelay) close() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.generation++
+	if pr.relay != nil {
+		pr.relay.Close()
+		pr.relay = nil
+	}
+}
+
+// sleep waits for the duration, returning false if the context ends first.
+func sleep(ctx context.Context, wait time.Duration) bool {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,282 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relaytest"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// testOptions keeps retries fast.
+var testOptions = PoolOptions{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, PublishTimeout: 2 * time.Second}
+
+// signedEvent returns a fresh signed event.
+func signedEvent(t *testing.T, content string) *nostr.Event {
+	t.Helper()
+	signer, err := core.NewKeySigner(strings.Repeat("01", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &nostr.Event{Kind: core.KindPromotion, CreatedAt: nostr.Now(), Content: content}
+	if err := signer.SignEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+// connectedPool returns a pool connected to the servers.
+func connectedPool(t *testing.T, urls ...string) *Pool {
+	t.Helper()
+	pool, err := NewPoolWithOptions(urls, testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	if err := pool.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func TestParseOKReason(t *testing.T) {
+	tests := []struct {
+		reason  string
+		want    error
+		message string
+	}{
+		{"rate-limited: slow down", ErrRateLimited, "slow down"},
+		{"invalid: event creation date is too far off", ErrInvalid, "event creation date is too far off"},
+		{"auth-required: we only accept events from registered users", ErrAuthRequired, "we only accept events from registered users"},
+		{"duplicate: already have this event", ErrDuplicate, "already have this event"},
+		{"blocked: you are banned", ErrBlocked, "you are banned"},
+		{"pow: difficulty 25 required", ErrPoW, "difficulty 25 required"},
+		{"restricted: not allowed", ErrRestricted, "not allowed"},
+		{"error: could not connect to the database", ErrRelayError, "could not connect to the database"},
+		{"something went wrong", ErrRejected, "something went wrong"},
+		{"custom: prefix", ErrRejected, "custom: prefix"},
+	}
+	for _, tt := range tests {
+		rejected := ParseOKReason(tt.reason)
+		if !errors.Is(rejected, tt.want) || rejected.Message != tt.message {
+			t.Errorf("ParseOKReason(%q) = %v (%q), want %v", tt.reason, rejected.Err, rejected.Message, tt.want)
+		}
+	}
+
+	if !IsTransient(ParseOKReason("rate-limited: later")) || IsTransient(ParseOKReason("invalid: bad")) {
+		t.Error("expected rate limits to be transient and invalid events not")
+	}
+}
+
+func TestPoolPublishRetriesTransientRejections(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+
+	var refusals atomic.Int32
+	server.SetReject(func(event *nostr.Event) string {
+		if refusals.Add(1) <= 2 {
+			return "rate-limited: slow down"
+		}
+		return ""
+	})
+
+	pool := connectedPool(t, server.URL)
+	results, err := pool.Publish(context.Background(), signedEvent(t, "retry"))
+	if err != nil {
+		t.Fatalf("expected publish to succeed after retries, got %v", err)
+	}
+	if results.SuccessCount != 1 || results.Results[0].Attempts != 3 {
+		t.Errorf("expected success on the third attempt, got %+v", results.Results[0])
+	}
+}
+
+func TestPoolPublishDoesNotRetryPermanentRejections(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	server.SetReject(func(event *nostr.Event) string { return "invalid: missing t tag" })
+
+	pool := connectedPool(t, server.URL)
+	results, err := pool.Publish(context.Background(), signedEvent(t, "invalid"))
+	if !errors.Is(err, ErrPublishFailed) || !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrPublishFailed and ErrInvalid, got %v", err)
+	}
+
+	var rejected *RejectedError
+	if !errors.As(results.Results[0].Error, &rejected) || rejected.RelayURL != server.URL || rejected.Message != "missing t tag" {
+		t.Errorf("expected a RejectedError from %s, got %v", server.URL, results.Results[0].Error)
+	}
+	if results.Results[0].Attempts != 1 {
+		t.Errorf("expected a single attempt, got %d", results.Results[0].Attempts)
+	}
+}
+
+func TestPoolPublishTreatsDuplicateAsSuccess(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+
+	pool := connectedPool(t, server.URL)
+	event := signedEvent(t, "duplicate")
+	for i := 0; i < 2; i++ {
+		if _, err := pool.Publish(context.Background(), event); err != nil {
+			t.Fatalf("publish %d failed: %v", i, err)
+		}
+	}
+	if len(server.Events()) != 1 {
+		t.Errorf("expected the relay to store the event once, got %d", len(server.Events()))
+	}
+}
+
+func TestPoolPublishReconnectsAfterFlap(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+
+	pool := connectedPool(t, server.URL)
+	server.DropConnections()
+
+	results, err := pool.Publish(context.Background(), signedEvent(t, "flap"))
+	if err != nil {
+		t.Fatalf("expected publish to reconnect, got %v", err)
+	}
+	if !results.Results[0].Success || pool.ConnectedCount() != 1 {
+		t.Errorf("expected a reconnected relay, got %+v", results.Results[0])
+	}
+}
+
+func TestPoolPublishReportsEachRelay(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	down := relaytest.NewServer()
+	down_url := down.URL
+	down.Close()
+
+	pool := connectedPool(t, server.URL, down_url)
+	results, err := pool.Publish(context.Background(), signedEvent(t, "partial"))
+	if err != nil {
+		t.Fatalf("expected partial success, got %v", err)
+	}
+	if results.SuccessCount != 1 || results.FailureCount != 1 {
+		t.Errorf("expected one success and one failure, got %d/%d", results.SuccessCount, results.FailureCount)
+	}
+	failed := results.Results[1]
+	if failed.RelayURL != down_url || !errors.Is(failed.Error, ErrConnectionFailed) || failed.Attempts != testOptions.MaxAttempts {
+		t.Errorf("expected the down relay to fail to connect after %d attempts, got %+v", testOptions.MaxAttempts, failed)
+	}
+}
+
+func TestPoolDoesNotBlockOnRelayInBackoff(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	down := relaytest.NewServer()
+	down_url := down.URL
+	down.Close()
+
+	pool, err := NewPoolWithOptions([]string{server.URL, down_url}, PoolOptions{InitialBackoff: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	if err := pool.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two callers wait out the down relay's backoff; neither may block the pool
+	ctx, cancel := context.WithCancel(context.Background())
+	var waiting sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		waiting.Add(1)
+		go func() {
+			defer waiting.Done()
+			pool.relay(down_url).connect(ctx, pool.options)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan int)
+	go func() { done <- pool.ConnectedCount() }()
+	select {
+	case count := <-done:
+		if count != 1 {
+			t.Errorf("expected one connected relay, got %d", count)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ConnectedCount blocked behind a relay waiting out its backoff")
+	}
+
+	results, err := pool.PublishToRelays(context.Background(), signedEvent(t, "up"), []string{server.URL})
+	if err != nil || results.SuccessCount != 1 {
+		t.Errorf("expected publish to the live relay, got %+v %v", results, err)
+	}
+
+	cancel()
+	waiting.Wait()
+	if pr := pool.relay(down_url); pr.failures != 1 || pr.dialing != nil {
+		t.Errorf("expected giving up on the backoff not to count as a failure, got %d failures", pr.failures)
+	}
+}
+
+func TestPoolQueryNeedsAnAnsweringRelay(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	refusing := relaytest.NewServer()
+	defer refusing.Close()
+	refusing.HangUpOnSubscribe()
+
+	pool := connectedPool(t, server.URL, refusing.URL)
+	stored := signedEvent(t, "stored")
+	if _, err := pool.PublishToRelays(context.Background(), stored, []string{server.URL}); err != nil {
+		t.Fatal(err)
+	}
+	found, err := pool.Query(context.Background(), nostr.Filter{IDs: []string{stored.ID}})
+	if err != nil || len(found) != 1 {
+		t.Fatalf("expected the answering relay's event, got %d events, %v", len(found), err)
+	}
+
+	only_refusing := connectedPool(t, refusing.URL)
+	if found, err := only_refusing.Query(context.Background(), nostr.Filter{IDs: []string{stored.ID}}); !errors.Is(err, ErrQueryFailed) {
+		t.Errorf("expected ErrQueryFailed when no relay answers, got %d events, %v", len(found), err)
+	}
+}
+
+func TestPoolQueryReconnectsAfterFlap(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+
+	pool := connectedPool(t, server.URL)
+	stored := signedEvent(t, "stored")
+	if _, err := pool.Publish(context.Background(), stored); err != nil {
+		t.Fatal(err)
+	}
+	server.DropConnections()
+
+	found, err := pool.Query(context.Background(), nostr.Filter{IDs: []string{stored.ID}})
+	if err != nil || len(found) != 1 {
+		t.Fatalf("expected query to reconnect, got %d events, %v", len(found), err)
+	}
+}
+
+func TestPoolQueryDoesNotWaitForHungRelay(t *testing.T) {
+	hung := relaytest.NewServer()
+	defer hung.Close()
+	hung.IgnoreSubscriptions()
+	server := relaytest.NewServer()
+	defer server.Close()
+
+	pool := connectedPool(t, hung.URL, server.URL)
+	stored := signedEvent(t, "stored")
+	if _, err := pool.Publish(context.Background(), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	found, err := pool.Query(ctx, nostr.Filter{IDs: []string{stored.ID}})
+	if err != nil || len(found) != 1 {
+		t.Fatalf("expected the live relay's event despite the hung relay, got %d events, %v", len(found), err)
+	}
+}
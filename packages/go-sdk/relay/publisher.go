@@ -3,7 +3,9 @@ package relay
 import (
 	"context"
 	"errors"
+	"fmt"
 
+	"github.com/joinnextblock/attn-protocol/go-core"
 	"github.com/nbd-wtf/go-nostr"
 )
 
@@ -16,13 +18,27 @@ var (
 
 	// ErrConnectionFailed is returned when relay connection fails.
 	ErrConnectionFailed = errors.New("failed to connect to relay")
+
+	// ErrConnectionLost is returned when the connection drops before the relay answers.
+	ErrConnectionLost = errors.New("relay connection lost")
+
+	// ErrPublishTimeout is returned when the relay does not answer a publish in time.
+	ErrPublishTimeout = errors.New("timed out waiting for relay OK")
+
+	// ErrUnknownRelay is returned when a relay URL is not part of the pool.
+	ErrUnknownRelay = errors.New("relay is not in the pool")
+
+	// ErrQueryFailed is returned when no relay answers a query with its stored events.
+	ErrQueryFailed = errors.New("no relay answered the query")
 )
 
 // PublishResult represents the result of publishing an event to a relay.
+// Error is a *RejectedError when the relay refused the event.
 type PublishResult struct {
 	RelayURL string
 	Success  bool
 	Error    error
+	Attempts int
 }
 
 // PublishResults represents the results of publishing to multiple relays.
@@ -35,22 +51,31 @@ type PublishResults struct {
 
 // PublishToRelay publishes an event to a single relay.
 func PublishToRelay(ctx context.Context, event *nostr.Event, relay_url string) (*PublishResult, error) {
+	return PublishToRelayWithSigner(ctx, nil, event, relay_url)
+}
+
+// PublishToRelayWithSigner publishes an event to a single relay, answering the relay's
+// NIP-42 AUTH challenge with signer if it requires authentication.
+func PublishToRelayWithSigner(ctx context.Context, signer core.Signer, event *nostr.Event, relay_url string) (*PublishResult, error) {
 	relay, err := nostr.RelayConnect(ctx, relay_url)
 	if err != nil {
+		err = fmt.Errorf("%w: %s: %v", ErrConnectionFailed, relay_url, err)
 		return &PublishResult{
 			RelayURL: relay_url,
 			Success:  false,
 			Error:    err,
+			Attempts: 1,
 		}, err
 	}
 	defer relay.Close()
 
-	err = relay.Publish(ctx, *event)
-	if err != nil {
+	err = publishEvent(ctx, relay, relay_url, event, signer)
+	if err != nil && !errors.Is(err, ErrDuplicate) {
 		return &PublishResult{
 			RelayURL: relay_url,
 			Success:  false,
 			Error:    err,
+			Attempts: 1,
 		}, err
 	}
 
@@ -58,11 +83,18 @@ func PublishToRelay(ctx context.Context, event *nostr.Event, relay_url string) (
 		RelayURL: relay_url,
 		Success:  true,
 		Error:    nil,
+		Attempts: 1,
 	}, nil
 }
 
 // PublishToMultiple publishes an event to multiple relays.
 func PublishToMultiple(ctx context.Context, event *nostr.Event, relay_urls []string) (*PublishResults, error) {
+	return PublishToMultipleWithSigner(ctx, nil, event, relay_urls)
+}
+
+// PublishToMultipleWithSigner publishes an event to multiple relays, authenticating
+// with signer to relays that require NIP-42 authentication.
+func PublishToMultipleWithSigner(ctx context.Context, signer core.Signer, event *nostr.Event, relay_urls []string) (*PublishResults, error) {
 	if len(relay_urls) == 0 {
 		return nil, ErrNoRelays
 	}
@@ -73,7 +105,7 @@ func PublishToMultiple(ctx context.Context, event *nostr.Event, relay_urls []str
 	}
 
 	for _, url := range relay_urls {
-		result, _ := PublishToRelay(ctx, event, url)
+		result, _ := PublishToRelayWithSigner(ctx, signer, event, url)
 		results.Results = append(results.Results, *result)
 
 		if result.Success {
@@ -84,103 +116,20 @@ func PublishToMultiple(ctx context.Context, event *nostr.Event, relay_urls []str
 	}
 
 	if results.SuccessCount == 0 {
-		return results, ErrPublishFailed
+		return results, results.failure()
 	}
 
 	return results, nil
 }
 
-// Pool manages connections to multiple Nostr relays.
-type Pool struct {
-	urls   []string
-	relays []*nostr.Relay
-}
-
-// NewPool creates a new relay pool with the given URLs.
-func NewPool(urls []string) (*Pool, error) {
-	if len(urls) == 0 {
-		return nil, ErrNoRelays
-	}
-
-	return &Pool{
-		urls:   urls,
-		relays: make([]*nostr.Relay, 0),
-	}, nil
-}
-
-// Connect connects to all relays in the pool.
-func (p *Pool) Connect(ctx context.Context) error {
-	for _, url := range p.urls {
-		relay, err := nostr.RelayConnect(ctx, url)
-		if err != nil {
-			// Continue trying other relays
-			continue
-		}
-		p.relays = append(p.relays, relay)
-	}
-
-	if len(p.relays) == 0 {
-		return ErrConnectionFailed
-	}
-
-	return nil
-}
-
-// Close closes all relay connections.
-func (p *Pool) Close() {
-	for _, relay := range p.relays {
-		relay.Close()
-	}
-	p.relays = nil
-}
-
-// Publish publishes an event to all connected relays.
-// Returns nil if at least one relay accepts the event.
-func (p *Pool) Publish(ctx context.Context, event *nostr.Event) error {
-	if len(p.relays) == 0 {
-		return ErrNoRelays
-	}
-
-	var success bool
-	for _, relay := range p.relays {
-		if err := relay.Publish(ctx, *event); err == nil {
-			success = true
-		}
-	}
-
-	if !success {
-		return ErrPublishFailed
-	}
-
-	return nil
-}
-
-// Query queries events from all connected relays.
-func (p *Pool) Query(ctx context.Context, filter nostr.Filter) ([]*nostr.Event, error) {
-	if len(p.relays) == 0 {
-		return nil, ErrNoRelays
-	}
-
-	var events []*nostr.Event
-	seen := make(map[string]bool)
-
-	for _, relay := range p.relays {
-		relay_events, err := relay.QuerySync(ctx, filter)
-		if err != nil {
-			continue
-		}
-		for _, event := range relay_events {
-			if !seen[event.ID] {
-				seen[event.ID] = true
-				events = append(events, event)
-			}
+// failure returns ErrPublishFailed joined with each relay's error, so callers can
+// check for a specific refusal with errors.Is.
+func (r *PublishResults) failure() error {
+	errs := []error{ErrPublishFailed}
+	for _, result := range r.Results {
+		if result.Error != nil {
+			errs = append(errs, result.Error)
 		}
 	}
-
-	return events, nil
-}
-
-// ConnectedCount returns the number of connected relays.
-func (p *Pool) ConnectedCount() int {
-	return len(p.relays)
+	return errors.Join(errs...)
 }
@@ -0,0 +1,248 @@
+package relay
+
+import (
+	"context"
+	"sync"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// DefaultDedupSize is the number of event ids a subscription remembers to drop
+// duplicates when SubscribeOptions.DedupSize is zero.
+const DefaultDedupSize = 10000
+
+// SubscribeOptions configures a pool subscription.
+type SubscribeOptions struct {
+	// Decode decodes each event into its go-core typed form (see core.Decode).
+	Decode bool
+
+	// DedupSize is the number of most recently delivered event ids remembered to
+	// drop duplicates, bounding the memory of long-lived subscriptions. Defaults to
+	// DefaultDedupSize.
+	DedupSize int
+}
+
+// SubscriptionEvent is an event delivered by a pool subscription.
+type SubscriptionEvent struct {
+	// Event is the raw Nostr event.
+	Event *nostr.Event
+
+	// RelayURL is the relay that delivered the event first.
+	RelayURL string
+
+	// Decoded is the typed event, set when SubscribeOptions.Decode is true and
+	// decoding succeeded. Switch on its concrete type, e.g. *core.Match.
+	Decoded core.DecodedEvent
+
+	// DecodeError is the decoding error, e.g. core.ErrUnsupportedKind for events
+	// that are not ATTN Protocol events.
+	DecodeError error
+}
+
+// Subscription is a set of filters subscribed on every relay in a pool.
+// Events from all relays are merged into one stream without duplicates.
+type Subscription struct {
+	// Events delivers each matching event once, as long as it is among the last
+	// DedupSize events delivered; an older event a relay replays after a reconnect
+	// is delivered again. It is closed when the subscription ends.
+	Events <-chan SubscriptionEvent
+
+	// EOSE receives a relay's URL once that relay has sent all its stored events.
+	// It is buffered for every relay in the pool, so it need not be drained.
+	EOSE <-chan string
+
+	events  chan SubscriptionEvent
+	eose    chan string
+	options SubscribeOptions
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu   sync.Mutex
+	seen *recentIDs
+}
+
+// recentIDs is a set of the most recently added event ids, up to a fixed size.
+type recentIDs struct {
+	ids   map[string]struct{}
+	order []string
+	next  int
+}
+
+// newRecentIDs returns an empty set remembering up to size ids.
+func newRecentIDs(size int) *recentIDs {
+	return &recentIDs{ids: make(map[string]struct{}), order: make([]string, 0, size)}
+}
+
+// add adds id, forgetting the oldest id once the set is full. Returns false if id
+// was already in the set.
+func (r *recentIDs) add(id string) bool {
+	if _, ok := r.ids[id]; ok {
+		return false
+	}
+	if len(r.order) < cap(r.order) {
+		r.order = append(r.order, id)
+	} else {
+		delete(r.ids, r.order[r.next])
+		r.order[r.next] = id
+		r.next = (r.next + 1) % len(r.order)
+	}
+	r.ids[id] = struct{}{}
+	return true
+}
+
+// Subscribe subscribes to the filters on every relay in the pool.
+func (p *Pool) Subscribe(ctx context.Context, filters nostr.Filters) *Subscription {
+	return p.SubscribeWithOptions(ctx, filters, SubscribeOptions{})
+}
+
+// SubscribeWithOptions subscribes to the filters on every relay in the pool.
+// Relays that drop are reconnected with the pool's backoff and resubscribed.
+// A relay that answers "auth-required:" is authenticated with the pool's Signer
+// and resubscribed; a relay that otherwise refuses with CLOSED is left out.
+// The subscription ends when ctx is done or Close is called.
+func (p *Pool) SubscribeWithOptions(ctx context.Context, filters nostr.Filters, options SubscribeOptions) *Subscription {
+	if options.DedupSize <= 0 {
+		options.DedupSize = DefaultDedupSize
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Subscription{
+		events:  make(chan SubscriptionEvent),
+		eose:    make(chan string, len(p.relays)),
+		options: options,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		seen:    newRecentIDs(options.DedupSize),
+	}
+	s.Events = s.events
+	s.EOSE = s.eose
+
+	var wg sync.WaitGroup
+	for _, pr := range p.relays {
+		wg.Add(1)
+		go func(pr *poolRelay) {
+			defer wg.Done()
+			s.run(ctx, p.options, pr, filters)
+		}(pr)
+	}
+
+	go func() {
+		wg.Wait()
+		close(s.events)
+		close(s.done)
+	}()
+
+	return s
+}
+
+// Close ends the subscription and waits for Events to be closed.
+func (s *Subscription) Close() {
+	s.cancel()
+	<-s.done
+}
+
+// run keeps one relay subscribed until the subscription ends.
+func (s *Subscription) run(ctx context.Context, options PoolOptions, pr *poolRelay, filters nostr.Filters) {
+	var eosed bool
+	var authed *nostr.Relay
+	for ctx.Err() == nil {
+		// connect waits out the backoff after a failed dial
+		relay, err := pr.connect(ctx, options)
+		if err != nil {
+			continue
+		}
+
+		sub, err := relay.Subscribe(ctx, filters)
+		if err != nil {
+			pr.fail(relay, options)
+			continue
+		}
+
+		served, closed, reason := s.consume(ctx, pr, sub, &eosed)
+		if closed {
+			// Authenticate once per connection, then subscribe again
+			if !IsAuthRequired(reason) || options.Signer == nil || authed == relay {
+				return
+			}
+			if err := authenticate(ctx, relay, pr.url, options.Signer); err != nil {
+				return
+			}
+			authed = relay
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !served {
+			pr.fail(relay, options)
+		} else if !relay.IsConnected() {
+			pr.drop(relay)
+		}
+	}
+}
+
+// consume forwards one relay subscription's events until it ends. Reports whether
+// the relay served the subscription, with an event or EOSE, and returns true and
+// the reason if the relay refused it with CLOSED.
+func (s *Subscription) consume(ctx context.Context, pr *poolRelay, sub *nostr.Subscription, eosed *bool) (served bool, closed bool, reason string) {
+	defer sub.Unsub()
+
+	serve := func() {
+		if !served {
+			served = true
+			pr.served()
+		}
+	}
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				return served, false, ""
+			}
+			serve()
+			if !s.deliver(ctx, pr.url, event) {
+				return served, false, ""
+			}
+		case <-sub.EndOfStoredEvents:
+			serve()
+			// Only the first EOSE is reported; later ones follow reconnects
+			if !*eosed {
+				*eosed = true
+				s.eose <- pr.url
+			}
+		case reason := <-sub.ClosedReason:
+			return served, true, reason
+		case <-ctx.Done():
+			return served, false, ""
+		}
+	}
+}
+
+// deliver sends an event unless another relay already delivered it.
+// Returns false if the subscription ended first.
+func (s *Subscription) deliver(ctx context.Context, url string, event *nostr.Event) bool {
+	s.mu.Lock()
+	added := s.seen.add(event.ID)
+	s.mu.Unlock()
+	if !added {
+		return true
+	}
+
+	delivered := SubscriptionEvent{Event: event, RelayURL: url}
+	if s.options.Decode {
+		decoded, err := core.Decode(event)
+		if err != nil {
+			delivered.DecodeError = err
+		} else {
+			delivered.Decoded = decoded
+		}
+	}
+
+	select {
+	case s.events <- delivered:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,201 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/events"
+	"github.com/joinnextblock/attn-protocol/go-sdk/relaytest"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// nextEvent waits for the subscription's next event.
+func nextEvent(t *testing.T, sub *Subscription) SubscriptionEvent {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events:
+		if !ok {
+			t.Fatal("subscription closed")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return SubscriptionEvent{}
+}
+
+func TestPoolSubscribeMergesRelays(t *testing.T) {
+	first := relaytest.NewServer()
+	defer first.Close()
+	second := relaytest.NewServer()
+	defer second.Close()
+
+	pool := connectedPool(t, first.URL, second.URL)
+	stored := signedEvent(t, "stored")
+	if _, err := pool.Publish(context.Background(), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := pool.Subscribe(context.Background(), nostr.Filters{{Kinds: []int{core.KindPromotion}}})
+	defer sub.Close()
+
+	if got := nextEvent(t, sub); got.Event.ID != stored.ID {
+		t.Fatalf("expected the stored event, got %s", got.Event.ID)
+	}
+	eosed := map[string]bool{}
+	for len(eosed) < 2 {
+		select {
+		case url := <-sub.EOSE:
+			eosed[url] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected EOSE from both relays, got %v", eosed)
+		}
+	}
+	if !eosed[first.URL] || !eosed[second.URL] {
+		t.Errorf("expected EOSE from each relay, got %v", eosed)
+	}
+
+	live := signedEvent(t, "live")
+	if _, err := pool.Publish(context.Background(), live); err != nil {
+		t.Fatal(err)
+	}
+	if got := nextEvent(t, sub); got.Event.ID != live.ID {
+		t.Fatalf("expected the live event, got %s", got.Event.ID)
+	}
+
+	// Both relays stored both events; each must be delivered once
+	select {
+	case event := <-sub.Events:
+		t.Errorf("expected no duplicates, got %s from %s", event.Event.ID, event.RelayURL)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestPoolSubscribeDecodes(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+
+	pool := connectedPool(t, server.URL)
+	promotion, err := events.CreatePromotion(strings.Repeat("01", 32), events.PromotionParams{
+		Duration:              30000,
+		Bid:                   5000,
+		EventID:               strings.Repeat("ab", 32),
+		MarketplaceCoordinate: "38188:" + strings.Repeat("cd", 32) + ":marketplace",
+		BlockHeight:           880000,
+		PromotionID:           "promotion-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Publish(context.Background(), promotion); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := pool.SubscribeWithOptions(context.Background(), nostr.Filters{{Kinds: []int{core.KindPromotion, 1}}}, SubscribeOptions{Decode: true})
+	defer sub.Close()
+
+	got := nextEvent(t, sub)
+	decoded, ok := got.Decoded.(*core.Promotion)
+	if !ok || got.DecodeError != nil {
+		t.Fatalf("expected a *core.Promotion, got %T (%v)", got.Decoded, got.DecodeError)
+	}
+	if decoded.Header().BlockHeight != 880000 {
+		t.Errorf("expected block height 880000, got %d", decoded.Header().BlockHeight)
+	}
+
+	signer, _ := core.NewKeySigner(strings.Repeat("01", 32))
+	note := &nostr.Event{Kind: 1, CreatedAt: nostr.Now(), Content: "note"}
+	if err := signer.SignEvent(context.Background(), note); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Publish(context.Background(), note); err != nil {
+		t.Fatal(err)
+	}
+	got = nextEvent(t, sub)
+	if got.Decoded != nil || !errors.Is(got.DecodeError, core.ErrUnsupportedKind) {
+		t.Errorf("expected ErrUnsupportedKind for a kind 1 note, got %T (%v)", got.Decoded, got.DecodeError)
+	}
+}
+
+func TestPoolSubscribeResubscribesAfterFlap(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+
+	pool := connectedPool(t, server.URL)
+	sub := pool.Subscribe(context.Background(), nostr.Filters{{Kinds: []int{core.KindPromotion}}})
+
+	select {
+	case <-sub.EOSE:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for EOSE")
+	}
+	server.DropConnections()
+
+	// Publish until the resubscribed relay forwards a live event
+	deadline := time.After(5 * time.Second)
+	for i := 0; ; i++ {
+		event := signedEvent(t, fmt.Sprintf("after flap %d", i))
+		pool.Publish(context.Background(), event)
+		select {
+		case got := <-sub.Events:
+			if got.RelayURL != server.URL {
+				t.Errorf("expected the event from %s, got %s", server.URL, got.RelayURL)
+			}
+			sub.Close()
+			if _, ok := <-sub.Events; ok {
+				t.Error("expected Events to be closed")
+			}
+			return
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("expected the subscription to recover after the relay dropped")
+		}
+	}
+}
+
+func TestPoolSubscribeBacksOffRefusingRelay(t *testing.T) {
+	server := relaytest.NewServer()
+	defer server.Close()
+	server.HangUpOnSubscribe()
+
+	pool, err := NewPoolWithOptions([]string{server.URL}, PoolOptions{InitialBackoff: 50 * time.Millisecond, MaxBackoff: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	if err := pool.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := pool.Subscribe(context.Background(), nostr.Filters{{Kinds: []int{core.KindPromotion}}})
+	time.Sleep(700 * time.Millisecond)
+	sub.Close()
+
+	// Backing off 50, 100, 200 and 400ms allows about five connections
+	if connections := server.Connections(); connections > 8 {
+		t.Errorf("expected the pool to back off a relay failing every subscription, got %d connections", connections)
+	}
+}
+
+func TestRecentIDsForgetsOldest(t *testing.T) {
+	seen := newRecentIDs(2)
+	for _, id := range []string{"a", "b", "c"} {
+		if !seen.add(id) {
+			t.Errorf("expected %s to be new", id)
+		}
+	}
+	if seen.add("c") || seen.add("b") {
+		t.Error("expected the two most recent ids to be remembered")
+	}
+	if !seen.add("a") {
+		t.Error("expected the oldest id to be forgotten")
+	}
+	if len(seen.ids) != 2 {
+		t.Errorf("expected the set to stay at 2 ids, got %d", len(seen.ids))
+	}
+}
@@ -0,0 +1,294 @@
+// Package relaytest provides an in-process Nostr relay for tests.
+//
+// The relay speaks enough of NIP-01 for go-nostr clients: it stores published
+// events after checking their signatures, answers REQ with stored matches and an
+// EOSE, and forwards new events to open subscriptions. It can also require
+// NIP-42 authentication before accepting events or subscriptions.
+package relaytest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/coder/websocket"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// Server is an in-process relay listening on a local websocket URL.
+type Server struct {
+	// URL is the relay's ws:// URL.
+	URL string
+
+	server *httptest.Server
+
+	mu       sync.Mutex
+	events   []*nostr.Event
+	conns    map[*conn]struct{}
+	reject   func(event *nostr.Event) string
+	accepted int
+
+	requireAuth bool
+	hangUpOnReq bool
+	ignoreReqs  bool
+}
+
+// conn is one client connection and its open subscriptions.
+type conn struct {
+	ws   *websocket.Conn
+	mu   sync.Mutex
+	subs map[string]nostr.Filters
+
+	challenge string
+	authed    string
+}
+
+// NewServer starts a relay. Close it when the test is done.
+func NewServer() *Server {
+	s := &Server{conns: make(map[*conn]struct{})}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = "ws://" + strings.TrimPrefix(s.server.URL, "http://")
+	return s
+}
+
+// Close disconnects every client and stops the relay.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for c := range s.conns {
+		c.ws.CloseNow()
+	}
+	s.mu.Unlock()
+	s.server.Close()
+}
+
+// SetReject installs a hook deciding whether to refuse a published event. A non-empty
+// return value is sent as the OK false reason, e.g. "rate-limited: slow down".
+func (s *Server) SetReject(reject func(event *nostr.Event) string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reject = reject
+}
+
+// RequireAuth makes the relay send a NIP-42 challenge to new connections and refuse
+// events and subscriptions with "auth-required:" until the client authenticates.
+func (s *Server) RequireAuth() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requireAuth = true
+}
+
+// HangUpOnSubscribe makes the relay close the connection of any client that sends
+// a REQ, as a relay taking connections but failing every subscription would.
+func (s *Server) HangUpOnSubscribe() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hangUpOnReq = true
+}
+
+// IgnoreSubscriptions makes the relay accept REQs but never answer them, as a
+// hung relay would.
+func (s *Server) IgnoreSubscriptions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ignoreReqs = true
+}
+
+// Connections returns the number of client connections the relay has accepted.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+// DropConnections closes every client connection, as a relay restart would.
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.conns {
+		c.ws.CloseNow()
+	}
+}
+
+// Events returns the events the relay has accepted, oldest first.
+func (s *Server) Events() []*nostr.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*nostr.Event(nil), s.events...)
+}
+
+// handle serves one websocket connection until the client goes away.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Accept(w, r, &websocket.AcceptOptions{InsecureSkipVerify: true})
+	if err != nil {
+		return
+	}
+	ws.SetReadLimit(1 << 20)
+
+	c := &conn{ws: ws, subs: make(map[string]nostr.Filters)}
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	s.accepted++
+	require_auth := s.requireAuth
+	hang_up_on_req := s.hangUpOnReq
+	ignore_reqs := s.ignoreReqs
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		ws.CloseNow()
+	}()
+
+	ctx := r.Context()
+	if require_auth {
+		c.challenge = randomChallenge()
+		c.send(ctx, nostr.AuthEnvelope{Challenge: &c.challenge})
+	}
+
+	for {
+		_, data, err := ws.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		switch envelope := nostr.ParseMessage(string(data)).(type) {
+		case *nostr.EventEnvelope:
+			if require_auth && !c.isAuthed() {
+				c.send(ctx, nostr.OKEnvelope{EventID: envelope.Event.ID, OK: false, Reason: "auth-required: authenticate to publish"})
+				continue
+			}
+			s.publish(ctx, c, &envelope.Event)
+		case *nostr.ReqEnvelope:
+			if hang_up_on_req {
+				return
+			}
+			if ignore_reqs {
+				continue
+			}
+			if require_auth && !c.isAuthed() {
+				c.send(ctx, nostr.ClosedEnvelope{SubscriptionID: envelope.SubscriptionID, Reason: "auth-required: authenticate to subscribe"})
+				continue
+			}
+			s.subscribe(ctx, c, envelope.SubscriptionID, envelope.Filters)
+		case *nostr.AuthEnvelope:
+			c.authenticate(ctx, &envelope.Event)
+		case *nostr.CloseEnvelope:
+			c.mu.Lock()
+			delete(c.subs, string(*envelope))
+			c.mu.Unlock()
+		}
+	}
+}
+
+// publish stores a signed event, acknowledges it and forwards it to matching subscriptions.
+func (s *Server) publish(ctx context.Context, c *conn, event *nostr.Event) {
+	if ok, _ := event.CheckSignature(); !ok || event.GetID() != event.ID {
+		c.send(ctx, nostr.OKEnvelope{EventID: event.ID, OK: false, Reason: "invalid: bad signature"})
+		return
+	}
+
+	s.mu.Lock()
+	if s.reject != nil {
+		if reason := s.reject(event); reason != "" {
+			s.mu.Unlock()
+			c.send(ctx, nostr.OKEnvelope{EventID: event.ID, OK: false, Reason: reason})
+			return
+		}
+	}
+	for _, stored := range s.events {
+		if stored.ID == event.ID {
+			s.mu.Unlock()
+			c.send(ctx, nostr.OKEnvelope{EventID: event.ID, OK: true, Reason: "duplicate: already have this event"})
+			return
+		}
+	}
+	s.events = append(s.events, event)
+	conns := make([]*conn, 0, len(s.conns))
+	for other := range s.conns {
+		conns = append(conns, other)
+	}
+	s.mu.Unlock()
+
+	c.send(ctx, nostr.OKEnvelope{EventID: event.ID, OK: true})
+
+	for _, other := range conns {
+		for _, id := range other.matching(event) {
+			sub_id := id
+			other.send(ctx, nostr.EventEnvelope{SubscriptionID: &sub_id, Event: *event})
+		}
+	}
+}
+
+// subscribe opens a subscription, replaying stored matches before EOSE.
+func (s *Server) subscribe(ctx context.Context, c *conn, sub_id string, filters nostr.Filters) {
+	c.mu.Lock()
+	c.subs[sub_id] = filters
+	c.mu.Unlock()
+
+	for _, event := range s.Events() {
+		if filters.Match(event) {
+			c.send(ctx, nostr.EventEnvelope{SubscriptionID: &sub_id, Event: *event})
+		}
+	}
+	eose := nostr.EOSEEnvelope(sub_id)
+	c.send(ctx, &eose)
+}
+
+// authenticate checks a NIP-42 AUTH event against the connection's challenge.
+func (c *conn) authenticate(ctx context.Context, event *nostr.Event) {
+	ok, _ := event.CheckSignature()
+	challenge := event.Tags.Find("challenge")
+	if !ok || event.GetID() != event.ID || event.Kind != nostr.KindClientAuthentication ||
+		c.challenge == "" || challenge == nil || challenge[1] != c.challenge {
+		c.send(ctx, nostr.OKEnvelope{EventID: event.ID, OK: false, Reason: "invalid: bad auth event"})
+		return
+	}
+
+	c.mu.Lock()
+	c.authed = event.PubKey
+	c.mu.Unlock()
+	c.send(ctx, nostr.OKEnvelope{EventID: event.ID, OK: true})
+}
+
+// isAuthed reports whether the client has authenticated.
+func (c *conn) isAuthed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.authed != ""
+}
+
+// matching returns the ids of the connection's subscriptions matching the event.
+func (c *conn) matching(event *nostr.Event) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var ids []string
+	for id, filters := range c.subs {
+		if filters.Match(event) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// send writes an envelope to the client, dropping it if the connection is gone.
+func (c *conn) send(ctx context.Context, envelope json.Marshaler) {
+	data, err := envelope.MarshalJSON()
+	if err != nil {
+		return
+	}
+	c.ws.Write(ctx, websocket.MessageText, data)
+}
+
+// randomChallenge returns a fresh NIP-42 challenge.
+func randomChallenge() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
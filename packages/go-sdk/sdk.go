@@ -20,43 +20,56 @@
 package sdk
 
 import (
-	"encoding/hex"
+	"context"
 	"time"
 
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/joinnextblock/attn-protocol/go-sdk/nip19"
 	"github.com/nbd-wtf/go-nostr"
 )
 
 // SdkConfig holds configuration for the SDK.
 type SdkConfig struct {
-	// PrivateKey is the hex-encoded private key for signing events.
+	// Signer signs events, e.g. a nip46.RemoteSigner that keeps the key off this host.
+	// Takes precedence over PrivateKey.
+	Signer core.Signer
+
+	// PrivateKey is the hex or nsec private key for signing events when no Signer is set.
 	PrivateKey string
 }
 
 // Sdk provides methods for creating and publishing ATTN Protocol events.
 type Sdk struct {
-	config     SdkConfig
-	privateKey string
-	publicKey  string
+	config    SdkConfig
+	signer    core.Signer
+	publicKey string
 }
 
 // NewSdk creates a new SDK instance.
 func NewSdk(config SdkConfig) (*Sdk, error) {
-	// Decode private key
-	sk_bytes, err := hex.DecodeString(config.PrivateKey)
-	if err != nil {
-		return nil, err
+	signer := config.Signer
+	if signer == nil {
+		private_key, err := nip19.DecodePrivateKey(config.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		key_signer, err := core.NewKeySigner(private_key)
+		if err != nil {
+			return nil, err
+		}
+		signer = key_signer
 	}
 
 	// Get public key
-	pk, err := nostr.GetPublicKey(hex.EncodeToString(sk_bytes))
+	pk, err := signer.GetPublicKey(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
 	return &Sdk{
-		config:     config,
-		privateKey: config.PrivateKey,
-		publicKey:  pk,
+		config:    config,
+		signer:    signer,
+		publicKey: pk,
 	}, nil
 }
 
@@ -65,9 +78,14 @@ func (s *Sdk) GetPublicKey() string {
 	return s.publicKey
 }
 
-// signEvent signs an event with the SDK's private key.
-func (s *Sdk) signEvent(event *nostr.Event) error {
-	return event.Sign(s.privateKey)
+// Signer returns the signer the SDK signs events with.
+func (s *Sdk) Signer() core.Signer {
+	return s.signer
+}
+
+// signEvent signs an event with the SDK's signer.
+func (s *Sdk) signEvent(ctx context.Context, event *nostr.Event) error {
+	return s.signer.SignEvent(ctx, event)
 }
 
 // createBaseEvent creates a base event with common fields.
@@ -60,6 +60,27 @@ func main() {
 			Msg("Unsupported storage type - implement custom storage or use 'sqlite'")
 	}
 
+	// Resolve match and confirmation references against storage when enabled
+	var resolver validation.Resolver
+	if cfg.ReferentialValidation {
+		if storage_resolver, ok := eventStorage.(validation.Resolver); ok {
+			resolver = storage_resolver
+			logger.Info().Msg("Referential validation enabled")
+		} else {
+			logger.Warn().
+				Str("storage_type", cfg.StorageType).
+				Msg("REFERENTIAL_VALIDATION set but storage does not implement validation.Resolver - skipping")
+		}
+	}
+
+	// Validation profile applied to ATTN Protocol events
+	validationOptions, err := validation.ParseProfile(cfg.ValidationProfile)
+	if err != nil {
+		logger.Fatal().
+			Err(err).
+			Msg("Invalid VALIDATION_PROFILE - use 'default', 'strict' or 'lenient'")
+	}
+
 	// Initialize plugins
 	authHooks := getAuthPlugin(cfg.AuthPlugin)
 	attnHooks := &plugin.NoATTNHooks{} // Default no-op hooks
@@ -110,7 +131,7 @@ func main() {
 		Msg("Relay initialized")
 
 	// Set up hooks
-	setupHooks(relay, eventStorage, authHooks, attnHooks, rateLimiter)
+	setupHooks(ctx, relay, eventStorage, resolver, validationOptions, authHooks, attnHooks, rateLimiter)
 
 	// Start the relay server
 	address := fmt.Sprintf(":%d", cfg.RelayPort)
@@ -155,10 +176,14 @@ func getAuthPlugin(pluginName string) plugin.AuthHooks {
 	}
 }
 
-// setupHooks configures all relay hooks using the plugin system.
+// setupHooks configures all relay hooks using the plugin system. ctx is the relay's
+// lifetime; reference lookups made while validating are bounded by it.
 func setupHooks(
+	ctx context.Context,
 	relay *rely.Relay,
 	eventStorage storage.Storage,
+	resolver validation.Resolver,
+	validationOptions validation.Options,
 	authHooks plugin.AuthHooks,
 	attnHooks plugin.ATTNHooks,
 	rateLimiter *ratelimit.RateLimiter,
@@ -226,16 +251,27 @@ func setupHooks(
 			}
 		}
 
-		// Validate event (shared validation - not plugin-based)
-		validationResult := validation.ValidateEvent(event)
+		// Validate event (shared validation - not plugin-based). The resolver path runs
+		// the same checks first, so only one of the two runs.
+		var validationResult validation.ValidationResult
+		if resolver != nil {
+			resolve_ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+			defer cancel()
+			validationResult = validation.ValidateEventWithResolver(resolve_ctx, event, resolver, validationOptions)
+		} else {
+			validationResult = validation.ValidateEventWithOptions(event, validationOptions)
+		}
 		if !validationResult.Valid {
 			logger.Warn().
 				Str("event_id", event.ID).
 				Str("pubkey", event.PubKey).
 				Int("kind", event.Kind).
+				Str("code", string(validationResult.Code)).
+				Str("field", validationResult.Field).
 				Str("reason", validationResult.Message).
 				Msg("Event REJECTED - validation failed")
-			return fmt.Errorf("validation failed: %s", validationResult.Message)
+			// Reason() is NIP-01 machine-readable: "invalid: <code>: <message>"
+			return errors.New(validationResult.Reason())
 		}
 
 		return nil
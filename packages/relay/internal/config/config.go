@@ -27,6 +27,10 @@ type Config struct {
 	StorageType string // "sqlite" or custom implementations
 	SQLiteDBPath string // Path to SQLite database file
 
+	// Validation Configuration
+	ReferentialValidation bool // Resolve match and confirmation references against storage before accepting
+	ValidationProfile     string // "default", "strict" or "lenient"
+
 	// Plugin Configuration
 	AuthPlugin string // "none" or custom plugin name
 
@@ -48,6 +52,8 @@ type Config struct {
 //   - RELAY_DOMAIN
 //   - STORAGE_TYPE (default: "sqlite")
 //   - SQLITE_DB_PATH (default: "./relay.db")
+//   - REFERENTIAL_VALIDATION (default: false)
+//   - VALIDATION_PROFILE (default: "default")
 //   - AUTH_PLUGIN (default: "none")
 //   - LOG_LEVEL (default: INFO)
 //   - RATE_LIMITER_CLEANUP_INTERVAL, RATE_LIMIT_WINDOW
@@ -69,6 +75,8 @@ func Load() (*Config, error) {
 		RelayDomain:      getEnv("RELAY_DOMAIN", ""),
 		StorageType:      getEnv("STORAGE_TYPE", "sqlite"),
 		SQLiteDBPath:     getEnv("SQLITE_DB_PATH", "./relay.db"),
+		ReferentialValidation: getEnvAsBool("REFERENTIAL_VALIDATION", false),
+		ValidationProfile: getEnv("VALIDATION_PROFILE", "default"),
 		AuthPlugin:       getEnv("AUTH_PLUGIN", "none"),
 		LogLevel:          getEnv("LOG_LEVEL", "INFO"),
 		RateLimiterCleanupInterval: getEnvAsDuration("RATE_LIMITER_CLEANUP_INTERVAL", 5*time.Minute),
@@ -110,6 +118,18 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return intValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return boolValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	value := os.Getenv(key)
 	if value == "" {
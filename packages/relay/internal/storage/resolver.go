@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// GetEventByID returns the stored event with the given ID, or nil if it is not stored.
+// Together with GetEventByCoordinate it implements validation.Resolver.
+func (s *SQLiteStorage) GetEventByID(ctx context.Context, id string) (*nostr.Event, error) {
+	events, err := s.QueryEvents(ctx, &nostr.Filter{IDs: []string{id}, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	return events[0], nil
+}
+
+// GetEventByCoordinate returns the latest stored event at the given coordinate, or nil if none is stored.
+// The d tag is matched in SQL, so an author's other events of the kind never crowd it out.
+func (s *SQLiteStorage) GetEventByCoordinate(ctx context.Context, coordinate core.Coordinate) (*nostr.Event, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, pubkey, created_at, kind, content, tags, sig FROM events
+		WHERE kind = ? AND pubkey = ? AND d_tag = ?
+		ORDER BY created_at DESC, id ASC
+		LIMIT 1
+	`, coordinate.Kind, coordinate.Pubkey, coordinate.DTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	return events[0], nil
+}
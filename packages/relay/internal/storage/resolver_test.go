@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joinnextblock/attn-protocol/go-core"
+	"github.com/nbd-wtf/go-nostr"
+)
+
+// TestGetEventByCoordinateBeyondDefaultLimit checks an event is resolved by its
+// coordinate even when its author has more than 500 newer events of the same kind.
+func TestGetEventByCoordinateBeyondDefaultLimit(t *testing.T) {
+	storage, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "relay.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+
+	ctx := context.Background()
+	pubkey := strings.Repeat("ab", 32)
+	store := func(d_tag string, created_at nostr.Timestamp) *nostr.Event {
+		event := &nostr.Event{
+			PubKey:    pubkey,
+			CreatedAt: created_at,
+			Kind:      core.KindMatch,
+			Tags:      nostr.Tags{{"d", d_tag}},
+			Content:   "{}",
+		}
+		event.ID = event.GetID()
+		if err := storage.StoreEvent(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+		return event
+	}
+
+	target := store("org.attnprotocol:match:first", 1700000000)
+	for i := 0; i < 600; i++ {
+		store(fmt.Sprintf("org.attnprotocol:match:m%d", i), nostr.Timestamp(1700000001+i))
+	}
+
+	got, err := storage.GetEventByCoordinate(ctx, core.Coordinate{Kind: core.KindMatch, Pubkey: pubkey, DTag: "org.attnprotocol:match:first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.ID != target.ID {
+		t.Fatalf("expected the first match to resolve, got %v", got)
+	}
+
+	got, err = storage.GetEventByCoordinate(ctx, core.Coordinate{Kind: core.KindMatch, Pubkey: pubkey, DTag: "org.attnprotocol:match:missing"})
+	if err != nil || got != nil {
+		t.Errorf("expected nil for a missing coordinate, got %v (%v)", got, err)
+	}
+}
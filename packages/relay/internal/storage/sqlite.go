@@ -92,7 +92,8 @@ func (s *SQLiteStorage) initSchema() error {
 		kind INTEGER NOT NULL,
 		content TEXT NOT NULL,
 		tags TEXT NOT NULL,
-		sig TEXT NOT NULL
+		sig TEXT NOT NULL,
+		d_tag TEXT NOT NULL DEFAULT ''
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_pubkey ON events(pubkey);
@@ -102,10 +103,48 @@ func (s *SQLiteStorage) initSchema() error {
 	CREATE INDEX IF NOT EXISTS idx_kind_created_at ON events(kind, created_at);
 	`
 
-	_, err := s.db.Exec(createTableSQL)
+	if _, err := s.db.Exec(createTableSQL); err != nil {
+		return err
+	}
+
+	if err := s.migrateDTag(); err != nil {
+		return err
+	}
+
+	// Coordinate lookups (kind, pubkey, d tag) for referential validation
+	_, err := s.db.Exec("CREATE INDEX IF NOT EXISTS idx_kind_pubkey_d_tag ON events(kind, pubkey, d_tag, created_at)")
 	return err
 }
 
+// migrateDTag adds the d_tag column to databases created before it existed and
+// fills it from the stored tags.
+func (s *SQLiteStorage) migrateDTag() error {
+	rows, err := s.db.Query("SELECT name FROM pragma_table_info('events') WHERE name = 'd_tag'")
+	if err != nil {
+		return err
+	}
+	exists := rows.Next()
+	rows.Close()
+	if exists {
+		return nil
+	}
+
+	if _, err := s.db.Exec("ALTER TABLE events ADD COLUMN d_tag TEXT NOT NULL DEFAULT ''"); err != nil {
+		return fmt.Errorf("failed to add d_tag column: %w", err)
+	}
+	_, err = s.db.Exec(`
+		UPDATE events SET d_tag = COALESCE((
+			SELECT json_extract(value, '$[1]') FROM json_each(events.tags)
+			WHERE json_extract(value, '$[0]') = 'd' AND json_array_length(value) >= 2
+			LIMIT 1
+		), '')
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to fill d_tag column: %w", err)
+	}
+	return nil
+}
+
 // StoreEvent stores a Nostr event in SQLite.
 // Implements retry logic for SQLITE_BUSY errors to handle concurrent writes.
 func (s *SQLiteStorage) StoreEvent(ctx context.Context, event *nostr.Event) error {
@@ -117,8 +156,8 @@ func (s *SQLiteStorage) StoreEvent(ctx context.Context, event *nostr.Event) erro
 
 	// Use INSERT OR REPLACE to handle replaceable events
 	query := `
-		INSERT OR REPLACE INTO events (id, pubkey, created_at, kind, content, tags, sig)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT OR REPLACE INTO events (id, pubkey, created_at, kind, content, tags, sig, d_tag)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	// Retry logic for SQLITE_BUSY errors
@@ -136,6 +175,7 @@ func (s *SQLiteStorage) StoreEvent(ctx context.Context, event *nostr.Event) erro
 			event.Content,
 			string(tagsJSON),
 			event.Sig,
+			event.Tags.GetD(),
 		)
 
 		if err == nil {
@@ -238,6 +278,11 @@ func (s *SQLiteStorage) QueryEvents(ctx context.Context, filter *nostr.Filter) (
 	}
 	defer rows.Close()
 
+	return scanEvents(rows)
+}
+
+// scanEvents reads events from rows selecting id, pubkey, created_at, kind, content, tags and sig.
+func scanEvents(rows *sql.Rows) ([]*nostr.Event, error) {
 	var events []*nostr.Event
 	for rows.Next() {
 		var id, pubkey, content, tagsJSON, sig string
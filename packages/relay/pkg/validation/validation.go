@@ -9,17 +9,35 @@
 package validation
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/nbd-wtf/go-nostr"
 
+	"github.com/joinnextblock/attn-protocol/go-core"
 	attn_validation "github.com/joinnextblock/attn-protocol/go-core/validation"
 	city_validation "github.com/joinnextblock/city-protocol/relay/pkg/validation"
 )
 
 // ValidationResult represents the result of event validation.
-// Re-exported from city-protocol/relay/pkg/validation.
-type ValidationResult = city_validation.ValidationResult
+// Re-exported from attn-protocol/go-core/validation so failures carry a stable Code.
+type ValidationResult = attn_validation.ValidationResult
+
+// ErrorCode is a stable, machine-readable validation failure reason.
+// Re-exported from attn-protocol/go-core/validation.
+type ErrorCode = attn_validation.ErrorCode
+
+// CodeCityProtocol marks failures reported by City Protocol validation,
+// which does not classify its own rejections.
+const CodeCityProtocol ErrorCode = "city_protocol"
+
+// fromCityResult converts a City Protocol validation result.
+func fromCityResult(result city_validation.ValidationResult) ValidationResult {
+	if result.Valid {
+		return ValidationResult{Valid: true, Message: result.Message}
+	}
+	return ValidationResult{Valid: false, Code: CodeCityProtocol, Message: result.Message}
+}
 
 // AllowedEventKinds defines the set of event kinds accepted by the ATTN Protocol relay.
 // This extends City Protocol's allowed kinds with ATTN Protocol-specific kinds.
@@ -71,91 +89,121 @@ func IsCityProtocolKind(kind int) bool {
 //
 // City Protocol kinds (388X8 pattern) and supporting Nostr kinds are delegated to City Protocol.
 func ValidateEvent(event *nostr.Event) ValidationResult {
+	return ValidateEventWithOptions(event, attn_validation.DefaultOptions())
+}
+
+// Options selects the validation profile applied to ATTN Protocol events.
+// Re-exported from attn-protocol/go-core/validation.
+type Options = attn_validation.Options
+
+// ParseProfile returns the options for a named profile: "default", "strict" or "lenient".
+// Re-exported from attn-protocol/go-core/validation.
+func ParseProfile(name string) (Options, error) {
+	return attn_validation.ParseProfile(name)
+}
+
+// ValidateEventWithOptions validates an event like ValidateEvent, applying the
+// validation profile to ATTN Protocol kinds.
+func ValidateEventWithOptions(event *nostr.Event, opts Options) ValidationResult {
 	// First, check if event kind is allowed
 	if !AllowedEventKinds[event.Kind] {
 		return ValidationResult{
 			Valid:   false,
+			Code:    attn_validation.CodeUnsupportedKind,
 			Message: fmt.Sprintf("Event kind %d is not supported by this relay. Only ATTN Protocol kinds (38188-38988), City Protocol kinds (388X8), and supporting Nostr kinds are accepted.", event.Kind),
 		}
 	}
 
 	// Route ATTN Protocol events to ATTN validators
 	if attn_validation.IsATTNProtocolKind(event.Kind) {
-		result := attn_validation.ValidateATTNEvent(event)
-		return ValidationResult{Valid: result.Valid, Message: result.Message}
+		return attn_validation.ValidateATTNEventWithOptions(event, opts)
+	}
+
+	// Block events must also carry a block reference ATTN events can rely on
+	if event.Kind == core.KindCityBlock {
+		return ValidateBlockEvent(event)
 	}
 
 	// Delegate City Protocol events and supporting Nostr kinds to City Protocol validation
-	return city_validation.ValidateEvent(event)
+	return fromCityResult(city_validation.ValidateEvent(event))
+}
+
+// Resolver fetches events referenced by other events.
+// Re-exported from attn-protocol/go-core/validation.
+type Resolver = attn_validation.Resolver
+
+// ValidateEventWithResolver validates an event like ValidateEventWithOptions and, for ATTN
+// Protocol match and confirmation events, checks the referenced chain against the resolver.
+func ValidateEventWithResolver(ctx context.Context, event *nostr.Event, resolver Resolver, opts Options) ValidationResult {
+	if !attn_validation.IsATTNProtocolKind(event.Kind) {
+		return ValidateEventWithOptions(event, opts)
+	}
+	return attn_validation.ValidateWithResolverOptions(ctx, event, resolver, opts)
 }
 
 // ValidateMarketplaceEvent validates Marketplace events (kind 38188).
 // Re-exported from attn-protocol/go-core/validation.
 func ValidateMarketplaceEvent(event *nostr.Event) ValidationResult {
-	result := attn_validation.ValidateMarketplaceEvent(event)
-	return ValidationResult{Valid: result.Valid, Message: result.Message}
+	return attn_validation.ValidateMarketplaceEvent(event)
 }
 
 // ValidateBillboardEvent validates Billboard events (kind 38288).
 // Re-exported from attn-protocol/go-core/validation.
 func ValidateBillboardEvent(event *nostr.Event) ValidationResult {
-	result := attn_validation.ValidateBillboardEvent(event)
-	return ValidationResult{Valid: result.Valid, Message: result.Message}
+	return attn_validation.ValidateBillboardEvent(event)
 }
 
 // ValidatePromotionEvent validates Promotion events (kind 38388).
 // Re-exported from attn-protocol/go-core/validation.
 func ValidatePromotionEvent(event *nostr.Event) ValidationResult {
-	result := attn_validation.ValidatePromotionEvent(event)
-	return ValidationResult{Valid: result.Valid, Message: result.Message}
+	return attn_validation.ValidatePromotionEvent(event)
 }
 
 // ValidateAttentionEvent validates Attention events (kind 38488).
 // Re-exported from attn-protocol/go-core/validation.
 func ValidateAttentionEvent(event *nostr.Event) ValidationResult {
-	result := attn_validation.ValidateAttentionEvent(event)
-	return ValidationResult{Valid: result.Valid, Message: result.Message}
+	return attn_validation.ValidateAttentionEvent(event)
 }
 
 // ValidateMatchEvent validates Match events (kind 38888).
 // Re-exported from attn-protocol/go-core/validation.
 func ValidateMatchEvent(event *nostr.Event) ValidationResult {
-	result := attn_validation.ValidateMatchEvent(event)
-	return ValidationResult{Valid: result.Valid, Message: result.Message}
+	return attn_validation.ValidateMatchEvent(event)
 }
 
 // ValidateBillboardConfirmationEvent validates Billboard Confirmation events (kind 38588).
 // Re-exported from attn-protocol/go-core/validation.
 func ValidateBillboardConfirmationEvent(event *nostr.Event) ValidationResult {
-	result := attn_validation.ValidateBillboardConfirmationEvent(event)
-	return ValidationResult{Valid: result.Valid, Message: result.Message}
+	return attn_validation.ValidateBillboardConfirmationEvent(event)
 }
 
 // ValidateAttentionConfirmationEvent validates Attention Confirmation events (kind 38688).
 // Re-exported from attn-protocol/go-core/validation.
 func ValidateAttentionConfirmationEvent(event *nostr.Event) ValidationResult {
-	result := attn_validation.ValidateAttentionConfirmationEvent(event)
-	return ValidationResult{Valid: result.Valid, Message: result.Message}
+	return attn_validation.ValidateAttentionConfirmationEvent(event)
 }
 
 // ValidateMarketplaceConfirmationEvent validates Marketplace Confirmation events (kind 38788).
 // Re-exported from attn-protocol/go-core/validation.
 func ValidateMarketplaceConfirmationEvent(event *nostr.Event) ValidationResult {
-	result := attn_validation.ValidateMarketplaceConfirmationEvent(event)
-	return ValidationResult{Valid: result.Valid, Message: result.Message}
+	return attn_validation.ValidateMarketplaceConfirmationEvent(event)
 }
 
 // ValidateAttentionPaymentConfirmationEvent validates Attention Payment Confirmation events (kind 38988).
 // Re-exported from attn-protocol/go-core/validation.
 func ValidateAttentionPaymentConfirmationEvent(event *nostr.Event) ValidationResult {
-	result := attn_validation.ValidateAttentionPaymentConfirmationEvent(event)
-	return ValidationResult{Valid: result.Valid, Message: result.Message}
+	return attn_validation.ValidateAttentionPaymentConfirmationEvent(event)
 }
 
 // ValidateBlockEvent validates City Protocol Block events (kind 38808).
-// Delegated to city-protocol/relay/pkg/validation.
+// Delegated to city-protocol/relay/pkg/validation, then checked against
+// attn-protocol/go-core/validation so the d tag height and hash agree with content.
 func ValidateBlockEvent(event *nostr.Event) ValidationResult {
-	return city_validation.ValidateBlockEvent(event)
+	if result := city_validation.ValidateBlockEvent(event); !result.Valid {
+		return fromCityResult(result)
+	}
+
+	return attn_validation.ValidateCityBlockEvent(event)
 }
 
 // ValidateCityBlockEvent is an alias for ValidateBlockEvent.
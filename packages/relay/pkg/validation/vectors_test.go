@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nbd-wtf/go-nostr"
+
+	attn_validation "github.com/joinnextblock/attn-protocol/go-core/validation"
+)
+
+// vectorsDir holds the golden corpus shared by every implementation.
+const vectorsDir = "../../../protocol/test-vectors"
+
+type vectorFile struct {
+	Kind    int `json:"kind"`
+	Vectors []struct {
+		Name  string       `json:"name"`
+		Valid bool         `json:"valid"`
+		Code  ErrorCode    `json:"code"`
+		Field string       `json:"field"`
+		Event *nostr.Event `json:"event"`
+	} `json:"vectors"`
+}
+
+func TestValidateEvent_Vectors(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(vectorsDir, "*.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no test vectors found in %s: %v", vectorsDir, err)
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var file vectorFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+
+		for _, vector := range file.Vectors {
+			// The relay framework verifies ids and signatures before validation runs
+			if vector.Code == attn_validation.CodeBadID || vector.Code == attn_validation.CodeBadSignature {
+				continue
+			}
+
+			t.Run(filepath.Base(path)+"/"+vector.Name, func(t *testing.T) {
+				result := ValidateEvent(vector.Event)
+				if result.Valid != vector.Valid {
+					t.Fatalf("expected valid=%t, got valid=%t: %s", vector.Valid, result.Valid, result.Message)
+				}
+
+				// City Protocol may reject a block first without a classified code
+				if file.Kind == 38808 {
+					return
+				}
+				if result.Code != vector.Code || result.Field != vector.Field {
+					t.Errorf("expected code=%q field=%q, got code=%q field=%q: %s", vector.Code, vector.Field, result.Code, result.Field, result.Message)
+				}
+			})
+		}
+	}
+}